@@ -2,8 +2,10 @@
 package goli
 
 import (
+	"io"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 const (
@@ -27,12 +29,51 @@ const (
 	hyperlinkEnd = "\x1b]8;;\x1b\\"
 )
 
+// moveCursorCacheSize bounds the positions whose MoveCursor sequences are
+// precomputed: 0 <= x, y < moveCursorCacheSize.
+const moveCursorCacheSize = 256
+
+var (
+	moveCursorOnce sync.Once
+	// moveCursorText holds every cached sequence back to back, row by row;
+	// moveCursorOffsets[y*moveCursorCacheSize+x] is where (x, y) starts.
+	// Slicing one string avoids an allocation per sequence.
+	moveCursorText    string
+	moveCursorOffsets [moveCursorCacheSize*moveCursorCacheSize + 1]uint32
+)
+
 // MoveCursor returns the ANSI code to move the cursor to (x, y).
 // ANSI uses 1-based coordinates.
 func MoveCursor(x, y int) string {
+	if uint(x) < moveCursorCacheSize && uint(y) < moveCursorCacheSize {
+		moveCursorOnce.Do(buildMoveCursorTable)
+		i := y*moveCursorCacheSize + x
+		return moveCursorText[moveCursorOffsets[i]:moveCursorOffsets[i+1]]
+	}
+	return moveCursor(x, y)
+}
+
+func moveCursor(x, y int) string {
 	return csiStr + strconv.Itoa(y+1) + ";" + strconv.Itoa(x+1) + "H"
 }
 
+func buildMoveCursorTable() {
+	var sb strings.Builder
+	sb.Grow(moveCursorCacheSize * moveCursorCacheSize * len("\x1b[256;256H"))
+	for y := 0; y < moveCursorCacheSize; y++ {
+		for x := 0; x < moveCursorCacheSize; x++ {
+			moveCursorOffsets[y*moveCursorCacheSize+x] = uint32(sb.Len())
+			sb.WriteString(csiStr)
+			sb.WriteString(strconv.Itoa(y + 1))
+			sb.WriteByte(';')
+			sb.WriteString(strconv.Itoa(x + 1))
+			sb.WriteByte('H')
+		}
+	}
+	moveCursorOffsets[len(moveCursorOffsets)-1] = uint32(sb.Len())
+	moveCursorText = sb.String()
+}
+
 // HideCursor returns the ANSI code to hide the cursor.
 func HideCursor() string {
 	return CSI + "?25l"
@@ -48,6 +89,19 @@ func ClearScreen() string {
 	return CSI + "2J" + CSI + "H"
 }
 
+// SetTerminalTitle writes the OSC 0 sequence that sets the terminal window
+// title. Control characters in title are dropped so they can't end the
+// sequence early. An empty title resets it to the terminal's default.
+func SetTerminalTitle(w io.Writer, title string) {
+	title = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, title)
+	io.WriteString(w, OSC+"0;"+title+ST)
+}
+
 // Foreground color ANSI codes indexed by Color
 var fgCodes = [...]string{
 	ColorNone:          "",
@@ -241,10 +295,80 @@ func RunsToAnsiBuilder(runs []CellRun, sb *strings.Builder) {
 	sb.WriteString(resetStr)
 }
 
+// RunsToAnsiCompressed renders all runs like RunsToAnsiBuilder, but paints
+// long runs of identical blank cells (e.g. background fills) with an erase
+// and a cursor advance instead of writing every space. The erase is only
+// drawn in the background color on terminals with background color erase
+// (BCE); see Options.CompressBlankRuns.
+func RunsToAnsiCompressed(runs []CellRun, sb *strings.Builder) {
+	for _, run := range runs {
+		runToAnsiCompressed(run, sb)
+	}
+	sb.WriteString(resetStr)
+}
+
+// runsToAnsiBuilder renders runs with RunsToAnsiCompressed if compress is
+// set, or RunsToAnsiBuilder otherwise.
+func runsToAnsiBuilder(runs []CellRun, sb *strings.Builder, compress bool) {
+	if compress {
+		RunsToAnsiCompressed(runs, sb)
+	} else {
+		RunsToAnsiBuilder(runs, sb)
+	}
+}
+
+// runToAnsiCompressed renders a run like RunToAnsi, compressing blank runs.
+func runToAnsiCompressed(run CellRun, sb *strings.Builder) {
+	sb.WriteString(MoveCursor(run.X, run.Y))
+
+	var currentStyle *Style
+	currentHyperlink := ""
+
+	for i := 0; i < len(run.Cells); i++ {
+		c := run.Cells[i]
+		styleChanged := currentStyle == nil || !currentStyle.Equal(c.Style)
+		hyperlinkChanged := c.Style.HyperlinkURL != currentHyperlink
+
+		if styleChanged {
+			if currentHyperlink != "" {
+				sb.WriteString(hyperlinkEnd)
+			}
+			sb.WriteString(resetStr)
+			StyleToAnsi(c.Style, sb)
+			if c.Style.HyperlinkURL != "" {
+				sb.WriteString(HyperlinkStart(c.Style.HyperlinkURL))
+			}
+			currentHyperlink = c.Style.HyperlinkURL
+			styleCopy := c.Style
+			currentStyle = &styleCopy
+		} else if hyperlinkChanged {
+			if currentHyperlink != "" {
+				sb.WriteString(hyperlinkEnd)
+			}
+			if c.Style.HyperlinkURL != "" {
+				sb.WriteString(HyperlinkStart(c.Style.HyperlinkURL))
+			}
+			currentHyperlink = c.Style.HyperlinkURL
+		}
+
+		if n := blankRunLength(run.Cells, i); n > 0 {
+			writeBlankRun(n, sb)
+			i += n - 1
+			continue
+		}
+		sb.WriteRune(c.Char)
+	}
+
+	if currentHyperlink != "" {
+		sb.WriteString(hyperlinkEnd)
+	}
+}
+
 // bufferToAnsiLines renders a CellBuffer to ANSI output suitable for printing.
 // Unlike BufferToSequentialAnsi, it uses no cursor positioning and \n line separators.
 // Only outputs rows 0..maxRow (inclusive).
-func bufferToAnsiLines(buf *CellBuffer, maxRow int) string {
+// If trimTrailing is true, unstyled trailing spaces are dropped from each row.
+func bufferToAnsiLines(buf *CellBuffer, maxRow int, trimTrailing bool) string {
 	var sb strings.Builder
 	sb.Grow(buf.Width() * (maxRow + 1) * 15)
 
@@ -264,7 +388,12 @@ func bufferToAnsiLines(buf *CellBuffer, maxRow int) string {
 			sb.WriteByte('\n')
 		}
 
-		for x := 0; x < buf.Width(); x++ {
+		end := buf.Width()
+		if trimTrailing {
+			end = rowContentEnd(buf, y, false)
+		}
+
+		for x := 0; x < end; x++ {
 			c := buf.Get(x, y)
 
 			styleChanged := currentStyle == nil || !currentStyle.Equal(c.Style)
@@ -308,6 +437,12 @@ func bufferToAnsiLines(buf *CellBuffer, maxRow int) string {
 // This is used for overflow content where ANSI cursor positioning doesn't work.
 // Outputs from cursor position (0,0) downward, using newlines to advance rows.
 func BufferToSequentialAnsi(buf *CellBuffer) string {
+	return bufferToSequentialAnsi(buf, false)
+}
+
+// bufferToSequentialAnsi implements BufferToSequentialAnsi, compressing
+// blank runs like RunsToAnsiCompressed if compress is set.
+func bufferToSequentialAnsi(buf *CellBuffer, compress bool) string {
 	var sb strings.Builder
 	// Estimate ~15 bytes per cell
 	sb.Grow(buf.Width() * buf.Height() * 15)
@@ -332,8 +467,9 @@ func BufferToSequentialAnsi(buf *CellBuffer) string {
 			sb.WriteString("\r\n")
 		}
 
-		for x := 0; x < buf.Width(); x++ {
-			c := buf.Get(x, y)
+		row := buf.cells[y*buf.width : (y+1)*buf.width]
+		for x := 0; x < len(row); x++ {
+			c := row[x]
 
 			styleChanged := currentStyle == nil || !currentStyle.Equal(c.Style)
 			hyperlinkChanged := c.Style.HyperlinkURL != currentHyperlink
@@ -364,6 +500,13 @@ func BufferToSequentialAnsi(buf *CellBuffer) string {
 				currentHyperlink = c.Style.HyperlinkURL
 			}
 
+			if compress {
+				if n := blankRunLength(row, x); n > 0 {
+					writeBlankRun(n, &sb)
+					x += n - 1
+					continue
+				}
+			}
 			sb.WriteRune(c.Char)
 		}
 	}
@@ -376,3 +519,36 @@ func BufferToSequentialAnsi(buf *CellBuffer) string {
 
 	return sb.String()
 }
+
+// blankRunLength returns the length of the run of identical blank cells
+// starting at cells[i], if painting it with writeBlankRun is shorter than
+// writing the spaces. Returns 0 otherwise. Only spaces whose style an erase
+// reproduces (background color alone) qualify.
+func blankRunLength(cells []Cell, i int) int {
+	c := cells[i]
+	if c.Char != ' ' || c.Style.Underline || c.Style.Inverse ||
+		c.Style.Strikethrough || c.Style.HyperlinkURL != "" {
+		return 0
+	}
+	n := 1
+	for i+n < len(cells) && cells[i+n].Equal(c) {
+		n++
+	}
+	// ECH and CUF each cost CSI + digits + final byte
+	if cost := 2 * (len(csiStr) + len(strconv.Itoa(n)) + 1); cost >= n {
+		return 0
+	}
+	return n
+}
+
+// writeBlankRun paints n blanks with the current background using ECH
+// (which doesn't move the cursor), then advances past them with CUF.
+func writeBlankRun(n int, sb *strings.Builder) {
+	count := strconv.Itoa(n)
+	sb.WriteString(csiStr)
+	sb.WriteString(count)
+	sb.WriteByte('X')
+	sb.WriteString(csiStr)
+	sb.WriteString(count)
+	sb.WriteByte('C')
+}
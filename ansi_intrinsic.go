@@ -16,7 +16,7 @@ func init() {
 	})
 }
 
-func measureAnsi(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureAnsi(node gox.VNode, ctx LayoutContext) (int, int) {
 	text := CollectTextContent(node)
 	lines := strings.Split(text, "\n")
 	maxWidth := 0
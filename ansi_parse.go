@@ -4,9 +4,10 @@ import (
 	"strings"
 )
 
-// ContainsAnsi returns true if the string contains ANSI escape sequences.
+// ContainsAnsi returns true if the string contains ANSI escape sequences
+// (CSI or OSC).
 func ContainsAnsi(s string) bool {
-	return strings.Contains(s, "\x1b[")
+	return strings.Contains(s, "\x1b[") || strings.Contains(s, "\x1b]")
 }
 
 // StripAnsi removes ANSI escape sequences from a string,
@@ -28,6 +29,9 @@ func StripAnsi(s string) string {
 			if i < len(s) {
 				i++ // skip final byte
 			}
+		} else if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == ']' {
+			// OSC sequence: skip through the terminator
+			_, i = parseOSC(s, i)
 		} else if s[i] == '\x1b' {
 			// Other escape: skip ESC + next byte
 			i += 2
@@ -39,6 +43,58 @@ func StripAnsi(s string) string {
 	return b.String()
 }
 
+// StripOSC removes OSC (Operating System Command) sequences such as
+// OSC 8 hyperlinks from a string, leaving CSI sequences untouched.
+func StripOSC(s string) string {
+	if !strings.Contains(s, "\x1b]") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	i := 0
+	for i < len(s) {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == ']' {
+			_, i = parseOSC(s, i)
+		} else {
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// parseOSC parses an OSC sequence starting at s[start] (the ESC byte).
+// It returns the sequence payload (between "ESC ]" and the terminator) and
+// the index just past the terminator. Both ST (ESC \) and BEL terminators
+// are accepted; an unterminated sequence consumes the rest of the string.
+func parseOSC(s string, start int) (payload string, end int) {
+	i := start + 2 // skip ESC]
+	for i < len(s) {
+		if s[i] == '\a' {
+			return s[start+2 : i], i + 1
+		}
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+			return s[start+2 : i], i + 2
+		}
+		i++
+	}
+	return s[start+2:], len(s)
+}
+
+// applyOSC applies an OSC payload to a style. Only OSC 8 hyperlinks
+// ("8;params;url") are recognized; an empty URL closes the link.
+func applyOSC(payload string, style *Style) {
+	if !strings.HasPrefix(payload, "8;") {
+		return
+	}
+	rest := payload[2:]
+	semi := strings.IndexByte(rest, ';')
+	if semi < 0 {
+		return
+	}
+	style.HyperlinkURL = rest[semi+1:]
+}
+
 // AnsiSegment represents a piece of text with associated style from ANSI codes.
 type AnsiSegment struct {
 	Text  string
@@ -47,6 +103,7 @@ type AnsiSegment struct {
 
 // ParseAnsiLine parses a line containing ANSI escape codes into styled segments.
 // The baseStyle is the element's own style; ANSI styles are merged on top.
+// OSC 8 hyperlinks set HyperlinkURL on the enclosed segments.
 func ParseAnsiLine(line string, baseStyle Style) []AnsiSegment {
 	if !ContainsAnsi(line) {
 		return []AnsiSegment{{Text: line, Style: baseStyle}}
@@ -78,6 +135,15 @@ func ParseAnsiLine(line string, baseStyle Style) []AnsiSegment {
 				}
 				i++ // skip final byte
 			}
+		} else if line[i] == '\x1b' && i+1 < len(line) && line[i+1] == ']' {
+			// OSC sequence — hyperlinks start a new segment
+			if text.Len() > 0 {
+				segments = append(segments, AnsiSegment{Text: text.String(), Style: current})
+				text.Reset()
+			}
+			var payload string
+			payload, i = parseOSC(line, i)
+			applyOSC(payload, &current)
 		} else if line[i] == '\x1b' {
 			// Non-CSI escape: skip
 			i += 2
@@ -96,9 +162,12 @@ func ParseAnsiLine(line string, baseStyle Style) []AnsiSegment {
 
 // applySGR applies SGR (Select Graphic Rendition) parameters to a style.
 func applySGR(paramStr string, style *Style, baseStyle Style) {
+	// Hyperlinks are controlled by OSC 8, not SGR, so a reset keeps them.
+	url := style.HyperlinkURL
 	if paramStr == "" {
 		// ESC[m is equivalent to ESC[0m (reset)
 		*style = baseStyle
+		style.HyperlinkURL = url
 		return
 	}
 
@@ -109,6 +178,7 @@ func applySGR(paramStr string, style *Style, baseStyle Style) {
 		switch {
 		case p == 0:
 			*style = baseStyle
+			style.HyperlinkURL = url
 		case p == 1:
 			style.Bold = true
 		case p == 2:
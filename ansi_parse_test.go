@@ -96,6 +96,74 @@ func TestContainsAnsi(t *testing.T) {
 	}
 }
 
+func TestParseAnsiLine_OSC8InColoredText(t *testing.T) {
+	line := "\x1b[32msee \x1b]8;;https://example.com\x1b\\docs\x1b]8;;\x1b\\ here\x1b[0m"
+	segs := ParseAnsiLine(line, Style{})
+	if len(segs) != 3 {
+		t.Fatalf("got %d segments, want 3: %+v", len(segs), segs)
+	}
+	if segs[0].Text != "see " || segs[0].Style.HyperlinkURL != "" {
+		t.Errorf("seg[0] = %+v, want plain 'see '", segs[0])
+	}
+	if segs[1].Text != "docs" || segs[1].Style.HyperlinkURL != "https://example.com" {
+		t.Errorf("seg[1] = %+v, want linked 'docs'", segs[1])
+	}
+	if segs[2].Text != " here" || segs[2].Style.HyperlinkURL != "" {
+		t.Errorf("seg[2] = %+v, want plain ' here'", segs[2])
+	}
+	for i, seg := range segs {
+		if seg.Style.Color != ColorGreen {
+			t.Errorf("seg[%d].Color = %d, want ColorGreen", i, seg.Style.Color)
+		}
+	}
+}
+
+func TestParseAnsiLine_OSC8NestedSGR(t *testing.T) {
+	// BEL terminator, SGR changes inside the link, and a reset that must not
+	// close the link.
+	line := "\x1b]8;id=1;https://a.b\a\x1b[1mbold\x1b[0m plain\x1b]8;;\a after"
+	segs := ParseAnsiLine(line, Style{})
+	if len(segs) != 3 {
+		t.Fatalf("got %d segments, want 3: %+v", len(segs), segs)
+	}
+	if segs[0].Text != "bold" || !segs[0].Style.Bold || segs[0].Style.HyperlinkURL != "https://a.b" {
+		t.Errorf("seg[0] = %+v, want bold linked 'bold'", segs[0])
+	}
+	if segs[1].Text != " plain" || segs[1].Style.Bold || segs[1].Style.HyperlinkURL != "https://a.b" {
+		t.Errorf("seg[1] = %+v, want unbolded linked ' plain'", segs[1])
+	}
+	if segs[2].Text != " after" || segs[2].Style.HyperlinkURL != "" {
+		t.Errorf("seg[2] = %+v, want unlinked ' after'", segs[2])
+	}
+}
+
+func TestStripOSC(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello", "hello"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1b]8;;https://example.com\alink\x1b]8;;\a", "link"},
+		{"\x1b[32m\x1b]8;;u\x1b\\x\x1b]8;;\x1b\\\x1b[0m", "\x1b[32mx\x1b[0m"},
+		{"\x1b]0;title", ""},
+	}
+
+	for _, tt := range tests {
+		got := StripOSC(tt.input)
+		if got != tt.expected {
+			t.Errorf("StripOSC(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+		if StripAnsi(got) != StripAnsi(tt.input) {
+			t.Errorf("StripAnsi(StripOSC(%q)) = %q, want %q", tt.input, StripAnsi(got), StripAnsi(tt.input))
+		}
+	}
+
+	if w := RuneWidth("\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"); w != 4 {
+		t.Errorf("RuneWidth of hyperlink = %d, want 4", w)
+	}
+}
+
 func ansiNode(text string) gox.VNode {
 	return gox.VNode{
 		Type:  "ansi",
@@ -140,7 +208,7 @@ func TestAnsiElement_ColoredText(t *testing.T) {
 func TestAnsiElement_CorrectWidth(t *testing.T) {
 	// "\x1b[32mhi\x1b[0m" is 2 visible chars wide, not 10+
 	node := ansiNode("\x1b[32mhi\x1b[0m")
-	w, h := measureAnsi(node, nil)
+	w, h := measureAnsi(node, LayoutContext{})
 	if w != 2 {
 		t.Errorf("ansi element width should be 2, got %d", w)
 	}
@@ -177,3 +245,21 @@ func TestAnsiElement_BoldAndColor(t *testing.T) {
 		t.Errorf("ansi element should output bold ANSI, got: %q", result)
 	}
 }
+
+func TestAnsiElement_MultilineCommandOutput(t *testing.T) {
+	// Shaped like `git diff --color` output
+	diff := "\x1b[1mdiff --git a/x b/x\x1b[0m\n\x1b[31m-old\x1b[0m\n\x1b[32m+new\x1b[0m"
+	node := ansiNode(diff)
+
+	w, h := measureAnsi(node, LayoutContext{})
+	if w != 18 || h != 3 {
+		t.Errorf("expected 18x3, got %dx%d", w, h)
+	}
+
+	result := sprintWith(boxNode(gox.Props{"width": 20, "height": 3}, node),
+		PrintOptions{Width: 20, Height: 3, StripStyles: true, TrimTrailingSpaces: true})
+	want := "diff --git a/x b/x\n-old\n+new\n"
+	if result != want {
+		t.Errorf("expected %q, got %q", want, result)
+	}
+}
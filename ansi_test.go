@@ -0,0 +1,258 @@
+package goli
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// solidBackgroundRuns returns one full-width run per row of blank cells
+// with a blue background.
+func solidBackgroundRuns(width, height int) []CellRun {
+	runs := make([]CellRun, height)
+	for y := range runs {
+		cells := make([]Cell, width)
+		for x := range cells {
+			cells[x] = Cell{Char: ' ', Style: Style{Background: ColorBlue}}
+		}
+		runs[y] = CellRun{X: 0, Y: y, Cells: cells}
+	}
+	return runs
+}
+
+func TestRunsToAnsiCompressed_SolidBackground(t *testing.T) {
+	runs := solidBackgroundRuns(200, 50)
+
+	var plain, compressed strings.Builder
+	RunsToAnsiBuilder(runs, &plain)
+	RunsToAnsiCompressed(runs, &compressed)
+
+	if !strings.Contains(compressed.String(), "\x1b[200X\x1b[200C") {
+		t.Errorf("expected erase + cursor advance for each row, got %q", compressed.String()[:40])
+	}
+	if compressed.Len()*2 > plain.Len() {
+		t.Errorf("expected at least 50%% fewer bytes, got %d vs %d", compressed.Len(), plain.Len())
+	}
+}
+
+func TestRunsToAnsiCompressed_KeepsShortAndStyledRuns(t *testing.T) {
+	inverse := Style{Inverse: true}
+	cells := []Cell{
+		{Char: 'a'}, {Char: ' '}, {Char: ' '}, {Char: 'b'},
+	}
+	for i := 0; i < 20; i++ {
+		cells = append(cells, Cell{Char: ' ', Style: inverse})
+	}
+
+	var plain, compressed strings.Builder
+	RunsToAnsiBuilder([]CellRun{{Cells: cells}}, &plain)
+	RunsToAnsiCompressed([]CellRun{{Cells: cells}}, &compressed)
+
+	if compressed.String() != plain.String() {
+		t.Errorf("expected identical output, got %q vs %q", compressed.String(), plain.String())
+	}
+}
+
+func TestBufferToSequentialAnsi_CompressesBlankRows(t *testing.T) {
+	buf := NewCellBuffer(40, 2)
+	buf.WriteString(0, 0, "hi", Style{})
+
+	if out := BufferToSequentialAnsi(buf); strings.Contains(out, "X") {
+		t.Errorf("expected no erase sequences by default, got %q", out)
+	}
+
+	out := bufferToSequentialAnsi(buf, true)
+	if !strings.Contains(out, "hi\x1b[38X\x1b[38C") {
+		t.Errorf("expected trailing blanks compressed on row 0, got %q", out)
+	}
+	if !strings.Contains(out, "\x1b[40X\x1b[40C") {
+		t.Errorf("expected blank row 1 compressed, got %q", out)
+	}
+}
+
+// replayAnsi draws ANSI output onto a blank buffer the way a terminal with
+// background color erase would. It understands the sequences goli emits:
+// cursor position, SGR, ECH, CUF, OSC hyperlinks and CRLF.
+func replayAnsi(out string, width, height int) *CellBuffer {
+	buf := NewCellBuffer(width, height)
+	var sgr strings.Builder
+	var style Style
+	x, y := 0, 0
+	for i := 0; i < len(out); {
+		switch {
+		case strings.HasPrefix(out[i:], "\x1b]"):
+			end := strings.Index(out[i:], "\x1b\\")
+			i += end + 2
+		case strings.HasPrefix(out[i:], "\x1b["):
+			j := i + 2
+			for out[j] < 0x40 || out[j] > 0x7e {
+				j++
+			}
+			seq, params := out[i:j+1], out[i+2:j]
+			var nums []int
+			for _, f := range strings.Split(params, ";") {
+				n, _ := strconv.Atoi(f)
+				nums = append(nums, n)
+			}
+			switch out[j] {
+			case 'H':
+				y, x = nums[0]-1, nums[1]-1
+			case 'm':
+				if params == "0" || params == "" {
+					sgr.Reset()
+				}
+				sgr.WriteString(seq)
+				segs := ParseAnsiLine(sgr.String()+"x", Style{})
+				style = segs[len(segs)-1].Style
+			case 'X':
+				for k := 0; k < nums[0]; k++ {
+					buf.Set(x+k, y, Cell{Char: ' ', Style: Style{Background: style.Background, BackgroundRGB: style.BackgroundRGB}})
+				}
+			case 'C':
+				x += nums[0]
+			}
+			i = j + 1
+		case strings.HasPrefix(out[i:], "\r\n"):
+			x, y = 0, y+1
+			i += 2
+		default:
+			r, size := utf8.DecodeRuneInString(out[i:])
+			buf.Set(x, y, Cell{Char: r, Style: style})
+			x += runewidth.RuneWidth(r)
+			i += size
+		}
+	}
+	return buf
+}
+
+// blankRunsBuffer returns a buffer mixing text with wide plain and
+// colored blank areas.
+func blankRunsBuffer() *CellBuffer {
+	buf := NewCellBuffer(60, 4)
+	fillBlank(buf, 0, 0, 60, 4, Style{Background: ColorBlue})
+	buf.WriteString(2, 0, "title", Style{Bold: true, Background: ColorBlue})
+	fillBlank(buf, 10, 1, 40, 1, Style{})
+	buf.WriteString(0, 2, "left", Style{Color: ColorRed})
+	fillBlank(buf, 20, 3, 30, 1, Style{BackgroundRGB: &RGB{10, 20, 30}})
+	return buf
+}
+
+func fillBlank(buf *CellBuffer, x0, y0, w, h int, style Style) {
+	for y := y0; y < y0+h; y++ {
+		for x := x0; x < x0+w; x++ {
+			buf.SetChar(x, y, ' ', style)
+		}
+	}
+}
+
+func assertSameScreen(t *testing.T, want, got *CellBuffer) {
+	t.Helper()
+	for y := 0; y < want.Height(); y++ {
+		for x := 0; x < want.Width(); x++ {
+			w, g := want.Get(x, y), got.Get(x, y)
+			if w.Char != g.Char || w.Style.Background != g.Style.Background ||
+				(w.Style.BackgroundRGB == nil) != (g.Style.BackgroundRGB == nil) {
+				t.Fatalf("cell (%d,%d): expected %q %+v, got %q %+v", x, y, w.Char, w.Style, g.Char, g.Style)
+			}
+		}
+	}
+}
+
+func TestRunsToAnsiCompressed_MatchesReference(t *testing.T) {
+	buf := blankRunsBuffer()
+	runs := FindRuns(DiffBuffers(NewCellBuffer(buf.Width(), buf.Height()), buf))
+
+	var plain, compressed strings.Builder
+	RunsToAnsiBuilder(runs, &plain)
+	RunsToAnsiCompressed(runs, &compressed)
+	if compressed.Len() >= plain.Len() {
+		t.Errorf("expected compressed output to be smaller, got %d vs %d", compressed.Len(), plain.Len())
+	}
+	assertSameScreen(t, replayAnsi(plain.String(), 60, 4), replayAnsi(compressed.String(), 60, 4))
+}
+
+func TestBufferToSequentialAnsi_CompressedMatchesReference(t *testing.T) {
+	buf := blankRunsBuffer()
+	plain := replayAnsi(BufferToSequentialAnsi(buf), 60, 4)
+	assertSameScreen(t, buf, plain)
+	assertSameScreen(t, plain, replayAnsi(bufferToSequentialAnsi(buf, true), 60, 4))
+}
+
+func benchmarkSolidBackground(b *testing.B, render func([]CellRun, *strings.Builder)) {
+	runs := solidBackgroundRuns(200, 50)
+	var sb strings.Builder
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sb.Reset()
+		render(runs, &sb)
+	}
+	b.ReportMetric(float64(sb.Len()), "bytes/frame")
+}
+
+func BenchmarkRunsToAnsi_SolidBackground(b *testing.B) {
+	benchmarkSolidBackground(b, RunsToAnsiBuilder)
+}
+
+func BenchmarkRunsToAnsiCompressed_SolidBackground(b *testing.B) {
+	benchmarkSolidBackground(b, RunsToAnsiCompressed)
+}
+
+func TestSetTerminalTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{"plain title", "goli", "\x1b]0;goli\x1b\\"},
+		{"empty title resets", "", "\x1b]0;\x1b\\"},
+		{"control characters dropped", "a\x1b\\b\x07c", "\x1b]0;a\\bc\x1b\\"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			SetTerminalTitle(&buf, tt.title)
+			if buf.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestMoveCursor(t *testing.T) {
+	tests := []struct {
+		x, y     int
+		expected string
+	}{
+		{0, 0, "\x1b[1;1H"},
+		{9, 2, "\x1b[3;10H"},
+		{255, 255, "\x1b[256;256H"},
+		{256, 0, "\x1b[1;257H"},
+		{3, 1000, "\x1b[1001;4H"},
+		{-1, 0, "\x1b[1;0H"},
+	}
+	for _, tt := range tests {
+		if got := MoveCursor(tt.x, tt.y); got != tt.expected {
+			t.Errorf("MoveCursor(%d, %d): expected %q, got %q", tt.x, tt.y, tt.expected, got)
+		}
+	}
+
+	for y := 0; y < moveCursorCacheSize; y++ {
+		for x := 0; x < moveCursorCacheSize; x++ {
+			if got, want := MoveCursor(x, y), moveCursor(x, y); got != want {
+				t.Fatalf("MoveCursor(%d, %d): expected %q, got %q", x, y, want, got)
+			}
+		}
+	}
+}
+
+func BenchmarkMoveCursor(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = MoveCursor(i%80, i/80%24)
+	}
+}
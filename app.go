@@ -2,10 +2,13 @@
 package goli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -14,35 +17,94 @@ import (
 
 // App represents a reactive TUI application.
 type App struct {
-	renderer    *Renderer
-	disposeRoot func()
-	rerender    func()
-	quit        func()
+	renderer RendererInterface
+	headless *HeadlessRenderer
+	output   io.Writer // Escape sequences outside frames, e.g. SetTitle
+	mount    func() func()
+	rerender func()
+	quit     func()
+
+	// Render completion tracking for WaitForRender
+	renderMu     sync.Mutex
+	renderCount  uint64
+	renderWaited uint64        // renderCount when WaitForRender last returned
+	rendered     chan struct{} // Closed and replaced after each render
+
+	onLayout     func(root *LayoutBox)
+	visibility   visibilityTracker // Watchers following this app's frames
+	stopRenderer func()            // Stops a renderer created by Render, on Dispose
+	disposeOnce  sync.Once
+
+	// mu guards the reactive root against concurrent Reload and Dispose
+	mu            sync.Mutex
+	disposeRoot   func()
+	disposed      bool
+	beforeDispose []func() // Run by Dispose before the root is disposed
+
+	ctx    context.Context
+	cancel context.CancelFunc // Called by Dispose
 }
 
+// ErrRenderTimeout is returned by App.WaitForRender when no render
+// completes within the timeout.
+var ErrRenderTimeout = errors.New("goli: timed out waiting for render")
+
 // Default frame rate limit (60 FPS = ~16.67ms per frame)
 const defaultFrameInterval = 16 * time.Millisecond
 
 // Render creates a reactive TUI application from a gox component.
+// It renders with a Renderer, a PipelineRenderer when opts.Pipeline is set,
+// or a HeadlessRenderer when opts.Headless is set.
 func Render(appFn func() gox.VNode, opts Options) *App {
 	output := opts.Output
 	if output == nil {
 		output = os.Stdout
 	}
 
-	r := NewRenderer(Options{
-		Width:  opts.Width,
-		Height: opts.Height,
-		Output: output,
-	})
+	var renderer RendererInterface
+	switch {
+	case opts.Headless:
+		renderer = NewHeadless(Options{Width: opts.Width, Height: opts.Height})
+	case opts.Pipeline:
+		renderer = NewPipeline(Options{Width: opts.Width, Height: opts.Height, Output: output})
+	default:
+		renderer = NewRenderer(Options{Width: opts.Width, Height: opts.Height, Output: output})
+	}
+
+	app := RenderWith(appFn, opts, renderer)
+	if p, ok := renderer.(*PipelineRenderer); ok {
+		app.stopRenderer = p.Stop
+	}
+	return app
+}
+
+// RenderWith is like Render but draws frames with the given renderer, e.g.
+// one returned by NewAuto. opts.Width, Height, Pipeline and Headless are
+// ignored; the caller stops a PipelineRenderer after disposing the app.
+func RenderWith(appFn func() gox.VNode, opts Options, renderer RendererInterface) *App {
+	app := &App{renderer: renderer, rendered: make(chan struct{})}
+	app.ctx, app.cancel = context.WithCancel(context.Background())
+	app.headless, _ = renderer.(*HeadlessRenderer)
+
+	app.output = opts.Output
+	if app.output == nil {
+		if app.headless != nil {
+			app.output = io.Discard
+		} else {
+			app.output = os.Stdout
+		}
+	}
+	r := app.renderer
+	layouts, _ := r.(layoutProvider)
+	sized, _ := r.(sizedRenderer)
 
-	var disposeRoot func()
 	var currentVNode gox.VNode
 	var hasVNode bool
 
 	// Simple throttling - just track last render time
 	var lastRender time.Time
 	throttleDisabled := opts.DisableThrottle
+	firstRendered := false
 
 	doRender := func() {
 		if !hasVNode {
@@ -58,38 +120,156 @@ func Render(appFn func() gox.VNode, opts Options) *App {
 			lastRender = now
 		}
 
+		// A panic while drawing (e.g., in an intrinsic) replaces the frame
+		// with the error; the next signal change renders normally again
+		defer func() {
+			if rec := recover(); rec != nil {
+				err := renderPanicError(rec)
+				if opts.OnError != nil {
+					opts.OnError(err)
+				}
+				r.Render(errorFrame(err))
+				app.renderDone()
+			}
+		}()
+
 		if opts.OnRender != nil {
 			opts.OnRender()
 		}
 		r.Render(currentVNode)
+		if app.onLayout != nil && layouts != nil {
+			app.onLayout(layouts.LastLayout())
+		}
+		app.renderDone()
+
+		// May re-render if a component reads a visibility that changed
+		if layouts != nil && sized != nil {
+			app.visibility.update(&Global.visibility, layouts.LastLayout(), ClipRegion{MaxX: sized.Width(), MaxY: sized.Height()})
+		}
+
+		if !firstRendered {
+			firstRendered = true
+			if opts.OnFirstRender != nil {
+				opts.OnFirstRender()
+			}
+		}
 	}
 
-	disposeRoot = CreateRoot(func(dispose DisposeFunc) func() {
-		CreateEffect(func() CleanupFunc {
-			defer func() {
-				if r := recover(); r != nil {
+	mount := func() func() {
+		return CreateRoot(func(dispose DisposeFunc) func() {
+			CreateEffect(func() CleanupFunc {
+				// A panicking component doesn't stop the effect: the
+				// signals it read before panicking still re-run it
+				vnode, err := buildFrame(appFn)
+				if err != nil {
 					if opts.OnError != nil {
-						if err, ok := r.(error); ok {
-							opts.OnError(err)
-						}
+						opts.OnError(err)
 					}
+					vnode = errorFrame(err)
 				}
-			}()
+				currentVNode = vnode
+				hasVNode = true
+				doRender()
+				return nil
+			})
 
-			currentVNode = appFn()
-			hasVNode = true
-			doRender()
-			return nil
+			return dispose
 		})
+	}
+	app.mount = mount
+	app.rerender = doRender
+	app.disposeRoot = mount()
 
-		return dispose
-	})
+	return app
+}
+
+// buildFrame calls appFn, returning a panic as an error.
+func buildFrame(appFn func() gox.VNode) (vnode gox.VNode, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = renderPanicError(rec)
+		}
+	}()
+	return appFn(), nil
+}
 
-	return &App{
-		renderer:    r,
-		disposeRoot: disposeRoot,
-		rerender:    doRender,
+// renderPanicError converts a value recovered during rendering to an error.
+func renderPanicError(rec any) error {
+	if err, ok := rec.(error); ok {
+		return err
 	}
+	return fmt.Errorf("goli: render panic: %v", rec)
+}
+
+// errorFrame is drawn in place of a frame whose rendering panicked.
+func errorFrame(err error) gox.VNode {
+	return gox.Element("box", gox.Props{"padding": 1},
+		gox.Element("text", gox.Props{"style": map[string]any{"bold": true, "color": "red"}},
+			gox.Text("Render error"),
+		),
+		gox.Element("text", nil, gox.Text(err.Error())),
+	)
+}
+
+// layoutProvider is implemented by renderers that lay out synchronously
+// and expose the result, such as Renderer.
+type layoutProvider interface {
+	LastLayout() *LayoutBox
+}
+
+// unmounter is implemented by renderers that call OnUnmount hooks for the
+// elements on screen when the app is disposed. A PipelineRenderer does so
+// when it stops.
+type unmounter interface {
+	unmount()
+}
+
+// sizedRenderer is implemented by renderers that report their screen
+// size, such as Renderer.
+type sizedRenderer interface {
+	Width() int
+	Height() int
+}
+
+// SetLayoutCallback registers fn to receive the layout tree after each
+// render, e.g. ScrollView.Update. Pass nil to remove it. It is not called
+// for renderers that lay out asynchronously, such as PipelineRenderer.
+func (a *App) SetLayoutCallback(fn func(root *LayoutBox)) {
+	a.onLayout = fn
+}
+
+// renderDone records a completed render and wakes WaitForRender callers.
+func (a *App) renderDone() {
+	a.renderMu.Lock()
+	a.renderCount++
+	close(a.rendered)
+	a.rendered = make(chan struct{})
+	a.renderMu.Unlock()
+}
+
+// WaitForRender blocks until a render has completed since WaitForRender
+// last returned (or since the app was created), so tests can inspect
+// CurrentBuffer after a signal change. Concurrent callers are all released
+// by the same render. Returns ErrRenderTimeout if none completes in time.
+func (a *App) WaitForRender(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	a.renderMu.Lock()
+	target := a.renderWaited + 1
+	for a.renderCount < target {
+		rendered := a.rendered
+		a.renderMu.Unlock()
+		select {
+		case <-rendered:
+		case <-timer.C:
+			return ErrRenderTimeout
+		}
+		a.renderMu.Lock()
+	}
+	a.renderWaited = max(a.renderWaited, a.renderCount)
+	a.renderMu.Unlock()
+	return nil
 }
 
 // Rerender forces a re-render.
@@ -97,22 +277,121 @@ func (a *App) Rerender() {
 	a.rerender()
 }
 
-// Dispose cleans up the app.
+// Dispose cleans up the app and cancels its Context. It is safe to call
+// more than once, including concurrently; only the first call has effect.
 func (a *App) Dispose() {
+	a.disposeOnce.Do(func() {
+		a.mu.Lock()
+		a.disposed = true
+		stops, root := a.beforeDispose, a.disposeRoot
+		a.beforeDispose, a.disposeRoot = nil, nil
+		a.mu.Unlock()
+
+		for _, stop := range stops {
+			stop()
+		}
+		if root != nil {
+			root()
+		}
+		if u, ok := a.renderer.(unmounter); ok {
+			u.unmount()
+		}
+		a.visibility.clear()
+		if a.stopRenderer != nil {
+			a.stopRenderer()
+			a.stopRenderer = nil
+		}
+		a.cancel()
+	})
+}
+
+// SetTitle sets the terminal window title. Headless apps without an
+// Output discard it.
+//
+// Example (title follows a signal):
+//
+//	CreateEffect(func() CleanupFunc {
+//	    app.SetTitle("myapp - " + fileName())
+//	    return nil
+//	})
+func (a *App) SetTitle(title string) {
+	SetTerminalTitle(a.output, title)
+}
+
+// Context returns a context that is canceled when the app is disposed, for
+// goroutines that should stop with the app. Reload doesn't cancel it.
+//
+// Example:
+//
+//	go func() {
+//	    for {
+//	        select {
+//	        case <-app.Context().Done():
+//	            return
+//	        case msg := <-updates:
+//	            setStatus(msg)
+//	        }
+//	    }
+//	}()
+func (a *App) Context() context.Context {
+	return a.ctx
+}
+
+// Reload disposes the app's reactive root and re-runs the app function in
+// a fresh root. Signals created outside the app function survive; signals
+// created inside components are recreated. It does nothing once the app is
+// disposed.
+func (a *App) Reload() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.disposed {
+		return
+	}
 	if a.disposeRoot != nil {
 		a.disposeRoot()
-		a.disposeRoot = nil
 	}
+	a.disposeRoot = a.mount()
+}
+
+// addBeforeDispose registers fn to run when Dispose starts, before the
+// reactive root is disposed, or runs it now if the app is disposed.
+func (a *App) addBeforeDispose(fn func()) {
+	a.mu.Lock()
+	if a.disposed {
+		a.mu.Unlock()
+		fn()
+		return
+	}
+	a.beforeDispose = append(a.beforeDispose, fn)
+	a.mu.Unlock()
 }
 
 // Renderer returns the underlying renderer.
-func (a *App) Renderer() *Renderer {
+func (a *App) Renderer() RendererInterface {
 	return a.renderer
 }
 
+// CurrentBuffer returns the renderer's current visual buffer.
+// Panics if the renderer doesn't expose one, e.g. a PipelineRenderer.
+func (a *App) CurrentBuffer() *CellBuffer {
+	r, ok := a.renderer.(interface{ CurrentBuffer() *CellBuffer })
+	if !ok {
+		panic(fmt.Sprintf("goli: %T does not support CurrentBuffer", a.renderer))
+	}
+	return r.CurrentBuffer()
+}
+
+// Headless returns the in-memory renderer when the app was created with
+// Options.Headless, or nil otherwise.
+func (a *App) Headless() *HeadlessRenderer {
+	return a.headless
+}
+
 // Resize resizes the terminal.
 func (a *App) Resize(width, height int) {
-	a.renderer.Resize(width, height)
+	if r, ok := a.renderer.(interface{ Resize(width, height int) }); ok {
+		r.Resize(width, height)
+	}
 	a.rerender()
 }
 
@@ -128,12 +407,40 @@ type RunOptions struct {
 	Width              int
 	Height             int
 	Output             io.Writer
+	Input              io.Reader // Defaults to os.Stdin
 	OnMount            func(*App)
 	OnUnmount          func()
 	OnRender           func()
 	OnError            func(error)
-	CaptureConsole     bool // Capture console output (default: true). Press Ctrl+L to toggle log viewer.
-	MaxConsoleMessages int  // Maximum number of console messages to keep (default: 1000)
+	OnFirstRender      func() // Called once, right after the first frame is drawn
+	CaptureConsole     bool   // Capture console output (default: true). Press Ctrl+L to toggle log viewer.
+	MaxConsoleMessages int    // Maximum number of console messages to keep (default: 1000)
+	PreReload          func() // Called before a hot reload (see WatchAndReload)
+	PostReload         func() // Called after a hot reload
+	MouseMode          MouseTrackingMode
+	OnMouse            func(ev MouseEvent) // Called for mouse events no focusable consumed
+	ShutdownTimeout    time.Duration       // Force-exit if cleanup takes longer (default: 2s)
+	Title              string              // Terminal window title, reset on exit
+}
+
+// defaultShutdownTimeout is the RunOptions.ShutdownTimeout default.
+const defaultShutdownTimeout = 2 * time.Second
+
+// exitProcess is os.Exit, replaced in tests.
+var exitProcess = os.Exit
+
+// runWithShutdownTimeout runs cleanup. If it doesn't return within timeout,
+// it prints a diagnostic, calls beforeExit and exits with status 1.
+func runWithShutdownTimeout(cleanup func(), timeout time.Duration, beforeExit func()) {
+	timer := time.AfterFunc(timeout, func() {
+		fmt.Fprintf(os.Stderr, "goli: shutdown did not finish within %v, exiting\n", timeout)
+		if beforeExit != nil {
+			beforeExit()
+		}
+		exitProcess(1)
+	})
+	defer timer.Stop()
+	cleanup()
 }
 
 // Run runs a TUI app with full terminal handling.
@@ -268,11 +575,12 @@ func Run(appFn func() gox.VNode, opts RunOptions) {
 	}()
 
 	app := Render(wrappedAppFn, Options{
-		Width:    width,
-		Height:   height,
-		Output:   output,
-		OnRender: opts.OnRender,
-		OnError:  opts.OnError,
+		Width:         width,
+		Height:        height,
+		Output:        output,
+		OnRender:      opts.OnRender,
+		OnError:       opts.OnError,
+		OnFirstRender: opts.OnFirstRender,
 	})
 
 	// Hide cursor
@@ -282,6 +590,22 @@ func Run(appFn func() gox.VNode, opts RunOptions) {
 	// Clear screen on exit
 	defer io.WriteString(output, ClearScreen())
 
+	if opts.Title != "" {
+		app.SetTitle(opts.Title)
+		defer SetTerminalTitle(output, "")
+	}
+
+	// Enable mouse reporting
+	if opts.MouseMode != MouseDisabled {
+		EnableMouse(output, opts.MouseMode)
+		defer DisableMouse(output)
+	}
+
+	input := opts.Input
+	if input == nil {
+		input = os.Stdin
+	}
+
 	// Handle signals
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGWINCH)
@@ -290,19 +614,32 @@ func Run(appFn func() gox.VNode, opts RunOptions) {
 	done := make(chan struct{})
 	var cleanedUp bool
 
+	shutdownTimeout := opts.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
 	// Cleanup function
 	cleanup := func() {
 		if cleanedUp {
 			return
 		}
 		cleanedUp = true
-		if logCapture != nil {
-			logCapture.Stop()
-		}
-		app.Dispose()
-		if opts.OnUnmount != nil {
-			opts.OnUnmount()
-		}
+		runWithShutdownTimeout(func() {
+			if logCapture != nil {
+				logCapture.Stop()
+			}
+			app.Dispose()
+			if opts.OnUnmount != nil {
+				opts.OnUnmount()
+			}
+		}, shutdownTimeout, func() {
+			// Deferred restores won't run after os.Exit
+			io.WriteString(output, ShowCursor())
+			if oldState != nil {
+				Restore(Stdin(), oldState)
+			}
+		})
 		close(done)
 	}
 
@@ -347,21 +684,49 @@ func Run(appFn func() gox.VNode, opts RunOptions) {
 		}
 	}()
 
-	// Start input reader
+	// Start input reader. Reads block, so keys are handed to the input
+	// goroutine below, which also runs work posted from other goroutines
+	// (key sequence timeouts) in order with them.
+	keys := make(chan string)
 	go func() {
+		defer close(keys)
 		buf := make([]byte, 64)
 		for {
+			n, err := input.Read(buf)
+			if err != nil {
+				// Any error on stdin (EOF, closed, etc.) - stop reading
+				// The app continues running for programmatic control
+				return
+			}
 			select {
+			case keys <- string(buf[:n]):
 			case <-done:
 				return
-			default:
-				n, err := os.Stdin.Read(buf)
-				if err != nil {
-					// Any error on stdin (EOF, closed, etc.) - stop reading
-					// The app continues running for programmatic control
-					return
+			}
+		}
+	}()
+
+	posted := make(chan func())
+	Manager().setInputRunner(func(fn func()) {
+		select {
+		case posted <- fn:
+		case <-done:
+		}
+	})
+	defer Manager().setInputRunner(nil)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case fn := <-posted:
+				fn()
+			case key, ok := <-keys:
+				if !ok {
+					keys = nil // Input closed; keep running posted work
+					continue
 				}
-				key := string(buf[:n])
 
 				// Ctrl+C exits
 				if key == "\x03" {
@@ -372,6 +737,18 @@ func Run(appFn func() gox.VNode, opts RunOptions) {
 					return
 				}
 
+				// Route mouse reports to the element under the pointer
+				if opts.MouseMode != MouseDisabled {
+					if events, ok := splitMouseEvents(key); ok {
+						for _, ev := range events {
+							if !HandleMouse(ev) && opts.OnMouse != nil {
+								opts.OnMouse(ev)
+							}
+						}
+						continue
+					}
+				}
+
 				// Route to focus manager (handles Tab, routes to focused element, then global handler)
 				HandleKey(key)
 			}
@@ -0,0 +1,348 @@
+package goli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+func TestApp_WaitForRender(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(1)
+
+	var output strings.Builder
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text(strings.Repeat("x", count())))
+	}, Options{Width: 10, Height: 1, Output: &output, DisableThrottle: true})
+	defer app.Dispose()
+
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatalf("expected initial render, got %v", err)
+	}
+	if err := app.WaitForRender(10 * time.Millisecond); err != ErrRenderTimeout {
+		t.Errorf("expected ErrRenderTimeout with no new render, got %v", err)
+	}
+
+	setCount(3)
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.CurrentBuffer().ToDebugString(); !strings.HasPrefix(got, "xxx ") {
+		t.Errorf("expected rerendered output, got %q", got)
+	}
+}
+
+func TestApp_WaitForRenderReleasesAllWaiters(t *testing.T) {
+	Reset()
+	var output strings.Builder
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("hi"))
+	}, Options{Width: 10, Height: 1, Output: &output, DisableThrottle: true})
+	defer app.Dispose()
+	app.WaitForRender(time.Second)
+
+	const waiters = 3
+	errs := make(chan error, waiters)
+	var wg sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- app.WaitForRender(time.Second)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	app.Rerender()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected every waiter released, got %v", err)
+		}
+	}
+}
+
+func TestApp_HeadlessNilByDefault(t *testing.T) {
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("hi"))
+	}, Options{Width: 5, Height: 1, Output: &strings.Builder{}, DisableThrottle: true})
+	defer app.Dispose()
+
+	if app.Headless() != nil {
+		t.Error("expected nil Headless for an app rendering to Output")
+	}
+}
+
+func TestApp_ContextCanceledOnDispose(t *testing.T) {
+	Reset()
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("hi"))
+	}, Options{Width: 5, Height: 1, Headless: true, DisableThrottle: true})
+
+	ctx := app.Context()
+	app.Reload()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("expected live context before Dispose, got %v", err)
+	}
+
+	app.Dispose()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected context to be canceled after Dispose")
+	}
+}
+
+func TestRunWithShutdownTimeout_ForceExitsWhenCleanupHangs(t *testing.T) {
+	exited := make(chan int, 1)
+	prevExit := exitProcess
+	exitProcess = func(code int) { exited <- code }
+	defer func() { exitProcess = prevExit }()
+
+	release := make(chan struct{})
+	defer close(release)
+	restored := false
+	go runWithShutdownTimeout(func() { <-release }, 20*time.Millisecond, func() { restored = true })
+
+	select {
+	case code := <-exited:
+		if code != 1 {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+		if !restored {
+			t.Error("expected beforeExit to run before exiting")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected force exit after the shutdown timeout")
+	}
+}
+
+func TestRunWithShutdownTimeout_NoExitWhenCleanupFinishes(t *testing.T) {
+	exited := make(chan int, 1)
+	prevExit := exitProcess
+	exitProcess = func(code int) { exited <- code }
+	defer func() { exitProcess = prevExit }()
+
+	runWithShutdownTimeout(func() {}, 20*time.Millisecond, nil)
+
+	select {
+	case <-exited:
+		t.Error("expected no exit after cleanup finished in time")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestApp_SetTitleWritesToOutput(t *testing.T) {
+	Reset()
+	var buf bytes.Buffer
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("hi"))
+	}, Options{Width: 5, Height: 1, Output: &buf, DisableThrottle: true})
+	defer app.Dispose()
+
+	buf.Reset()
+	app.SetTitle("goli")
+	if buf.String() != "\x1b]0;goli\x1b\\" {
+		t.Errorf("expected OSC 0 title sequence, got %q", buf.String())
+	}
+}
+
+func TestRenderWith_PipelineRenderer(t *testing.T) {
+	Reset()
+	var out syncBuffer
+	p := NewPipeline(Options{Width: 10, Height: 1, Output: &out})
+	defer p.Stop()
+	app := RenderWith(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("piped"))
+	}, Options{DisableThrottle: true}, p)
+	defer app.Dispose()
+
+	if app.Renderer() != RendererInterface(p) {
+		t.Errorf("expected Renderer to return the given pipeline, got %T", app.Renderer())
+	}
+	if err := p.Flush(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "piped") {
+		t.Errorf("expected pipeline output to contain %q, got %q", "piped", out.String())
+	}
+}
+
+func TestApp_CurrentBufferPanicsForPipeline(t *testing.T) {
+	Reset()
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("hi"))
+	}, Options{Width: 5, Height: 1, Output: &syncBuffer{}, Pipeline: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected CurrentBuffer to panic for a pipeline renderer")
+		}
+	}()
+	app.CurrentBuffer()
+}
+
+func TestApp_RecoversFromComponentPanic(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+
+	var errs []error
+	app := Render(func() gox.VNode {
+		if count() == 1 {
+			panic("boom")
+		}
+		return gox.Element("text", nil, gox.Text(fmt.Sprintf("count %d", count())))
+	}, Options{Width: 30, Height: 5, Headless: true, DisableThrottle: true, OnError: func(err error) {
+		errs = append(errs, err)
+	}})
+	defer app.Dispose()
+	app.WaitForRender(time.Second)
+
+	setCount(1)
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatalf("expected an error frame, got %v", err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "boom") {
+		t.Errorf("expected OnError with the panic, got %v", errs)
+	}
+	if !app.Headless().ContainsText("boom") {
+		t.Errorf("expected the error frame to show the panic, got:\n%s", app.Headless().PlainOutput())
+	}
+
+	setCount(2)
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatalf("expected rendering to continue after the panic, got %v", err)
+	}
+	app.Headless().AssertContains(t, "count 2")
+}
+
+func TestApp_OnFirstRender(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	r := NewRenderer(Options{Width: 20, Height: 3, Output: &bytes.Buffer{}})
+
+	calls := 0
+	rendered := false
+	app := RenderWith(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text(fmt.Sprintf("count %d", count())))
+	}, Options{DisableThrottle: true, OnFirstRender: func() {
+		calls++
+		_, _, rendered = r.CurrentBuffer().FindText("count 0")
+	}}, r)
+	defer app.Dispose()
+
+	if calls != 1 || !rendered {
+		t.Fatalf("expected one call after the first frame was drawn, got %d calls (rendered %v)", calls, rendered)
+	}
+	setCount(1)
+	app.WaitForRender(time.Second)
+	if calls != 1 {
+		t.Errorf("expected OnFirstRender only once, got %d calls", calls)
+	}
+}
+
+func TestApp_OnFirstRenderSetsSignal(t *testing.T) {
+	Reset()
+	loaded, setLoaded := CreateSignal(false)
+
+	app := Render(func() gox.VNode {
+		if loaded() {
+			return gox.Element("text", nil, gox.Text("loaded"))
+		}
+		return gox.Element("text", nil, gox.Text("loading"))
+	}, Options{Width: 20, Height: 3, Headless: true, DisableThrottle: true, OnFirstRender: func() {
+		setLoaded(true)
+	}})
+	defer app.Dispose()
+
+	app.WaitForRender(time.Second)
+	app.Headless().AssertContains(t, "loaded")
+}
+
+func TestApp_RecoversFromRenderPanic(t *testing.T) {
+	Reset()
+	RegisterIntrinsic("panicOnRender", &IntrinsicHandler{
+		Measure: func(node gox.VNode, ctx LayoutContext) (int, int) { return 1, 1 },
+		RenderLogical: func(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion) {
+			panic(errors.New("render failed"))
+		},
+	})
+	broken, setBroken := CreateSignal(true)
+
+	var errs []error
+	app := Render(func() gox.VNode {
+		if broken() {
+			return gox.Element("panicOnRender", nil)
+		}
+		return gox.Element("text", nil, gox.Text("fixed"))
+	}, Options{Width: 30, Height: 5, Headless: true, DisableThrottle: true, OnError: func(err error) {
+		errs = append(errs, err)
+	}})
+	defer app.Dispose()
+	app.WaitForRender(time.Second)
+
+	if len(errs) != 1 || errs[0].Error() != "render failed" {
+		t.Errorf("expected OnError with the render error, got %v", errs)
+	}
+	app.Headless().AssertContains(t, "render failed")
+
+	setBroken(false)
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	app.Headless().AssertContains(t, "fixed")
+}
+
+func TestApp_ConcurrentDispose(t *testing.T) {
+	Reset()
+	cleanups := 0
+	var output strings.Builder
+	app := Render(func() gox.VNode {
+		OnCleanup(func() { cleanups++ })
+		return gox.Element("text", nil, gox.Text("hi"))
+	}, Options{Width: 10, Height: 1, Output: &output, Pipeline: true, DisableThrottle: true})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.Dispose()
+		}()
+	}
+	wg.Wait()
+	app.Dispose()
+
+	if cleanups != 1 {
+		t.Errorf("expected the root to be disposed once, got %d cleanups", cleanups)
+	}
+	if app.Context().Err() == nil {
+		t.Error("expected the context to be canceled")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the pipeline's output goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -1,7 +1,9 @@
 package goli
 
 // Batch batches multiple signal updates into a single update cycle.
-// All effects are deferred until the batch completes.
+// All effects are deferred until the batch completes. Batches are per
+// goroutine: writes from other goroutines are unaffected, and a batch
+// ending on one goroutine doesn't flush another's.
 //
 // Example:
 //
@@ -14,11 +16,14 @@ package goli
 //	    // Effects run only once after both updates
 //	})
 func Batch[T any](fn func() T) T {
-	Global.incrementBatchDepth()
+	rt := Global
+	gid := goroutineID()
+	rt.incrementBatchDepth(gid)
 
 	defer func() {
-		if Global.decrementBatchDepth() {
-			Global.flushPending()
+		if b := rt.decrementBatchDepth(gid); b != nil {
+			rt.flushPending(b)
+			rt.flushIdle(b)
 		}
 	}()
 
@@ -33,6 +38,42 @@ func BatchVoid(fn func()) {
 	})
 }
 
+// BatchErr is like Batch for functions that can fail.
+// Effects are flushed when the batch completes, whether or not fn errors.
+func BatchErr[T any](fn func() (T, error)) (T, error) {
+	var err error
+	value := Batch(func() T {
+		var v T
+		v, err = fn()
+		return v
+	})
+	return value, err
+}
+
+// BatchMany runs all functions inside a single batch.
+func BatchMany(fns ...func()) {
+	BatchVoid(func() {
+		for _, fn := range fns {
+			fn()
+		}
+	})
+}
+
+// WhenIdle runs fn after the current batch completes and its effects have
+// run, or immediately if no batch is active.
+//
+// Example:
+//
+//	BatchVoid(func() {
+//	    setItems(next)
+//	    WhenIdle(func() { list.ScrollToBottom() })
+//	})
+func WhenIdle(fn func()) {
+	if !Global.addIdleCallback(fn) {
+		fn()
+	}
+}
+
 // Untrack reads signals without tracking them as dependencies.
 //
 // Example:
@@ -1,11 +1,18 @@
 // Package goli provides buffer implementations for terminal rendering.
 package goli
 
-import "strings"
-
-// MaxBufferHeight is the maximum height a LogicalBuffer can auto-grow to.
-// This prevents runaway memory usage from unbounded growth.
-// 10,000 lines is generous for most TUI applications.
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// MaxBufferHeight is the default maximum height a LogicalBuffer can
+// auto-grow to. This prevents runaway memory usage from unbounded growth.
+// 10,000 lines is generous for most TUI applications; use
+// NewLogicalBufferCapped or SetMaxHeight to change it per buffer.
 const MaxBufferHeight = 10000
 
 // CellBuffer is a fixed-size 2D grid of cells representing the terminal screen.
@@ -13,6 +20,7 @@ const MaxBufferHeight = 10000
 type CellBuffer struct {
 	width, height int
 	cells         []Cell
+	rowHashes     []uint64 // XOR of cellHash over each row, kept current by Set
 }
 
 // NewCellBuffer creates a new buffer filled with empty cells.
@@ -21,11 +29,106 @@ func NewCellBuffer(width, height int) *CellBuffer {
 	for i := range cells {
 		cells[i] = EmptyCell
 	}
-	return &CellBuffer{
-		width:  width,
-		height: height,
-		cells:  cells,
+	b := &CellBuffer{
+		width:     width,
+		height:    height,
+		cells:     cells,
+		rowHashes: make([]uint64, height),
+	}
+	b.fillRowHashes(emptyRowHash(width))
+	return b
+}
+
+// cellHash hashes a cell at column x, FNV-1a style. Equal cells (per
+// Cell.Equal) at the same column hash equally, so rows whose hashes differ
+// have changed.
+func cellHash(x int, c Cell) uint64 {
+	s := c.Style
+	var flags uint64
+	if s.Bold {
+		flags |= 1
+	}
+	if s.Dim {
+		flags |= 2
+	}
+	if s.Italic {
+		flags |= 4
+	}
+	if s.Underline {
+		flags |= 8
+	}
+	if s.Inverse {
+		flags |= 16
 	}
+	if s.Strikethrough {
+		flags |= 32
+	}
+	h := fnvMix(fnvOffset, uint64(x)&0xffff|uint64(c.Char)<<16|uint64(s.Color)<<40|uint64(s.Background)<<48|flags<<56)
+
+	var rgb uint64
+	if s.ColorRGB != nil {
+		rgb = 1<<24 | uint64(s.ColorRGB.R)<<16 | uint64(s.ColorRGB.G)<<8 | uint64(s.ColorRGB.B)
+	}
+	if s.BackgroundRGB != nil {
+		rgb |= (1<<24 | uint64(s.BackgroundRGB.R)<<16 | uint64(s.BackgroundRGB.G)<<8 | uint64(s.BackgroundRGB.B)) << 32
+	}
+	if rgb != 0 {
+		h = fnvMix(h, rgb)
+	}
+	for i := 0; i < len(s.HyperlinkURL); i++ {
+		h = fnvMix(h, uint64(s.HyperlinkURL[i]))
+	}
+	return h
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+func fnvMix(h, v uint64) uint64 {
+	return (h ^ v) * fnvPrime
+}
+
+// emptyRowHash returns the hash of a row of EmptyCells.
+func emptyRowHash(width int) uint64 {
+	var h uint64
+	for x := 0; x < width; x++ {
+		h ^= cellHash(x, EmptyCell)
+	}
+	return h
+}
+
+func (b *CellBuffer) fillRowHashes(h uint64) {
+	for y := range b.rowHashes {
+		b.rowHashes[y] = h
+	}
+}
+
+// rehash recomputes every row hash from the cells.
+func (b *CellBuffer) rehash() {
+	if cap(b.rowHashes) < b.height {
+		b.rowHashes = make([]uint64, b.height)
+	}
+	b.rowHashes = b.rowHashes[:b.height]
+	for y := 0; y < b.height; y++ {
+		var h uint64
+		for x, c := range b.cells[y*b.width : (y+1)*b.width] {
+			h ^= cellHash(x, c)
+		}
+		b.rowHashes[y] = h
+	}
+}
+
+// rowsEqual reports whether row y holds identical cells in a and b. Differing
+// row hashes prove a change, but matching ones don't prove equality: the XOR
+// of cell hashes can collide (e.g. for two swapped cells), so the cells are
+// compared too. false means the row must be diffed cell by cell.
+func rowsEqual(a, b *CellBuffer, y int) bool {
+	if a.width != b.width || a.rowHashes[y] != b.rowHashes[y] {
+		return false
+	}
+	return slices.Equal(a.cells[y*a.width:(y+1)*a.width], b.cells[y*b.width:(y+1)*b.width])
 }
 
 func (b *CellBuffer) index(x, y int) int {
@@ -55,7 +158,9 @@ func (b *CellBuffer) Set(x, y int, c Cell) {
 	if !b.inBounds(x, y) {
 		return
 	}
-	b.cells[b.index(x, y)] = c
+	i := b.index(x, y)
+	b.rowHashes[y] ^= cellHash(x, b.cells[i]) ^ cellHash(x, c)
+	b.cells[i] = c
 }
 
 // SetChar sets a character with style at (x, y).
@@ -103,11 +208,82 @@ func (b *CellBuffer) WriteString(x, y int, text string, style Style) int {
 	return written
 }
 
+// WriteSegments writes pre-parsed ANSI segments starting at (x, y), merging
+// each segment's style with the existing cell. Returns the number of columns
+// advanced (the display width of all segments), including clipped ones.
+func (b *CellBuffer) WriteSegments(x, y int, segments []AnsiSegment) int {
+	col := x
+	for _, seg := range segments {
+		for _, char := range seg.Text {
+			b.SetCharMerge(col, y, char, seg.Style)
+			col += runewidth.RuneWidth(char)
+		}
+	}
+	return col - x
+}
+
+// Resize changes the buffer dimensions, preserving cells that fall within
+// both the old and new bounds. New cells are EmptyCell. The backing array is
+// reused when it has enough capacity, so repeated resizes don't allocate.
+func (b *CellBuffer) Resize(newWidth, newHeight int) {
+	if newWidth < 0 {
+		newWidth = 0
+	}
+	if newHeight < 0 {
+		newHeight = 0
+	}
+	if newWidth == b.width && newHeight == b.height {
+		return
+	}
+
+	oldWidth, oldLen := b.width, len(b.cells)
+	newLen := newWidth * newHeight
+	keepRows := min(b.height, newHeight)
+	keepCols := min(oldWidth, newWidth)
+
+	if newLen > cap(b.cells) {
+		// Not enough room: copy the overlapping region into a new array
+		cells := make([]Cell, newLen)
+		for i := range cells {
+			cells[i] = EmptyCell
+		}
+		for y := 0; y < keepRows; y++ {
+			copy(cells[y*newWidth:y*newWidth+keepCols], b.cells[y*oldWidth:y*oldWidth+keepCols])
+		}
+		b.width, b.height, b.cells = newWidth, newHeight, cells
+		b.rehash()
+		return
+	}
+
+	// Rearrange rows in place. Narrower rows move toward the front, so walk
+	// top-down; wider rows move toward the back, so walk bottom-up.
+	b.cells = b.cells[:max(oldLen, newLen)]
+	if newWidth <= oldWidth {
+		for y := 0; y < keepRows; y++ {
+			copy(b.cells[y*newWidth:y*newWidth+keepCols], b.cells[y*oldWidth:y*oldWidth+keepCols])
+		}
+	} else {
+		for y := keepRows - 1; y >= 0; y-- {
+			copy(b.cells[y*newWidth:y*newWidth+keepCols], b.cells[y*oldWidth:y*oldWidth+keepCols])
+			for i := y*newWidth + keepCols; i < (y+1)*newWidth; i++ {
+				b.cells[i] = EmptyCell
+			}
+		}
+	}
+	b.cells = b.cells[:newLen]
+	for i := keepRows * newWidth; i < newLen; i++ {
+		b.cells[i] = EmptyCell
+	}
+	b.width, b.height = newWidth, newHeight
+	b.rehash()
+}
+
 // Clear clears the entire buffer with empty cells.
 func (b *CellBuffer) Clear() {
 	for i := range b.cells {
 		b.cells[i] = EmptyCell
 	}
+	b.fillRowHashes(emptyRowHash(b.width))
 }
 
 // ToDebugString returns a debug string representation (characters only).
@@ -124,6 +300,74 @@ func (b *CellBuffer) ToDebugString() string {
 	return sb.String()
 }
 
+// FindText returns the cell position of the first occurrence of s, scanning
+// rows top to bottom. Matches don't span rows, and an empty s is never
+// found.
+func (b *CellBuffer) FindText(s string) (x, y int, found bool) {
+	if m := findText(s, b.height, b.rowText, b.Get, 1); len(m) > 0 {
+		return m[0].X, m[0].Y, true
+	}
+	return 0, 0, false
+}
+
+// FindTextAll returns every occurrence of s, including overlapping ones,
+// scanning rows top to bottom and each row left to right.
+func (b *CellBuffer) FindTextAll(s string) []TextMatch {
+	return findText(s, b.height, b.rowText, b.Get, 0)
+}
+
+// rowText returns the text of row y and the cell column of each of its runes.
+func (b *CellBuffer) rowText(y int) (string, []int) {
+	if y < 0 || y >= b.height {
+		return "", nil
+	}
+	var sb strings.Builder
+	columns := make([]int, 0, b.width)
+	for x, cell := range b.cells[y*b.width : (y+1)*b.width] {
+		if cell.Char == 0 {
+			continue
+		}
+		sb.WriteRune(cell.Char)
+		columns = append(columns, x)
+	}
+	return sb.String(), columns
+}
+
+// TextMatch is an occurrence of text found in a buffer.
+type TextMatch struct {
+	X, Y  int   // Cell position of the first character
+	Style Style // Style of the first character
+}
+
+// findText searches the rows of a buffer for s, returning at most limit
+// matches (0 = all). rowText gives each row's text and the cell column of
+// each rune; get looks up the style of a match's first cell.
+func findText(s string, height int, rowText func(y int) (string, []int), get func(x, y int) Cell, limit int) []TextMatch {
+	if s == "" {
+		return nil
+	}
+	var matches []TextMatch
+	for y := 0; y < height; y++ {
+		text, columns := rowText(y)
+		for start := 0; start < len(text); {
+			i := strings.Index(text[start:], s)
+			if i < 0 {
+				break
+			}
+			i += start
+			x := columns[utf8.RuneCountInString(text[:i])]
+			matches = append(matches, TextMatch{X: x, Y: y, Style: get(x, y).Style})
+			if len(matches) == limit {
+				return matches
+			}
+			// Resume one rune later so overlapping matches are found
+			_, size := utf8.DecodeRuneInString(text[i:])
+			start = i + size
+		}
+	}
+	return matches
+}
+
 // LogicalRow is a variable-length array of cells.
 type LogicalRow struct {
 	Cells []Cell
@@ -132,20 +376,49 @@ type LogicalRow struct {
 // LogicalBuffer stores content as logical rows with arbitrary length.
 // Terminal wrapping is handled at render time, not storage time.
 type LogicalBuffer struct {
-	rows   []LogicalRow
-	height int
+	rows      []LogicalRow
+	height    int
+	maxHeight int
 }
 
-// NewLogicalBuffer creates a new logical buffer with the given height.
+// NewLogicalBuffer creates a new logical buffer with the given height that
+// can auto-grow up to MaxBufferHeight rows.
 func NewLogicalBuffer(height int) *LogicalBuffer {
+	return NewLogicalBufferCapped(height, MaxBufferHeight)
+}
+
+// NewLogicalBufferCapped creates a new logical buffer with the given height
+// that can auto-grow up to maxHeight rows. A maxHeight <= 0 means
+// MaxBufferHeight.
+func NewLogicalBufferCapped(height, maxHeight int) *LogicalBuffer {
 	rows := make([]LogicalRow, height)
 	for i := range rows {
 		rows[i] = LogicalRow{Cells: nil}
 	}
-	return &LogicalBuffer{
+	b := &LogicalBuffer{
 		rows:   rows,
 		height: height,
 	}
+	b.SetMaxHeight(maxHeight)
+	return b
+}
+
+// SetMaxHeight changes the height the buffer can auto-grow to. A value
+// <= 0 restores MaxBufferHeight. Rows already beyond the new limit are
+// kept, but Set and SetMerge ignore them from now on.
+func (b *LogicalBuffer) SetMaxHeight(n int) {
+	if n <= 0 {
+		n = MaxBufferHeight
+	}
+	b.maxHeight = n
+}
+
+// MaxHeight returns the height the buffer can auto-grow to.
+func (b *LogicalBuffer) MaxHeight() int {
+	if b.maxHeight <= 0 {
+		return MaxBufferHeight
+	}
+	return b.maxHeight
 }
 
 // Height returns the number of logical rows.
@@ -166,9 +439,9 @@ func (b *LogicalBuffer) Get(x, y int) Cell {
 
 // Set sets the cell at logical position (x, y).
 // Extends the row if needed. Grows the buffer if y exceeds current height.
-// Will not grow beyond MaxBufferHeight.
+// Writes at or beyond MaxHeight are ignored.
 func (b *LogicalBuffer) Set(x, y int, c Cell) {
-	if x < 0 || y < 0 || y >= MaxBufferHeight {
+	if x < 0 || y < 0 || y >= b.MaxHeight() {
 		return
 	}
 	// Grow buffer if needed
@@ -187,9 +460,10 @@ func (b *LogicalBuffer) Set(x, y int, c Cell) {
 
 // SetMerge sets a cell, merging style with existing cell.
 // Preserves background color if the new style doesn't specify one.
-// Grows the buffer if y exceeds current height. Will not grow beyond MaxBufferHeight.
+// Grows the buffer if y exceeds current height. Writes at or beyond
+// MaxHeight are ignored.
 func (b *LogicalBuffer) SetMerge(x, y int, c Cell) {
-	if x < 0 || y < 0 || y >= MaxBufferHeight {
+	if x < 0 || y < 0 || y >= b.MaxHeight() {
 		return
 	}
 	// Grow buffer if needed
@@ -230,6 +504,47 @@ func (b *LogicalBuffer) GetRow(y int) *LogicalRow {
 	return &b.rows[y]
 }
 
+// GetRowText returns the characters of row y as a string, skipping cells
+// with no character.
+func (b *LogicalBuffer) GetRowText(y int) string {
+	text, _ := b.rowText(y)
+	return text
+}
+
+// FindText returns the cell position of the first occurrence of s, scanning
+// rows top to bottom. Matches don't span rows, and an empty s is never
+// found.
+func (b *LogicalBuffer) FindText(s string) (x, y int, found bool) {
+	if m := findText(s, b.height, b.rowText, b.Get, 1); len(m) > 0 {
+		return m[0].X, m[0].Y, true
+	}
+	return 0, 0, false
+}
+
+// FindTextAll returns every occurrence of s, including overlapping ones,
+// scanning rows top to bottom and each row left to right.
+func (b *LogicalBuffer) FindTextAll(s string) []TextMatch {
+	return findText(s, b.height, b.rowText, b.Get, 0)
+}
+
+// rowText returns the text of row y and the cell column of each of its runes.
+func (b *LogicalBuffer) rowText(y int) (string, []int) {
+	row := b.GetRow(y)
+	if row == nil {
+		return "", nil
+	}
+	var sb strings.Builder
+	columns := make([]int, 0, len(row.Cells))
+	for x, cell := range row.Cells {
+		if cell.Char == 0 {
+			continue
+		}
+		sb.WriteRune(cell.Char)
+		columns = append(columns, x)
+	}
+	return sb.String(), columns
+}
+
 // WriteString writes a string starting at (x, y).
 // The row extends as needed - no clipping.
 func (b *LogicalBuffer) WriteString(x, y int, text string, style Style) {
@@ -243,6 +558,20 @@ func (b *LogicalBuffer) WriteString(x, y int, text string, style Style) {
 	}
 }
 
+// WriteSegments writes pre-parsed ANSI segments starting at (x, y), merging
+// each segment's style with the existing cell. Returns the number of columns
+// advanced (the display width of all segments).
+func (b *LogicalBuffer) WriteSegments(x, y int, segments []AnsiSegment) int {
+	col := x
+	for _, seg := range segments {
+		for _, char := range seg.Text {
+			b.SetMerge(col, y, New(char, seg.Style))
+			col += runewidth.RuneWidth(char)
+		}
+	}
+	return col - x
+}
+
 // ClearRow clears a row.
 func (b *LogicalBuffer) ClearRow(y int) {
 	if y < 0 || y >= b.height {
@@ -251,6 +580,25 @@ func (b *LogicalBuffer) ClearRow(y int) {
 	b.rows[y] = LogicalRow{Cells: nil}
 }
 
+// Resize trims or extends the buffer to newHeight rows.
+// Rows within the new height are preserved; added rows are empty.
+func (b *LogicalBuffer) Resize(newHeight int) {
+	if newHeight < 0 {
+		newHeight = 0
+	}
+	if newHeight <= len(b.rows) {
+		for y := newHeight; y < len(b.rows); y++ {
+			b.rows[y] = LogicalRow{Cells: nil}
+		}
+		b.rows = b.rows[:newHeight]
+	} else {
+		for len(b.rows) < newHeight {
+			b.rows = append(b.rows, LogicalRow{Cells: nil})
+		}
+	}
+	b.height = newHeight
+}
+
 // Clear clears the entire buffer.
 func (b *LogicalBuffer) Clear() {
 	for y := 0; y < b.height; y++ {
@@ -258,6 +606,76 @@ func (b *LogicalBuffer) Clear() {
 	}
 }
 
+// CopyFrom makes this buffer an exact copy of src, reusing its row storage.
+func (b *LogicalBuffer) CopyFrom(src *LogicalBuffer) {
+	b.Resize(src.height)
+	for y := range src.rows {
+		b.rows[y].Cells = append(b.rows[y].Cells[:0], src.rows[y].Cells...)
+	}
+}
+
+// contentHash returns an FNV-1a hash of the buffer's height and non-empty
+// cells with their positions. Buffers with the same content hash equally.
+func (b *LogicalBuffer) contentHash() uint64 {
+	h := fnvMix(fnvOffset, uint64(b.height))
+	for y := 0; y < b.height; y++ {
+		h = fnvMix(h, uint64(y))
+		for x, c := range b.rows[y].Cells {
+			if c.Char != 0 {
+				h = fnvMix(h, cellHash(x, c))
+			}
+		}
+	}
+	return h
+}
+
+// AppendBuffer composites src onto this buffer with its origin at
+// (offsetX, offsetY). Empty cells in src are skipped so the destination
+// shows through, and non-empty cells are written with SetMerge semantics.
+// Cells that land at negative coordinates are clipped.
+func (b *LogicalBuffer) AppendBuffer(src *LogicalBuffer, offsetX, offsetY int) {
+	for sy := 0; sy < src.height; sy++ {
+		y := sy + offsetY
+		if y < 0 {
+			continue
+		}
+		for sx, c := range src.rows[sy].Cells {
+			x := sx + offsetX
+			if x < 0 || c.Equal(EmptyCell) {
+				continue
+			}
+			b.SetMerge(x, y, c)
+		}
+	}
+}
+
+// GetRegion copies the w×h rectangle starting at (x, y) into a new buffer
+// whose origin is the rectangle's top-left corner. Parts of the rectangle
+// outside this buffer read as empty.
+func (b *LogicalBuffer) GetRegion(x, y, w, h int) *LogicalBuffer {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	region := NewLogicalBuffer(h)
+	for dy := 0; dy < h; dy++ {
+		sy := y + dy
+		if sy < 0 || sy >= b.height {
+			continue
+		}
+		cells := b.rows[sy].Cells
+		for dx := 0; dx < w && x+dx < len(cells); dx++ {
+			if x+dx < 0 {
+				continue
+			}
+			region.Set(dx, dy, cells[x+dx])
+		}
+	}
+	return region
+}
+
 // VisualRows holds the result of transforming logical rows to visual rows.
 type VisualRows struct {
 	Rows            [][]Cell // Visual rows
@@ -0,0 +1,195 @@
+package goli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// csvHeader names the columns written by CellBuffer.ToCSV.
+var csvHeader = []string{
+	"x", "y", "char", "fgColor", "bgColor",
+	"bold", "italic", "underline", "dim", "inverse", "strikethrough", "link",
+}
+
+// colorNames are the CSV names of the named colors; ColorNone is empty.
+var colorNames = [...]string{
+	ColorDefault:       "default",
+	ColorBlack:         "black",
+	ColorRed:           "red",
+	ColorGreen:         "green",
+	ColorYellow:        "yellow",
+	ColorBlue:          "blue",
+	ColorMagenta:       "magenta",
+	ColorCyan:          "cyan",
+	ColorWhite:         "white",
+	ColorBrightBlack:   "brightBlack",
+	ColorBrightRed:     "brightRed",
+	ColorBrightGreen:   "brightGreen",
+	ColorBrightYellow:  "brightYellow",
+	ColorBrightBlue:    "brightBlue",
+	ColorBrightMagenta: "brightMagenta",
+	ColorBrightCyan:    "brightCyan",
+	ColorBrightWhite:   "brightWhite",
+}
+
+// CSVOptions configures CellBuffer.ToCSV.
+type CSVOptions struct {
+	// ChangedOnly exports only the cells that differ from From.
+	ChangedOnly bool
+	// From is the buffer to compare against; nil compares against an
+	// empty buffer, exporting only non-empty cells.
+	From *CellBuffer
+}
+
+// ToCSV exports cells as CSV, one row per cell after a header row:
+//
+//	x,y,char,fgColor,bgColor,bold,italic,underline,dim,inverse,strikethrough,link
+//
+// Colors are names ("red", "brightBlue"), "#rrggbb" for RGB, or empty
+// when unset. A cell with no character (the right half of a wide
+// character) has an empty char. This is meant for test failure output and
+// diffing tools; LoadCSV reads it back.
+func (b *CellBuffer) ToCSV(opts CSVOptions) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Write(csvHeader)
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			cell := b.Get(x, y)
+			if opts.ChangedOnly {
+				prev := EmptyCell
+				if opts.From != nil {
+					prev = opts.From.Get(x, y)
+				}
+				if cell.Equal(prev) {
+					continue
+				}
+			}
+			w.Write(cellRecord(x, y, cell))
+		}
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// LoadCSV sets the cells listed in CSV written by ToCSV. Cells not listed
+// are left unchanged, so loading a ChangedOnly export into a copy of its
+// From buffer reproduces the exported buffer.
+func (b *CellBuffer) LoadCSV(data string) error {
+	r := csv.NewReader(strings.NewReader(data))
+	r.FieldsPerRecord = len(csvHeader)
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("goli: reading CSV header: %w", err)
+	}
+	if strings.Join(header, ",") != strings.Join(csvHeader, ",") {
+		return fmt.Errorf("goli: unexpected CSV header %q", strings.Join(header, ","))
+	}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("goli: reading CSV: %w", err)
+		}
+		x, y, cell, err := parseCellRecord(record)
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return fmt.Errorf("goli: CSV line %d: %w", line, err)
+		}
+		if !b.inBounds(x, y) {
+			line, _ := r.FieldPos(0)
+			return fmt.Errorf("goli: CSV line %d: cell (%d,%d) outside %dx%d buffer", line, x, y, b.width, b.height)
+		}
+		b.Set(x, y, cell)
+	}
+}
+
+func cellRecord(x, y int, cell Cell) []string {
+	s := cell.Style
+	char := ""
+	if cell.Char != 0 {
+		char = string(cell.Char)
+	}
+	return []string{
+		strconv.Itoa(x),
+		strconv.Itoa(y),
+		char,
+		formatCSVColor(s.Color, s.ColorRGB),
+		formatCSVColor(s.Background, s.BackgroundRGB),
+		strconv.FormatBool(s.Bold),
+		strconv.FormatBool(s.Italic),
+		strconv.FormatBool(s.Underline),
+		strconv.FormatBool(s.Dim),
+		strconv.FormatBool(s.Inverse),
+		strconv.FormatBool(s.Strikethrough),
+		s.HyperlinkURL,
+	}
+}
+
+func parseCellRecord(record []string) (x, y int, cell Cell, err error) {
+	if x, err = strconv.Atoi(record[0]); err != nil {
+		return 0, 0, cell, fmt.Errorf("invalid x: %w", err)
+	}
+	if y, err = strconv.Atoi(record[1]); err != nil {
+		return 0, 0, cell, fmt.Errorf("invalid y: %w", err)
+	}
+	if record[2] != "" {
+		r, size := utf8.DecodeRuneInString(record[2])
+		if size != len(record[2]) {
+			return 0, 0, cell, fmt.Errorf("char %q is not a single rune", record[2])
+		}
+		cell.Char = r
+	}
+
+	s := &cell.Style
+	if s.Color, s.ColorRGB, err = parseCSVColor(record[3]); err != nil {
+		return 0, 0, cell, err
+	}
+	if s.Background, s.BackgroundRGB, err = parseCSVColor(record[4]); err != nil {
+		return 0, 0, cell, err
+	}
+	flags := []*bool{&s.Bold, &s.Italic, &s.Underline, &s.Dim, &s.Inverse, &s.Strikethrough}
+	for i, flag := range flags {
+		if *flag, err = strconv.ParseBool(record[5+i]); err != nil {
+			return 0, 0, cell, fmt.Errorf("invalid %s: %w", csvHeader[5+i], err)
+		}
+	}
+	s.HyperlinkURL = record[11]
+	return x, y, cell, nil
+}
+
+func formatCSVColor(c Color, rgb *RGB) string {
+	if rgb != nil {
+		return fmt.Sprintf("#%02x%02x%02x", rgb.R, rgb.G, rgb.B)
+	}
+	if int(c) < len(colorNames) {
+		return colorNames[c]
+	}
+	return strconv.Itoa(int(c))
+}
+
+func parseCSVColor(s string) (Color, *RGB, error) {
+	if s == "" {
+		return ColorNone, nil, nil
+	}
+	if strings.HasPrefix(s, "#") {
+		var rgb RGB
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &rgb.R, &rgb.G, &rgb.B); err != nil || len(s) != 7 {
+			return ColorNone, nil, fmt.Errorf("invalid color %q", s)
+		}
+		return ColorNone, &rgb, nil
+	}
+	if c, ok := NameToColor[s]; ok {
+		return c, nil, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n < 256 {
+		return Color(n), nil, nil
+	}
+	return ColorNone, nil, fmt.Errorf("invalid color %q", s)
+}
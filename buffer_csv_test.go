@@ -0,0 +1,96 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+)
+
+func styledCSVBuffer() *CellBuffer {
+	buf := NewCellBuffer(6, 2)
+	buf.SetChar(0, 0, 'a', Style{Color: ColorRed, Bold: true})
+	buf.SetChar(3, 0, ',', Style{Background: ColorBrightBlue, Italic: true})
+	buf.SetChar(2, 1, '日', Style{ColorRGB: &RGB{R: 255, G: 128}, Underline: true, HyperlinkURL: "https://example.com"})
+	buf.Set(3, 1, Cell{})
+	return buf
+}
+
+func assertBuffersEqual(t *testing.T, want, got *CellBuffer) {
+	t.Helper()
+	for y := 0; y < want.Height(); y++ {
+		for x := 0; x < want.Width(); x++ {
+			if !got.Get(x, y).Equal(want.Get(x, y)) {
+				t.Errorf("cell (%d,%d): expected %+v, got %+v", x, y, want.Get(x, y), got.Get(x, y))
+			}
+		}
+	}
+}
+
+func TestCellBuffer_ToCSVChangedOnly(t *testing.T) {
+	buf := styledCSVBuffer()
+
+	csv := buf.ToCSV(CSVOptions{ChangedOnly: true})
+	want := strings.Join([]string{
+		"x,y,char,fgColor,bgColor,bold,italic,underline,dim,inverse,strikethrough,link",
+		"0,0,a,red,,true,false,false,false,false,false,",
+		`3,0,",",,brightBlue,false,true,false,false,false,false,`,
+		"2,1,日,#ff8000,,false,false,true,false,false,false,https://example.com",
+		"3,1,,,,false,false,false,false,false,false,",
+		"",
+	}, "\n")
+	if csv != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, csv)
+	}
+
+	loaded := NewCellBuffer(6, 2)
+	if err := loaded.LoadCSV(csv); err != nil {
+		t.Fatal(err)
+	}
+	assertBuffersEqual(t, buf, loaded)
+}
+
+func TestCellBuffer_ToCSVFrom(t *testing.T) {
+	prev := styledCSVBuffer()
+	buf := styledCSVBuffer()
+	buf.SetChar(5, 1, 'z', Style{Dim: true})
+
+	csv := buf.ToCSV(CSVOptions{ChangedOnly: true, From: prev})
+	if lines := strings.Split(strings.TrimSpace(csv), "\n"); len(lines) != 2 || lines[1] != "5,1,z,,,false,false,false,true,false,false," {
+		t.Errorf("expected only the changed cell, got\n%s", csv)
+	}
+}
+
+func TestCellBuffer_LoadCSVRoundTrip(t *testing.T) {
+	buf := styledCSVBuffer()
+	buf.WriteString(0, 1, " \"q\"", Style{Inverse: true, Strikethrough: true, Color: Color(200)})
+
+	loaded := NewCellBuffer(6, 2)
+	if err := loaded.LoadCSV(buf.ToCSV(CSVOptions{})); err != nil {
+		t.Fatal(err)
+	}
+	assertBuffersEqual(t, buf, loaded)
+	if loaded.ToCSV(CSVOptions{}) != buf.ToCSV(CSVOptions{}) {
+		t.Error("expected identical CSV after a round trip")
+	}
+}
+
+func TestCellBuffer_LoadCSVErrors(t *testing.T) {
+	header := "x,y,char,fgColor,bgColor,bold,italic,underline,dim,inverse,strikethrough,link\n"
+	tests := []struct {
+		name string
+		csv  string
+		want string
+	}{
+		{"empty", "", "header"},
+		{"wrong header", "x,y,char\n", "header"},
+		{"out of bounds", header + "9,0,a,,,false,false,false,false,false,false,\n", "outside"},
+		{"bad color", header + "0,0,a,mauve,,false,false,false,false,false,false,\n", "mauve"},
+		{"two runes", header + "0,0,ab,,,false,false,false,false,false,false,\n", "single rune"},
+		{"bad flag", header + "0,0,a,,,yes,false,false,false,false,false,\n", "bold"},
+	}
+	for _, tt := range tests {
+		err := NewCellBuffer(4, 1).LoadCSV(tt.csv)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error mentioning %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
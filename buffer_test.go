@@ -0,0 +1,306 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogicalBuffer_GetRegionAppendBufferRoundTrip(t *testing.T) {
+	src := NewLogicalBuffer(4)
+	src.WriteString(0, 0, "abcdef", Style{Color: ColorRed})
+	src.WriteString(0, 1, "ghijkl", Style{Bold: true})
+	src.WriteString(2, 2, "mn", Style{})
+
+	region := src.GetRegion(1, 0, 4, 3)
+	if region.Height() != 3 {
+		t.Fatalf("expected region height 3, got %d", region.Height())
+	}
+	if region.Get(0, 0).Char != 'b' || region.Get(3, 1).Char != 'k' || region.Get(1, 2).Char != 'm' {
+		t.Errorf("unexpected region contents: %q %q %q",
+			region.Get(0, 0).Char, region.Get(3, 1).Char, region.Get(1, 2).Char)
+	}
+
+	dst := NewLogicalBuffer(4)
+	dst.AppendBuffer(region, 1, 0)
+	for y := 0; y < 3; y++ {
+		for x := 1; x < 5; x++ {
+			if !dst.Get(x, y).Equal(src.Get(x, y)) {
+				t.Errorf("cell (%d,%d): expected %+v, got %+v", x, y, src.Get(x, y), dst.Get(x, y))
+			}
+		}
+	}
+}
+
+func TestLogicalBuffer_AppendBufferClipsNegativeOffsets(t *testing.T) {
+	src := NewLogicalBuffer(2)
+	src.WriteString(0, 0, "abc", Style{})
+	src.WriteString(0, 1, "def", Style{})
+
+	dst := NewLogicalBuffer(2)
+	dst.AppendBuffer(src, -1, -1)
+
+	if dst.Get(0, 0).Char != 'e' || dst.Get(1, 0).Char != 'f' {
+		t.Errorf("expected \"ef\" on row 0, got %q%q", dst.Get(0, 0).Char, dst.Get(1, 0).Char)
+	}
+	if dst.RowLength(1) != 0 {
+		t.Errorf("expected row 1 untouched, got length %d", dst.RowLength(1))
+	}
+}
+
+func TestLogicalBuffer_AppendBufferKeepsBackground(t *testing.T) {
+	dst := NewLogicalBuffer(1)
+	dst.WriteString(0, 0, "   ", Style{Background: ColorBlue})
+
+	src := NewLogicalBuffer(1)
+	src.Set(1, 0, New('x', Style{Color: ColorWhite}))
+	dst.AppendBuffer(src, 0, 0)
+
+	if dst.Get(0, 0).Style.Background != ColorBlue {
+		t.Error("empty source cells should not overwrite destination")
+	}
+	c := dst.Get(1, 0)
+	if c.Char != 'x' || c.Style.Background != ColorBlue || c.Style.Color != ColorWhite {
+		t.Errorf("expected merged cell, got %+v", c)
+	}
+}
+
+func TestLogicalBuffer_GetRegionOutOfBounds(t *testing.T) {
+	src := NewLogicalBuffer(1)
+	src.WriteString(0, 0, "ab", Style{})
+
+	region := src.GetRegion(-1, -1, 3, 3)
+	if region.Get(1, 1).Char != 'a' || region.Get(2, 1).Char != 'b' {
+		t.Errorf("expected \"ab\" at (1,1), got %q%q", region.Get(1, 1).Char, region.Get(2, 1).Char)
+	}
+	if region.RowLength(0) != 0 || region.RowLength(2) != 0 {
+		t.Error("rows outside the source should be empty")
+	}
+}
+
+func TestCellBuffer_ResizePreservesCells(t *testing.T) {
+	sizes := [][2]int{{6, 3}, {3, 2}, {8, 4}, {2, 5}, {4, 1}}
+	for _, size := range sizes {
+		buf := NewCellBuffer(4, 3)
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 4; x++ {
+				buf.SetChar(x, y, rune('a'+y*4+x), Style{})
+			}
+		}
+
+		w, h := size[0], size[1]
+		buf.Resize(w, h)
+		if buf.Width() != w || buf.Height() != h {
+			t.Fatalf("expected %dx%d, got %dx%d", w, h, buf.Width(), buf.Height())
+		}
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				want := EmptyCell
+				if x < 4 && y < 3 {
+					want = New(rune('a'+y*4+x), Style{})
+				}
+				if got := buf.Get(x, y); !got.Equal(want) {
+					t.Errorf("resize to %dx%d: cell (%d,%d) = %q, want %q", w, h, x, y, got.Char, want.Char)
+				}
+			}
+		}
+	}
+}
+
+func TestCellBuffer_ResizeReusesBackingArray(t *testing.T) {
+	buf := NewCellBuffer(80, 24)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Resize(60, 20)
+		buf.Resize(80, 24)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations when shrinking and regrowing, got %v", allocs)
+	}
+}
+
+func TestLogicalBuffer_Resize(t *testing.T) {
+	buf := NewLogicalBuffer(3)
+	buf.WriteString(0, 0, "keep", Style{})
+	buf.WriteString(0, 2, "drop", Style{})
+
+	buf.Resize(2)
+	if buf.Height() != 2 || buf.Get(0, 0).Char != 'k' {
+		t.Errorf("expected row 0 preserved after shrink, got height %d", buf.Height())
+	}
+
+	buf.Resize(4)
+	if buf.Height() != 4 {
+		t.Errorf("expected height 4, got %d", buf.Height())
+	}
+	if buf.RowLength(2) != 0 {
+		t.Error("expected regrown rows to be empty")
+	}
+}
+
+func BenchmarkRendererResize(b *testing.B) {
+	r := NewRenderer(Options{Width: 80, Height: 24, Output: &strings.Builder{}})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 100; j++ {
+			r.Resize(60+j%20, 20+j%4)
+		}
+	}
+}
+
+func TestCellBuffer_WriteSegments(t *testing.T) {
+	green := Style{Color: ColorGreen}
+	white := Style{Color: ColorWhite}
+	segments := []AnsiSegment{{Text: "hello", Style: green}, {Text: " world", Style: white}}
+
+	buf := NewCellBuffer(20, 1)
+	if n := buf.WriteSegments(2, 0, segments); n != 11 {
+		t.Errorf("expected 11 columns advanced, got %d", n)
+	}
+	if c := buf.Get(2, 0); c.Char != 'h' || !c.Style.Equal(green) {
+		t.Errorf("expected green 'h' at 2, got %q %+v", c.Char, c.Style)
+	}
+	if c := buf.Get(8, 0); c.Char != 'w' || !c.Style.Equal(white) {
+		t.Errorf("expected white 'w' at 8, got %q %+v", c.Char, c.Style)
+	}
+
+	wide := NewCellBuffer(10, 1)
+	if n := wide.WriteSegments(0, 0, []AnsiSegment{{Text: "日本", Style: green}}); n != 4 {
+		t.Errorf("expected wide runes to advance 4 columns, got %d", n)
+	}
+}
+
+func TestLogicalBuffer_WriteSegments(t *testing.T) {
+	green := Style{Color: ColorGreen}
+	white := Style{Color: ColorWhite}
+	segments := []AnsiSegment{{Text: "hello", Style: green}, {Text: " world", Style: white}}
+
+	buf := NewLogicalBuffer(1)
+	if n := buf.WriteSegments(0, 0, segments); n != 11 {
+		t.Errorf("expected 11 columns advanced, got %d", n)
+	}
+	if c := buf.Get(4, 0); c.Char != 'o' || !c.Style.Equal(green) {
+		t.Errorf("expected green 'o' at 4, got %q %+v", c.Char, c.Style)
+	}
+	if c := buf.Get(10, 0); c.Char != 'd' || !c.Style.Equal(white) {
+		t.Errorf("expected white 'd' at 10, got %q %+v", c.Char, c.Style)
+	}
+}
+
+func TestLogicalBuffer_GetRowText(t *testing.T) {
+	lb := NewLogicalBuffer(2)
+	lb.WriteString(2, 1, "héllo", Style{})
+	lb.Set(7, 1, Cell{})
+
+	if got := lb.GetRowText(1); got != "  héllo" {
+		t.Errorf("expected %q, got %q", "  héllo", got)
+	}
+	if got := lb.GetRowText(0); got != "" {
+		t.Errorf("expected empty row, got %q", got)
+	}
+	if got := lb.GetRowText(5); got != "" {
+		t.Errorf("expected empty string out of bounds, got %q", got)
+	}
+}
+
+func TestLogicalBuffer_FindText(t *testing.T) {
+	lb := NewLogicalBuffer(3)
+	lb.WriteString(0, 0, "first", Style{})
+	lb.WriteString(4, 2, "ünï needle", Style{})
+
+	if x, y, found := lb.FindText("needle"); !found || x != 8 || y != 2 {
+		t.Errorf("expected needle at (8,2), got (%d,%d) found=%v", x, y, found)
+	}
+	if _, _, found := lb.FindText("missing"); found {
+		t.Error("expected missing text not to be found")
+	}
+}
+
+func TestLogicalBuffer_FindTextAll(t *testing.T) {
+	lb := NewLogicalBuffer(3)
+	lb.WriteString(0, 0, "aaa", Style{})
+	lb.WriteString(2, 1, "ab", Style{Bold: true})
+	lb.WriteString(0, 2, "b", Style{})
+
+	tests := []struct {
+		name string
+		text string
+		want []TextMatch
+	}{
+		{"overlapping", "aa", []TextMatch{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+		{"multiple rows", "a", []TextMatch{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1, Style: Style{Bold: true}}}},
+		{"no span across rows", "abb", nil},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		got := lb.FindTextAll(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i].X != tt.want[i].X || got[i].Y != tt.want[i].Y || !got[i].Style.Equal(tt.want[i].Style) {
+				t.Errorf("%s: match %d expected %+v, got %+v", tt.name, i, tt.want[i], got[i])
+			}
+		}
+	}
+
+	if _, _, found := lb.FindText(""); found {
+		t.Error("expected empty text not to be found")
+	}
+}
+
+func TestCellBuffer_FindText(t *testing.T) {
+	buf := NewCellBuffer(10, 2)
+	buf.WriteString(0, 0, "日本 text", Style{})
+	buf.WriteString(3, 1, "text", Style{Color: ColorRed})
+
+	if x, y, found := buf.FindText("text"); !found || x != 3 || y != 0 {
+		t.Errorf("expected text at (3,0), got (%d,%d) found=%v", x, y, found)
+	}
+	matches := buf.FindTextAll("text")
+	if len(matches) != 2 || matches[1].X != 3 || matches[1].Y != 1 || matches[1].Style.Color != ColorRed {
+		t.Errorf("expected a red match at (3,1), got %+v", matches)
+	}
+	if _, _, found := buf.FindText("missing"); found {
+		t.Error("expected missing text not to be found")
+	}
+}
+
+func TestLogicalBuffer_SetBeyondMaxHeightIgnored(t *testing.T) {
+	buf := NewLogicalBufferCapped(1, 5)
+	buf.Set(0, 4, New('a', Style{}))
+	buf.Set(0, 5, New('b', Style{}))
+	buf.SetMerge(0, 100, New('c', Style{}))
+
+	if buf.Height() != 5 {
+		t.Errorf("expected height 5, got %d", buf.Height())
+	}
+	if buf.Get(0, 4).Char != 'a' {
+		t.Errorf("expected 'a' at row 4, got %q", buf.Get(0, 4).Char)
+	}
+}
+
+func TestLogicalBuffer_SetMaxHeight(t *testing.T) {
+	buf := NewLogicalBuffer(1)
+	if buf.MaxHeight() != MaxBufferHeight {
+		t.Errorf("expected default max height %d, got %d", MaxBufferHeight, buf.MaxHeight())
+	}
+
+	buf.SetMaxHeight(3)
+	buf.Set(0, 3, New('x', Style{}))
+	if buf.Height() != 1 {
+		t.Errorf("expected height 1 after ignored write, got %d", buf.Height())
+	}
+
+	buf.SetMaxHeight(0)
+	buf.Set(0, 3, New('x', Style{}))
+	if buf.Height() != 4 {
+		t.Errorf("expected height 4 after restoring the default, got %d", buf.Height())
+	}
+
+	var zero LogicalBuffer
+	zero.Set(0, 0, New('z', Style{}))
+	if zero.Get(0, 0).Char != 'z' {
+		t.Error("expected zero-value buffer to use the default max height")
+	}
+}
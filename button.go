@@ -72,6 +72,10 @@ type ButtonOptions struct {
 	OnKeypress func(key string) bool
 	// DisableFocus disables focus management registration (default: false, meaning focusable by default).
 	DisableFocus bool
+	// OnFocus is called when the button gains focus.
+	OnFocus func()
+	// OnBlur is called when the button loses focus.
+	OnBlur func()
 }
 
 // Button represents a clickable button component.
@@ -81,6 +85,8 @@ type Button struct {
 
 	onClick        func()
 	onKeypress     func(key string) bool
+	onFocus        func()
+	onBlur         func()
 	shouldRegister bool
 	registered     bool
 }
@@ -99,6 +105,8 @@ func NewButton(opts ButtonOptions) *Button {
 		setFocused:     setFocused,
 		onClick:        opts.OnClick,
 		onKeypress:     opts.OnKeypress,
+		onFocus:        opts.OnFocus,
+		onBlur:         opts.OnBlur,
 		shouldRegister: shouldRegister,
 	}
 
@@ -127,7 +135,9 @@ func (b *Button) Blur() {
 
 // SetFocused sets the focused state (called by focus manager).
 func (b *Button) SetFocused(f bool) {
+	wasFocused := Untrack(func() bool { return b.focused() })
 	b.setFocused(f)
+	notifyFocusChange(wasFocused, f, b.onFocus, b.onBlur)
 }
 
 // Dispose unregisters from the focus manager.
@@ -173,7 +183,7 @@ func (b *Button) Click() {
 
 // Button measure/layout/render functions
 
-func measureButton(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureButton(node gox.VNode, ctx LayoutContext) (int, int) {
 	padding := GetSpacing(node.Props, "padding")
 
 	// Measure children content (typically just text)
@@ -223,7 +233,7 @@ func layoutButton(node gox.VNode, availWidth, availHeight int, ctx *LayoutContex
 	margin := GetSpacing(node.Props, "margin")
 
 	// Calculate button dimensions
-	measuredW, measuredH := measureButton(node, nil)
+	measuredW, measuredH := measureButton(node, LayoutContext{})
 	buttonWidth := GetIntProp(node.Props, "width", -1)
 	if buttonWidth < 0 {
 		buttonWidth = min(measuredW, availWidth-margin.Left-margin.Right)
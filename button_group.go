@@ -0,0 +1,102 @@
+package goli
+
+import "github.com/germtb/gox"
+
+// ButtonGroupOption is one button of a ButtonGroup.
+type ButtonGroupOption struct {
+	Label string
+	Value string
+	// OnClick is called after the button becomes the active one.
+	OnClick func()
+}
+
+// ButtonGroup is a row of mutually exclusive toggle buttons, like radio
+// buttons: activating one (Enter/Space or Click) makes it the only active
+// button. Each button is focusable on its own.
+type ButtonGroup struct {
+	options   []ButtonGroupOption
+	buttons   []*Button
+	active    Accessor[string]
+	setActive Setter[string]
+
+	// ActiveStyle is merged into the active button's style, under the
+	// focused style (default: bold and underlined).
+	ActiveStyle Style
+}
+
+// NewButtonGroup creates one button per option. No button is active until
+// one is clicked or SetActive is called.
+//
+// Example:
+//
+//	group := goli.NewButtonGroup([]goli.ButtonGroupOption{
+//	    {Label: "List", Value: "list"},
+//	    {Label: "Grid", Value: "grid", OnClick: showGrid},
+//	})
+//	group.SetActive("list")
+//
+//	// In the app's VNode tree:
+//	group.VNode()
+func NewButtonGroup(options []ButtonGroupOption) *ButtonGroup {
+	active, setActive := CreateSignal("")
+	g := &ButtonGroup{
+		options:     options,
+		buttons:     make([]*Button, len(options)),
+		active:      active,
+		setActive:   setActive,
+		ActiveStyle: Style{Bold: true, Underline: true},
+	}
+	for i, opt := range options {
+		g.buttons[i] = NewButton(ButtonOptions{
+			OnClick: func() {
+				g.setActive(opt.Value)
+				if opt.OnClick != nil {
+					opt.OnClick()
+				}
+			},
+		})
+	}
+	return g
+}
+
+// ActiveValue returns the value of the active button, or "" if none is
+// active.
+func (g *ButtonGroup) ActiveValue() string {
+	return g.active()
+}
+
+// SetActive makes the button with value the active one without calling
+// its OnClick. An unknown value leaves no button active.
+func (g *ButtonGroup) SetActive(value string) {
+	g.setActive(value)
+}
+
+// Buttons returns the group's buttons, in option order.
+func (g *ButtonGroup) Buttons() []*Button {
+	return g.buttons
+}
+
+// Dispose unregisters the group's buttons from the focus manager.
+func (g *ButtonGroup) Dispose() {
+	for _, b := range g.buttons {
+		b.Dispose()
+	}
+}
+
+// VNode renders the buttons in a row, one column apart.
+func (g *ButtonGroup) VNode() gox.VNode {
+	active := g.ActiveValue()
+	buttons := make([]gox.VNode, len(g.options))
+	for i, opt := range g.options {
+		props := gox.Props{
+			"button":       g.buttons[i],
+			"paddingLeft":  1,
+			"paddingRight": 1,
+		}
+		if opt.Value == active {
+			props["style"] = g.ActiveStyle
+		}
+		buttons[i] = gox.Element("button", props, gox.Element("text", nil, gox.Text(opt.Label)))
+	}
+	return gox.Element("box", gox.Props{"direction": "row", "gap": 1}, buttons...)
+}
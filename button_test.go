@@ -2,6 +2,9 @@ package goli
 
 import (
 	"testing"
+	"time"
+
+	"github.com/germtb/gox"
 )
 
 func TestButtonCreation(t *testing.T) {
@@ -208,3 +211,102 @@ func TestButtonBlur(t *testing.T) {
 		t.Error("Button should not be focused after Blur()")
 	}
 }
+
+func TestButtonRendersLabel(t *testing.T) {
+	Manager().Clear()
+
+	btn := NewButton(ButtonOptions{})
+	defer btn.Dispose()
+
+	app := Render(func() gox.VNode {
+		return gox.Element("button", gox.Props{"button": btn},
+			gox.Element("text", nil, gox.Text("Submit")))
+	}, Options{Width: 20, Height: 3, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	h := app.Headless()
+	h.AssertContains(t, "Submit")
+	h.AssertCell(t, 0, 0, 'S', Style{})
+
+	btn.Focus()
+	app.Rerender()
+	h.AssertCell(t, 0, 0, 'S', Style{Inverse: true})
+}
+
+func TestButtonLabelInLogicalBuffer(t *testing.T) {
+	Manager().Clear()
+
+	ok := NewButton(ButtonOptions{})
+	cancel := NewButton(ButtonOptions{})
+	defer ok.Dispose()
+	defer cancel.Dispose()
+
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{"direction": "row", "gap": 2},
+			gox.Element("button", gox.Props{"button": ok}, gox.Element("text", nil, gox.Text("OK"))),
+			gox.Element("button", gox.Props{"button": cancel}, gox.Element("text", nil, gox.Text("Cancel"))),
+		)
+	}, Options{Width: 20, Height: 3, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	lb := app.Headless().CurrentLogicalBuffer()
+	if x, y, found := lb.FindText("Cancel"); !found || x != 4 || y != 0 {
+		t.Errorf("expected Cancel at (4,0), got (%d,%d) found=%v", x, y, found)
+	}
+}
+
+func TestButtonGroup_ClickActivatesOnlyOne(t *testing.T) {
+	setupTest(t)
+
+	var clicks []string
+	group := NewButtonGroup([]ButtonGroupOption{
+		{Label: "A", Value: "a", OnClick: func() { clicks = append(clicks, "a") }},
+		{Label: "B", Value: "b", OnClick: func() { clicks = append(clicks, "b") }},
+		{Label: "C", Value: "c"},
+	})
+	defer group.Dispose()
+
+	if group.ActiveValue() != "" {
+		t.Errorf("expected no active button initially, got %q", group.ActiveValue())
+	}
+
+	app := Render(group.VNode, Options{Width: 20, Height: 1, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	// Buttons are " A ", " B ", " C " one column apart
+	labelX := map[string]int{"a": 1, "b": 5, "c": 9}
+	assertActive := func(want string) {
+		t.Helper()
+		if group.ActiveValue() != want {
+			t.Errorf("expected %q active, got %q", want, group.ActiveValue())
+		}
+		for value, x := range labelX {
+			if bold := app.Headless().GetCell(x, 0).Style.Bold; bold != (value == want) {
+				t.Errorf("button %q: expected bold=%v, got %v", value, value == want, bold)
+			}
+		}
+	}
+
+	group.Buttons()[1].Click()
+	app.WaitForRender(time.Second)
+	assertActive("b")
+
+	group.Buttons()[0].Focus()
+	HandleKey(Enter)
+	app.WaitForRender(time.Second)
+	assertActive("a")
+	if cell := app.Headless().GetCell(labelX["a"], 0); !cell.Style.Inverse || !cell.Style.Bold {
+		t.Errorf("expected the focused active button to be inverse and bold, got %+v", cell.Style)
+	}
+
+	if len(clicks) != 2 || clicks[0] != "b" || clicks[1] != "a" {
+		t.Errorf("expected OnClick for b then a, got %v", clicks)
+	}
+
+	group.SetActive("c")
+	app.WaitForRender(time.Second)
+	assertActive("c")
+	if len(clicks) != 2 {
+		t.Errorf("expected SetActive not to call OnClick, got %v", clicks)
+	}
+}
@@ -148,11 +148,11 @@ func (s Style) HasBackground() bool {
 func (base Style) Merge(overlay Style) Style {
 	result := base
 
-	if overlay.Color != ColorNone {
+	if overlay.HasColor() {
 		result.Color = overlay.Color
 		result.ColorRGB = overlay.ColorRGB
 	}
-	if overlay.Background != ColorNone {
+	if overlay.HasBackground() {
 		result.Background = overlay.Background
 		result.BackgroundRGB = overlay.BackgroundRGB
 	}
@@ -180,3 +180,88 @@ func (base Style) Merge(overlay Style) Style {
 
 	return result
 }
+
+// colorPalette maps named colors to the xterm default palette.
+var colorPalette = [ColorBrightWhite + 1]RGB{
+	ColorBlack:         {0, 0, 0},
+	ColorRed:           {205, 0, 0},
+	ColorGreen:         {0, 205, 0},
+	ColorYellow:        {205, 205, 0},
+	ColorBlue:          {0, 0, 238},
+	ColorMagenta:       {205, 0, 205},
+	ColorCyan:          {0, 205, 205},
+	ColorWhite:         {229, 229, 229},
+	ColorBrightBlack:   {127, 127, 127},
+	ColorBrightRed:     {255, 0, 0},
+	ColorBrightGreen:   {0, 255, 0},
+	ColorBrightYellow:  {255, 255, 0},
+	ColorBrightBlue:    {92, 92, 255},
+	ColorBrightMagenta: {255, 0, 255},
+	ColorBrightCyan:    {0, 255, 255},
+	ColorBrightWhite:   {255, 255, 255},
+}
+
+// ColorToRGB returns the RGB value of a named color in the xterm default
+// palette. ColorNone and ColorDefault map to black.
+func ColorToRGB(c Color) RGB {
+	if int(c) < len(colorPalette) {
+		return colorPalette[c]
+	}
+	return RGB{}
+}
+
+// LerpColor interpolates the foreground and background colors of s towards
+// other in RGB space, with t in [0, 1]. Interpolated colors are returned as
+// ColorRGB/BackgroundRGB with Color/Background set to ColorNone. Other
+// attributes come from s. t <= 0 returns s and t >= 1 returns other.
+// A color that is unset on either side is not interpolated and keeps s's value.
+func (s Style) LerpColor(other Style, t float64) Style {
+	if t <= 0 {
+		return s
+	}
+	if t >= 1 {
+		return other
+	}
+
+	result := s
+	if a, ok := s.foregroundRGB(); ok {
+		if b, ok := other.foregroundRGB(); ok {
+			c := lerpRGB(a, b, t)
+			result.Color, result.ColorRGB = ColorNone, &c
+		}
+	}
+	if a, ok := s.backgroundRGB(); ok {
+		if b, ok := other.backgroundRGB(); ok {
+			c := lerpRGB(a, b, t)
+			result.Background, result.BackgroundRGB = ColorNone, &c
+		}
+	}
+	return result
+}
+
+// foregroundRGB resolves the foreground color to RGB, if one is set.
+func (s Style) foregroundRGB() (RGB, bool) {
+	return resolveRGB(s.Color, s.ColorRGB)
+}
+
+// backgroundRGB resolves the background color to RGB, if one is set.
+func (s Style) backgroundRGB() (RGB, bool) {
+	return resolveRGB(s.Background, s.BackgroundRGB)
+}
+
+func resolveRGB(c Color, rgb *RGB) (RGB, bool) {
+	if rgb != nil {
+		return *rgb, true
+	}
+	if c == ColorNone || c == ColorDefault {
+		return RGB{}, false
+	}
+	return ColorToRGB(c), true
+}
+
+func lerpRGB(a, b RGB, t float64) RGB {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + t*(float64(y)-float64(x)))
+	}
+	return RGB{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B)}
+}
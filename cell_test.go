@@ -0,0 +1,96 @@
+package goli
+
+import (
+	"testing"
+)
+
+func TestStyle_LerpColorMidpoint(t *testing.T) {
+	black := Style{Color: ColorBlack}
+	white := Style{Color: ColorBrightWhite}
+
+	mid := black.LerpColor(white, 0.5)
+	if mid.Color != ColorNone || mid.ColorRGB == nil {
+		t.Fatalf("expected RGB color, got %+v", mid)
+	}
+	if got := *mid.ColorRGB; got != (RGB{127, 127, 127}) {
+		t.Errorf("expected ~{128 128 128}, got %+v", got)
+	}
+}
+
+func TestStyle_LerpColorEndpoints(t *testing.T) {
+	from := Style{Color: ColorRed, Background: ColorBlack, Bold: true}
+	to := Style{ColorRGB: &RGB{0, 0, 255}, Background: ColorWhite}
+
+	if got := from.LerpColor(to, 0); !got.Equal(from) {
+		t.Errorf("expected t=0 to return the start style, got %+v", got)
+	}
+	if got := from.LerpColor(to, 1); !got.Equal(to) {
+		t.Errorf("expected t=1 to return the end style, got %+v", got)
+	}
+}
+
+func TestStyle_LerpColorBackgroundAndRGB(t *testing.T) {
+	from := Style{ColorRGB: &RGB{0, 100, 200}, Background: ColorBlack, Bold: true}
+	to := Style{ColorRGB: &RGB{100, 0, 200}, Background: ColorWhite}
+
+	got := from.LerpColor(to, 0.25)
+	if *got.ColorRGB != (RGB{25, 75, 200}) {
+		t.Errorf("expected {25 75 200}, got %+v", *got.ColorRGB)
+	}
+	if got.Background != ColorNone || *got.BackgroundRGB != (RGB{57, 57, 57}) {
+		t.Errorf("expected background {57 57 57}, got %+v", got.BackgroundRGB)
+	}
+	if !got.Bold {
+		t.Error("expected non-color attributes from the start style")
+	}
+}
+
+func TestStyle_LerpColorUnsetNotInterpolated(t *testing.T) {
+	from := Style{Color: ColorRed}
+	to := Style{}
+
+	if got := from.LerpColor(to, 0.5); got.Color != ColorRed || got.ColorRGB != nil {
+		t.Errorf("expected unset end color to leave the start color, got %+v", got)
+	}
+}
+
+func TestColorToRGB(t *testing.T) {
+	if ColorToRGB(ColorBrightWhite) != (RGB{255, 255, 255}) {
+		t.Errorf("expected bright white to be {255 255 255}, got %+v", ColorToRGB(ColorBrightWhite))
+	}
+	if ColorToRGB(ColorNone) != (RGB{}) {
+		t.Errorf("expected ColorNone to map to black, got %+v", ColorToRGB(ColorNone))
+	}
+}
+
+func TestStyle_EqualComparesRGBByValue(t *testing.T) {
+	a := Style{ColorRGB: &RGB{1, 2, 3}, BackgroundRGB: &RGB{4, 5, 6}}
+	b := Style{ColorRGB: &RGB{1, 2, 3}, BackgroundRGB: &RGB{4, 5, 6}}
+	if !a.Equal(b) {
+		t.Errorf("expected styles with equal RGB values to be equal")
+	}
+
+	// An explicit RGB black is not the same as the palette black
+	if (Style{ColorRGB: &RGB{0, 0, 0}}).Equal(Style{Color: ColorBlack}) {
+		t.Errorf("expected RGB black and ColorBlack to differ")
+	}
+	if (Style{BackgroundRGB: &RGB{0, 0, 0}}).Equal(Style{}) {
+		t.Errorf("expected RGB background and no background to differ")
+	}
+}
+
+func TestStyle_MergeKeepsUnsetFields(t *testing.T) {
+	got := Style{Bold: true, Background: ColorBlue}.Merge(Style{Color: ColorRed})
+	want := Style{Bold: true, Color: ColorRed, Background: ColorBlue}
+	if !got.Equal(want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestStyle_MergeRGBOverlay(t *testing.T) {
+	got := Style{Color: ColorRed, Italic: true}.Merge(Style{ColorRGB: &RGB{1, 2, 3}, BackgroundRGB: &RGB{4, 5, 6}})
+	want := Style{Italic: true, ColorRGB: &RGB{1, 2, 3}, BackgroundRGB: &RGB{4, 5, 6}}
+	if !got.Equal(want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
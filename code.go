@@ -0,0 +1,283 @@
+package goli
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/germtb/gox"
+)
+
+// TokenType classifies a span of source code for syntax highlighting.
+type TokenType int
+
+const (
+	TokenText TokenType = iota // Whitespace and anything unclassified
+	TokenKeyword
+	TokenName     // Identifiers
+	TokenFunction // Identifiers directly followed by "("
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenOperator
+	TokenPunctuation
+)
+
+// Token is a span of source code and its type.
+type Token struct {
+	Type TokenType
+	Text string
+}
+
+// Tokenizer splits source code into tokens. The token texts, concatenated,
+// must reproduce the source exactly.
+//
+// Full lexers (e.g. chroma) can be plugged in by wrapping them in a Tokenizer
+// and calling RegisterTokenizer; goli itself only ships a small lexer for Go.
+type Tokenizer interface {
+	Tokenize(source string) []Token
+}
+
+// TokenizerFunc adapts an ordinary function to the Tokenizer interface.
+type TokenizerFunc func(source string) []Token
+
+// Tokenize calls f(source).
+func (f TokenizerFunc) Tokenize(source string) []Token {
+	return f(source)
+}
+
+var (
+	tokenizersMu sync.RWMutex
+	tokenizers   = map[string]Tokenizer{}
+)
+
+// RegisterTokenizer sets the tokenizer used by <code language={language}>.
+// Registering a language again replaces its tokenizer; a nil tokenizer
+// removes it.
+func RegisterTokenizer(language string, t Tokenizer) {
+	tokenizersMu.Lock()
+	defer tokenizersMu.Unlock()
+	if t == nil {
+		delete(tokenizers, strings.ToLower(language))
+		return
+	}
+	tokenizers[strings.ToLower(language)] = t
+}
+
+func getTokenizer(language string) Tokenizer {
+	tokenizersMu.RLock()
+	defer tokenizersMu.RUnlock()
+	return tokenizers[strings.ToLower(language)]
+}
+
+// NewKeywordTokenizer returns a tokenizer for C-like languages: "//" and
+// "/* */" comments, double-, single- and back-quoted strings, numbers,
+// identifiers (those listed in keywords become TokenKeyword) and operators.
+func NewKeywordTokenizer(keywords ...string) Tokenizer {
+	set := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		set[k] = true
+	}
+	return TokenizerFunc(func(source string) []Token {
+		return tokenizeCLike(source, set)
+	})
+}
+
+func tokenizeCLike(src string, keywords map[string]bool) []Token {
+	var tokens []Token
+	emit := func(typ TokenType, text string) {
+		// Merge adjacent tokens of the same type to keep the output small
+		if n := len(tokens); n > 0 && tokens[n-1].Type == typ {
+			tokens[n-1].Text += text
+			return
+		}
+		tokens = append(tokens, Token{Type: typ, Text: text})
+	}
+
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := i
+
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end
+			emit(TokenComment, src[start:i])
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += 2 + end + 2
+			}
+			emit(TokenComment, src[start:i])
+
+		case r == '"' || r == '\'' || r == '`':
+			i++
+			for i < len(src) {
+				c := src[i]
+				if c == '\\' && r != '`' && i+1 < len(src) {
+					i += 2
+					continue
+				}
+				i++
+				if c == byte(r) || (c == '\n' && r != '`') {
+					break
+				}
+			}
+			emit(TokenString, src[start:i])
+
+		case unicode.IsDigit(r):
+			for i < len(src) {
+				c, n := utf8.DecodeRuneInString(src[i:])
+				if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '.' && c != '_' {
+					break
+				}
+				i += n
+			}
+			emit(TokenNumber, src[start:i])
+
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(src) {
+				c, n := utf8.DecodeRuneInString(src[i:])
+				if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+					break
+				}
+				i += n
+			}
+			word := src[start:i]
+			switch {
+			case keywords[word]:
+				emit(TokenKeyword, word)
+			case i < len(src) && src[i] == '(':
+				emit(TokenFunction, word)
+			default:
+				emit(TokenName, word)
+			}
+
+		case strings.ContainsRune("(){}[];,.", r):
+			i += size
+			emit(TokenPunctuation, src[start:i])
+
+		case strings.ContainsRune("+-*/%&|^<>=!:~", r):
+			i += size
+			emit(TokenOperator, src[start:i])
+
+		default:
+			i += size
+			emit(TokenText, src[start:i])
+		}
+	}
+	return tokens
+}
+
+func init() {
+	RegisterTokenizer("go", NewKeywordTokenizer(
+		"break", "case", "chan", "const", "continue", "default", "defer",
+		"else", "fallthrough", "for", "func", "go", "goto", "if", "import",
+		"interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var",
+		"true", "false", "nil", "iota",
+	))
+}
+
+var codeThemes = map[string]map[TokenType]Style{
+	"monokai": {
+		TokenKeyword:     {ColorRGB: &RGB{0xF9, 0x26, 0x72}},
+		TokenName:        {ColorRGB: &RGB{0xF8, 0xF8, 0xF2}},
+		TokenFunction:    {ColorRGB: &RGB{0xA6, 0xE2, 0x2E}},
+		TokenString:      {ColorRGB: &RGB{0xE6, 0xDB, 0x74}},
+		TokenNumber:      {ColorRGB: &RGB{0xAE, 0x81, 0xFF}},
+		TokenComment:     {ColorRGB: &RGB{0x75, 0x71, 0x5E}, Italic: true},
+		TokenOperator:    {ColorRGB: &RGB{0xF9, 0x26, 0x72}},
+		TokenPunctuation: {ColorRGB: &RGB{0xC0, 0xC0, 0xB8}},
+	},
+	"github": {
+		TokenKeyword:     {ColorRGB: &RGB{0xD7, 0x3A, 0x49}},
+		TokenName:        {ColorRGB: &RGB{0x24, 0x29, 0x2E}},
+		TokenFunction:    {ColorRGB: &RGB{0x6F, 0x42, 0xC1}},
+		TokenString:      {ColorRGB: &RGB{0x03, 0x2F, 0x62}},
+		TokenNumber:      {ColorRGB: &RGB{0x00, 0x5C, 0xC5}},
+		TokenComment:     {ColorRGB: &RGB{0x6A, 0x73, 0x7D}, Italic: true},
+		TokenOperator:    {ColorRGB: &RGB{0xD7, 0x3A, 0x49}},
+		TokenPunctuation: {ColorRGB: &RGB{0x58, 0x60, 0x69}},
+	},
+}
+
+// CodeThemeToStyleMap returns the token styles for a <code> theme
+// ("monokai" or "github"). Unknown themes fall back to monokai.
+// Token types missing from the map are drawn in the element's own style.
+func CodeThemeToStyleMap(theme string) map[TokenType]Style {
+	styles, ok := codeThemes[strings.ToLower(theme)]
+	if !ok {
+		styles = codeThemes["monokai"]
+	}
+	out := make(map[TokenType]Style, len(styles))
+	for k, v := range styles {
+		out[k] = v
+	}
+	return out
+}
+
+// HighlightCode returns source with ANSI styling for the given language and
+// theme. Languages without a registered tokenizer are returned unchanged.
+func HighlightCode(source, language, theme string) string {
+	tokenizer := getTokenizer(language)
+	if tokenizer == nil {
+		return source
+	}
+	styles := codeThemes[strings.ToLower(theme)]
+	if styles == nil {
+		styles = codeThemes["monokai"]
+	}
+
+	var sb strings.Builder
+	for _, tok := range tokenizer.Tokenize(source) {
+		style, ok := styles[tok.Type]
+		if !ok {
+			sb.WriteString(tok.Text)
+			continue
+		}
+		// Style each line separately: the ansi element parses line by line,
+		// so a multi-line comment or raw string must restate its style.
+		for j, line := range strings.Split(tok.Text, "\n") {
+			if j > 0 {
+				sb.WriteByte('\n')
+			}
+			if line == "" {
+				continue
+			}
+			StyleToAnsi(style, &sb)
+			sb.WriteString(line)
+			sb.WriteString(resetStr)
+		}
+	}
+	return sb.String()
+}
+
+func init() {
+	RegisterIntrinsic("code", &IntrinsicHandler{
+		Measure:       measureAnsi, // Styling adds no width
+		Layout:        layoutCode,
+		Render:        renderAnsi,
+		RenderLogical: renderAnsiLogical,
+	})
+}
+
+// layoutCode highlights the source and lays it out as an ansi element.
+func layoutCode(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
+	source := CollectTextContent(node)
+	highlighted := HighlightCode(source, GetStringProp(node.Props, "language", ""), GetStringProp(node.Props, "theme", "monokai"))
+	ansiNode := gox.VNode{
+		Type:     "ansi",
+		Props:    node.Props,
+		Children: []gox.VNode{CreateTextNode(highlighted)},
+	}
+	return layoutAnsi(ansiNode, availWidth, availHeight, ctx)
+}
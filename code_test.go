@@ -0,0 +1,94 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/germtb/gox"
+)
+
+func codeNode(props gox.Props, source string) gox.VNode {
+	return gox.VNode{
+		Type:     "code",
+		Props:    props,
+		Children: []gox.VNode{CreateTextNode(source)},
+	}
+}
+
+func renderCodeToBuffer(node gox.VNode, width, height int) *CellBuffer {
+	buf := NewCellBuffer(width, height)
+	box := ComputeLayout(node, LayoutContext{Width: width, Height: height}).Tree()
+	RenderToBuffer(box, buf, nil)
+	return buf
+}
+
+func TestCodeElement_GoHighlighting(t *testing.T) {
+	buf := renderCodeToBuffer(codeNode(gox.Props{"language": "go"}, "func main() {}"), 20, 1)
+
+	if got := strings.TrimRight(bufferToPlainLines(buf, 0, true), "\n"); got != "func main() {}" {
+		t.Fatalf("plain text = %q, want %q", got, "func main() {}")
+	}
+
+	theme := CodeThemeToStyleMap("monokai")
+	checks := []struct {
+		x    int
+		want TokenType
+	}{
+		{0, TokenKeyword},      // func
+		{5, TokenFunction},     // main
+		{9, TokenPunctuation},  // (
+		{12, TokenPunctuation}, // {
+	}
+	for _, c := range checks {
+		got := buf.Get(c.x, 0).Style.ColorRGB
+		want := theme[c.want].ColorRGB
+		if got == nil || *got != *want {
+			t.Errorf("cell %d color = %v, want %v", c.x, got, *want)
+		}
+	}
+
+	kw, fn, punct := *theme[TokenKeyword].ColorRGB, *theme[TokenFunction].ColorRGB, *theme[TokenPunctuation].ColorRGB
+	if kw == fn || fn == punct || kw == punct {
+		t.Error("keywords, identifiers and punctuation should have distinct styles")
+	}
+}
+
+func TestCodeElement_UnknownLanguageIsPlain(t *testing.T) {
+	buf := renderCodeToBuffer(codeNode(gox.Props{"language": "cobol"}, "MOVE A TO B"), 20, 1)
+	for x := 0; x < 11; x++ {
+		if c := buf.Get(x, 0); c.Style.ColorRGB != nil {
+			t.Errorf("cell %d should be unstyled, got %+v", x, c.Style)
+		}
+	}
+}
+
+func TestCodeElement_MeasuresVisibleWidth(t *testing.T) {
+	node := codeNode(gox.Props{"language": "go"}, "x := 1\nreturn x")
+	box := ComputeLayout(node, LayoutContext{Width: 40, Height: 10}).Tree()
+	if box.Width != 8 || box.Height != 2 {
+		t.Errorf("code box = %dx%d, want 8x2", box.Width, box.Height)
+	}
+}
+
+func TestKeywordTokenizer_RoundTrips(t *testing.T) {
+	src := "package main\n\n/* block\ncomment */\nfunc f(s string) int {\n\treturn len(`raw\n` + \"q\\\"\") * 0x1F // done\n}\n"
+	var sb strings.Builder
+	for _, tok := range getTokenizer("go").Tokenize(src) {
+		sb.WriteString(tok.Text)
+	}
+	if sb.String() != src {
+		t.Errorf("tokens do not reproduce source:\n%q\n%q", sb.String(), src)
+	}
+}
+
+func TestRegisterTokenizer_Custom(t *testing.T) {
+	RegisterTokenizer("shout", TokenizerFunc(func(src string) []Token {
+		return []Token{{Type: TokenKeyword, Text: src}}
+	}))
+	defer RegisterTokenizer("shout", nil)
+
+	out := HighlightCode("HEY", "shout", "github")
+	if StripAnsi(out) != "HEY" || out == "HEY" {
+		t.Errorf("HighlightCode with custom tokenizer = %q", out)
+	}
+}
@@ -0,0 +1,86 @@
+package goli
+
+import "cmp"
+
+// Number is the constraint for Sum.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns a memo of the sum of accessors, recomputed when any of
+// them changes. With no accessors it is always zero.
+//
+// Example:
+//
+//	a, setA := CreateSignal(1)
+//	b, _ := CreateSignal(2.5)
+//	total := Sum(func() float64 { return float64(a()) }, b)
+//	setA(2)
+//	fmt.Println(total()) // 4.5
+func Sum[T Number](accessors ...Accessor[T]) Accessor[T] {
+	return CreateMemo(func() T {
+		var total T
+		for _, acc := range accessors {
+			total += acc()
+		}
+		return total
+	})
+}
+
+// Min returns a memo of the smallest value of accessors, recomputed when
+// any of them changes. With no accessors it is always the zero value.
+func Min[T cmp.Ordered](accessors ...Accessor[T]) Accessor[T] {
+	return extremum(accessors, func(a, b T) bool { return a < b })
+}
+
+// Max returns a memo of the largest value of accessors, recomputed when
+// any of them changes. With no accessors it is always the zero value.
+func Max[T cmp.Ordered](accessors ...Accessor[T]) Accessor[T] {
+	return extremum(accessors, func(a, b T) bool { return a > b })
+}
+
+// extremum returns a memo of the value of accessors that beats all others.
+func extremum[T cmp.Ordered](accessors []Accessor[T], beats func(a, b T) bool) Accessor[T] {
+	return CreateMemo(func() T {
+		var best T
+		for i, acc := range accessors {
+			if v := acc(); i == 0 || beats(v, best) {
+				best = v
+			}
+		}
+		return best
+	})
+}
+
+// Map returns a memo of fn applied to acc's value. Only acc is tracked;
+// signals read inside fn are ignored.
+//
+// Example:
+//
+//	count, setCount := CreateSignal(3)
+//	label := Map(count, func(n int) string { return fmt.Sprintf("%d items", n) })
+//	setCount(4)
+//	fmt.Println(label()) // 4 items
+func Map[A, B any](acc Accessor[A], fn func(A) B) Accessor[B] {
+	return CreateMemo(func() B {
+		a := acc()
+		return Untrack(func() B { return fn(a) })
+	})
+}
+
+// Filter returns a memo of the items of acc's slice for which keep
+// returns true, in order. Only acc is tracked; signals read inside keep
+// are ignored. The source slice is never modified.
+func Filter[T any](acc Accessor[[]T], keep func(T) bool) Accessor[[]T] {
+	return Map(acc, func(items []T) []T {
+		var kept []T
+		for _, item := range items {
+			if keep(item) {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	})
+}
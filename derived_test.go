@@ -0,0 +1,109 @@
+package goli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSum_RerunsOnAnyInputChange(t *testing.T) {
+	Reset()
+	a, setA := CreateSignal(1)
+	b, setB := CreateSignal(2)
+	c, setC := CreateSignal(3)
+	total := Sum(a, b, c)
+
+	runs := 0
+	CreateEffect(func() CleanupFunc {
+		total()
+		runs++
+		return nil
+	})
+
+	setA(10)
+	setB(20)
+	setC(30)
+	if total() != 60 {
+		t.Errorf("expected 60, got %d", total())
+	}
+	if runs != 4 {
+		t.Errorf("expected a re-run per input change, got %d runs", runs)
+	}
+}
+
+func TestSum_Float(t *testing.T) {
+	Reset()
+	a, setA := CreateSignal(1.5)
+	b, _ := CreateSignal(0.25)
+	total := Sum(a, b)
+
+	setA(2.5)
+	if total() != 2.75 {
+		t.Errorf("expected 2.75, got %v", total())
+	}
+	if empty := Sum[float64](); empty() != 0 {
+		t.Errorf("expected 0 with no inputs, got %v", empty())
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	Reset()
+	a, setA := CreateSignal(5)
+	b, _ := CreateSignal(-2)
+	c, _ := CreateSignal(7)
+	lo, hi := Min(a, b, c), Max(a, b, c)
+
+	if lo() != -2 || hi() != 7 {
+		t.Errorf("expected -2 and 7, got %d and %d", lo(), hi())
+	}
+	setA(-9)
+	if lo() != -9 || hi() != 7 {
+		t.Errorf("expected -9 and 7, got %d and %d", lo(), hi())
+	}
+	setA(12)
+	if lo() != -2 || hi() != 12 {
+		t.Errorf("expected -2 and 12, got %d and %d", lo(), hi())
+	}
+
+	name, _ := CreateSignal("b")
+	if first := Min(name, func() string { return "a" }); first() != "a" {
+		t.Errorf("expected a, got %s", first())
+	}
+}
+
+func TestMap_OnlyRerunsWhenSourceChanges(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(2)
+	scale, setScale := CreateSignal(10)
+
+	calls := 0
+	scaled := Map(count, func(n int) int {
+		calls++
+		return n * scale()
+	})
+
+	setScale(100)
+	if calls != 1 || scaled() != 20 {
+		t.Errorf("expected scale changes to be ignored, got %d calls and %d", calls, scaled())
+	}
+	setCount(3)
+	if calls != 2 || scaled() != 300 {
+		t.Errorf("expected a re-run with the new scale, got %d calls and %d", calls, scaled())
+	}
+}
+
+func TestFilter(t *testing.T) {
+	Reset()
+	items, setItems := CreateSignal([]int{1, 2, 3, 4})
+	even := Filter(items, func(n int) bool { return n%2 == 0 })
+
+	if !slices.Equal(even(), []int{2, 4}) {
+		t.Errorf("expected [2 4], got %v", even())
+	}
+	setItems([]int{6, 7, 8})
+	if !slices.Equal(even(), []int{6, 8}) {
+		t.Errorf("expected [6 8], got %v", even())
+	}
+	if !slices.Equal(items(), []int{6, 7, 8}) {
+		t.Errorf("expected the source to be unchanged, got %v", items())
+	}
+}
@@ -28,6 +28,9 @@ func DiffBuffers(from, to *CellBuffer) []CellChange {
 
 	// Compare overlapping region
 	for y := 0; y < height; y++ {
+		if rowsEqual(from, to, y) {
+			continue
+		}
 		for x := 0; x < width; x++ {
 			fromCell := from.Get(x, y)
 			toCell := to.Get(x, y)
@@ -55,6 +58,54 @@ func DiffBuffers(from, to *CellBuffer) []CellChange {
 	return changes
 }
 
+// Conflict is a cell that both sides of a Diff3 changed differently.
+type Conflict struct {
+	X, Y  int
+	Left  Cell
+	Right Cell
+}
+
+// Diff3 merges two buffers that were both derived from base, e.g. the
+// outputs of two renderers that each own a region of the screen. A cell
+// changed only in left or only in right takes that side's value. A cell
+// changed differently in both keeps left's value and is reported as a
+// Conflict. The result is a new buffer with base's dimensions; cells outside
+// left or right count as unchanged, so a side smaller than base only
+// affects the cells it covers.
+func Diff3(base, left, right *CellBuffer) (*CellBuffer, []Conflict) {
+	merged := NewCellBuffer(base.Width(), base.Height())
+	var conflicts []Conflict
+
+	for y := 0; y < base.Height(); y++ {
+		for x := 0; x < base.Width(); x++ {
+			b := base.Get(x, y)
+			l, r := b, b
+			if left.inBounds(x, y) {
+				l = left.Get(x, y)
+			}
+			if right.inBounds(x, y) {
+				r = right.Get(x, y)
+			}
+
+			leftChanged := !l.Equal(b)
+			rightChanged := !r.Equal(b)
+			switch {
+			case leftChanged && rightChanged && !l.Equal(r):
+				conflicts = append(conflicts, Conflict{X: x, Y: y, Left: l, Right: r})
+				merged.Set(x, y, l)
+			case leftChanged:
+				merged.Set(x, y, l)
+			case rightChanged:
+				merged.Set(x, y, r)
+			default:
+				merged.Set(x, y, b)
+			}
+		}
+	}
+
+	return merged, conflicts
+}
+
 // GroupChangesByRow groups changes by row for more efficient cursor movement.
 func GroupChangesByRow(changes []CellChange) map[int][]CellChange {
 	byRow := make(map[int][]CellChange)
@@ -128,6 +179,9 @@ func DiffBuffersInto(from, to *CellBuffer, result []CellChange) []CellChange {
 
 	// Compare overlapping region
 	for y := 0; y < height; y++ {
+		if rowsEqual(from, to, y) {
+			continue
+		}
 		for x := 0; x < width; x++ {
 			fromCell := from.Get(x, y)
 			toCell := to.Get(x, y)
@@ -0,0 +1,153 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff3_MergesDisjointChanges(t *testing.T) {
+	base := NewCellBuffer(4, 1)
+	base.WriteString(0, 0, "....", Style{})
+	left := NewCellBuffer(4, 1)
+	left.WriteString(0, 0, "ab..", Style{})
+	right := NewCellBuffer(4, 1)
+	right.WriteString(0, 0, "..cd", Style{})
+
+	merged, conflicts := Diff3(base, left, right)
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if got := merged.ToDebugString(); got != "abcd" {
+		t.Errorf("expected %q, got %q", "abcd", got)
+	}
+}
+
+func TestDiff3_ReportsConflicts(t *testing.T) {
+	base := NewCellBuffer(3, 1)
+	left := NewCellBuffer(3, 1)
+	left.WriteString(0, 0, "xy", Style{})
+	right := NewCellBuffer(3, 1)
+	right.WriteString(0, 0, "xz", Style{})
+
+	merged, conflicts := Diff3(base, left, right)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %v", conflicts)
+	}
+	c := conflicts[0]
+	if c.X != 1 || c.Y != 0 || c.Left.Char != 'y' || c.Right.Char != 'z' {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+	// Identical changes on both sides aren't conflicts
+	if got := merged.Get(0, 0).Char; got != 'x' {
+		t.Errorf("expected 'x', got %q", got)
+	}
+}
+
+func TestDiff3_SmallerSideLeavesRestOfBase(t *testing.T) {
+	base := NewCellBuffer(4, 2)
+	base.WriteString(0, 0, "....", Style{})
+	base.WriteString(0, 1, "....", Style{})
+	left := NewCellBuffer(2, 1)
+	left.WriteString(0, 0, "ab", Style{})
+	right := NewCellBuffer(4, 2)
+	right.WriteString(0, 0, "....", Style{})
+	right.WriteString(0, 1, "..cd", Style{})
+
+	merged, conflicts := Diff3(base, left, right)
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if got := merged.ToDebugString(); got != "ab..\n..cd" {
+		t.Errorf("expected %q, got %q", "ab..\n..cd", got)
+	}
+}
+
+func TestDiffBuffers_SkipsOnlyUnchangedRows(t *testing.T) {
+	from := NewCellBuffer(20, 5)
+	to := NewCellBuffer(20, 5)
+	for _, buf := range []*CellBuffer{from, to} {
+		buf.WriteString(0, 1, "unchanged", Style{Bold: true})
+	}
+	to.SetChar(7, 3, 'x', Style{ColorRGB: &RGB{1, 2, 3}})
+
+	changes := DiffBuffers(from, to)
+	if len(changes) != 1 || changes[0].X != 7 || changes[0].Y != 3 {
+		t.Fatalf("expected one change at (7,3), got %+v", changes)
+	}
+
+	// Only the style differs
+	from.SetChar(7, 3, 'x', Style{ColorRGB: &RGB{1, 2, 4}})
+	if changes := DiffBuffersInto(from, to, nil); len(changes) != 1 {
+		t.Errorf("expected a style-only change, got %+v", changes)
+	}
+
+	// Writing a cell and restoring it leaves the row equal
+	from.SetChar(7, 3, 'x', Style{ColorRGB: &RGB{1, 2, 3}})
+	to.SetChar(0, 0, 'y', Style{})
+	to.Set(0, 0, EmptyCell)
+	if changes := DiffBuffers(from, to); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffBuffers_RowHashesFollowResizeAndClear(t *testing.T) {
+	from := NewCellBuffer(10, 3)
+	to := NewCellBuffer(10, 3)
+	to.WriteString(0, 0, "abc", Style{})
+	to.Resize(12, 4)
+	from.Resize(12, 4)
+
+	if changes := DiffBuffers(from, to); len(changes) != 3 {
+		t.Errorf("expected 3 changes after resize, got %d", len(changes))
+	}
+
+	to.Clear()
+	if changes := DiffBuffers(from, to); len(changes) != 0 {
+		t.Errorf("expected no changes after clear, got %d", len(changes))
+	}
+}
+
+func TestDiffBuffers_SwappedCells(t *testing.T) {
+	// Swapped cells leave the XOR row hash unchanged
+	for _, tc := range []struct{ from, to string }{
+		{"ab", "ba"},
+		{"! ", " !"},
+		{"xyz", "zyx"},
+	} {
+		from, to := NewCellBuffer(5, 1), NewCellBuffer(5, 1)
+		from.WriteString(0, 0, tc.from, Style{})
+		to.WriteString(0, 0, tc.to, Style{})
+		if changes := DiffBuffers(from, to); len(changes) == 0 {
+			t.Errorf("expected changes for %q -> %q, got none", tc.from, tc.to)
+		}
+		if changes := DiffBuffersInto(from, to, nil); len(changes) == 0 {
+			t.Errorf("expected changes into for %q -> %q, got none", tc.from, tc.to)
+		}
+	}
+}
+
+func benchmarkBuffer() *CellBuffer {
+	buf := NewCellBuffer(200, 50)
+	for y := 0; y < 50; y++ {
+		buf.WriteString(0, y, strings.Repeat("goli diff ", 20), Style{Color: ColorGreen})
+	}
+	return buf
+}
+
+func BenchmarkDiffBuffers_NoChanges(b *testing.B) {
+	from, to := benchmarkBuffer(), benchmarkBuffer()
+	changes := make([]CellChange, 0, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		changes = DiffBuffersInto(from, to, changes[:0])
+	}
+}
+
+func BenchmarkCellBuffer_Set(b *testing.B) {
+	buf := NewCellBuffer(200, 50)
+	cell := New('x', Style{Color: ColorGreen})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Set(i%200, (i/200)%50, cell)
+	}
+}
@@ -8,11 +8,26 @@ type CleanupFunc func()
 // DisposeFunc is a function that disposes an effect.
 type DisposeFunc func()
 
+// EffectOptions configures CreateEffect.
+type EffectOptions struct {
+	// Deferred delays the first run until the enclosing CreateRoot callback
+	// returns, so the effect sees everything the root sets up. Outside a
+	// root's setup, the first run waits for the current batch to end.
+	Deferred bool
+	// Once runs the effect a single time without tracking dependencies, like
+	// onMount. Its cleanup still runs when the effect is disposed.
+	Once bool
+}
+
 // CreateEffect creates a reactive effect that runs when its dependencies change.
 // Returns a dispose function to stop the effect.
 //
 // The effect function can optionally return a cleanup function that runs before
-// each re-execution and when the effect is disposed.
+// each re-execution and when the effect is disposed. Functions registered with
+// OnCleanup during a run, and effects created during it, are cleaned up at the
+// same times, so disposing one effect doesn't require disposing its root. By
+// default the effect runs immediately; see EffectOptions to defer it or run it
+// once.
 //
 // Example:
 //
@@ -22,8 +37,24 @@ type DisposeFunc func()
 //	    fmt.Println("Count is:", count())
 //	    return func() { fmt.Println("Cleaning up") }
 //	})
-func CreateEffect(fn func() CleanupFunc) DisposeFunc {
+//
+//	// Runs once, after the rest of the root is set up
+//	CreateEffect(func() CleanupFunc {
+//	    input.Focus()
+//	    return nil
+//	}, EffectOptions{Deferred: true, Once: true})
+func CreateEffect(fn func() CleanupFunc, opts ...EffectOptions) DisposeFunc {
+	var options EffectOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	if options.Once {
+		run := fn
+		fn = func() CleanupFunc { return Untrack(run) }
+	}
+
 	var cleanup CleanupFunc
+	var runOwner *Owner // Owns OnCleanup calls and effects of the last run
 	var disposed bool
 	var mu sync.Mutex
 
@@ -39,11 +70,11 @@ func CreateEffect(fn func() CleanupFunc) DisposeFunc {
 		}
 
 		// Cleanup previous run
-		if cleanup != nil {
-			cleanupFn := cleanup
-			cleanup = nil
+		if cleanup != nil || runOwner != nil {
+			cleanupFn, owned := cleanup, runOwner
+			cleanup, runOwner = nil, nil
 			mu.Unlock()
-			cleanupFn()
+			runCleanups(cleanupFn, owned)
 			mu.Lock()
 		}
 
@@ -57,21 +88,31 @@ func CreateEffect(fn func() CleanupFunc) DisposeFunc {
 
 		mu.Unlock()
 
-		// Run with tracking
+		// Run with tracking, owning what the run creates
+		owner := &Owner{}
 		prevComputation := Global.getCurrentComputation()
+		prevOwner := Global.getCurrentOwner()
 		Global.setCurrentComputation(comp)
+		Global.setCurrentOwner(owner)
 
 		newCleanup := fn()
 
+		Global.setCurrentOwner(prevOwner)
 		Global.setCurrentComputation(prevComputation)
 
 		mu.Lock()
-		cleanup = newCleanup
+		cleanup, runOwner = newCleanup, owner
 		mu.Unlock()
 	}
 
 	// Initial run
-	comp.execute()
+	owner := Global.getCurrentOwner()
+	Global.trackComputation(comp)
+	if options.Deferred {
+		Global.deferEffect(owner, comp.execute)
+	} else {
+		comp.execute()
+	}
 
 	// Dispose function
 	dispose := func() {
@@ -81,8 +122,9 @@ func CreateEffect(fn func() CleanupFunc) DisposeFunc {
 			return
 		}
 		disposed = true
-		cleanupFn := cleanup
-		cleanup = nil
+		cleanupFn, owned := cleanup, runOwner
+		cleanup, runOwner = nil, nil
+		Global.untrackComputation(comp)
 
 		// Unsubscribe from all signals
 		comp.mu.Lock()
@@ -94,22 +136,35 @@ func CreateEffect(fn func() CleanupFunc) DisposeFunc {
 
 		mu.Unlock()
 
-		if cleanupFn != nil {
-			cleanupFn()
-		}
+		runCleanups(cleanupFn, owned)
 	}
 
 	// Register with current owner for automatic cleanup
-	owner := Global.getCurrentOwner()
 	if owner != nil {
-		Global.mu.Lock()
-		owner.disposables = append(owner.disposables, dispose)
-		Global.mu.Unlock()
+		Global.addDisposable(owner, dispose)
 	}
 
 	return dispose
 }
 
+// runCleanups runs an effect run's returned cleanup, then the cleanups
+// registered with its owner by OnCleanup and nested effects.
+func runCleanups(cleanup CleanupFunc, owner *Owner) {
+	if cleanup != nil {
+		cleanup()
+	}
+	if owner == nil {
+		return
+	}
+	Global.mu.Lock()
+	disposables := owner.disposables
+	owner.disposables = nil
+	Global.mu.Unlock()
+	for _, d := range disposables {
+		d()
+	}
+}
+
 // CreateEffectSimple creates an effect without cleanup.
 func CreateEffectSimple(fn func()) DisposeFunc {
 	return CreateEffect(func() CleanupFunc {
@@ -118,6 +173,32 @@ func CreateEffectSimple(fn func()) DisposeFunc {
 	})
 }
 
+// Suspend runs fn in a computation that pauses while condition() is true.
+// While paused only condition is tracked, so changes to fn's dependencies
+// are ignored, and effects created inside fn are disposed. When condition
+// becomes false, fn runs again immediately. This is the building block for
+// Suspense-like components that wait for a resource.
+// Returns a dispose function that stops the computation.
+//
+// Example:
+//
+//	loading, setLoading := CreateSignal(true)
+//	Suspend(loading, func() {
+//	    fmt.Println("Data:", data())
+//	})
+//	setLoading(false) // prints the data
+func Suspend(condition Accessor[bool], fn func()) DisposeFunc {
+	return CreateEffect(func() CleanupFunc {
+		if condition() {
+			return nil
+		}
+		return CreateRoot(func(dispose DisposeFunc) CleanupFunc {
+			fn()
+			return CleanupFunc(dispose)
+		})
+	})
+}
+
 // CreateMemo creates a memoized computation.
 // Only re-computes when dependencies change.
 //
@@ -138,3 +219,80 @@ func CreateMemo[T any](fn func() T) Accessor[T] {
 
 	return value
 }
+
+// CreateMemoWithDeps creates a memoized computation with an explicit
+// dependency list, like React's useMemo(fn, deps). Only the deps accessors
+// are tracked; signals read inside compute are ignored. compute re-runs
+// only when at least one dep returns a value that differs (==) from the
+// previous run.
+//
+// Example:
+//
+//	count, _ := CreateSignal(5)
+//	scale, _ := CreateSignal(2)
+//	scaled := CreateMemoWithDeps(
+//	    []func() any{func() any { return count() }},
+//	    func() int { return count() * scale() }, // scale changes are ignored
+//	)
+func CreateMemoWithDeps[T any](deps []func() any, compute func() T) Accessor[T] {
+	value, setValue := CreateSignal[T](*new(T))
+
+	var prev []any
+	CreateEffect(func() CleanupFunc {
+		current := make([]any, len(deps))
+		for i, dep := range deps {
+			current[i] = dep()
+		}
+		if prev != nil && depsEqual(prev, current) {
+			return nil
+		}
+		prev = current
+		setValue(Untrack(compute))
+		return nil
+	})
+
+	return value
+}
+
+// CreateSelector derives a value from part of a larger store. selector
+// re-runs whenever store changes, but subscribers are only notified when
+// its result differs (==) from the previous one, so components reading
+// one field are not re-run by changes to the others. Only store is
+// tracked; signals read inside selector are ignored.
+//
+// Example:
+//
+//	state, setState := CreateSignal(AppState{Title: "goli"})
+//	title := CreateSelector(state, func(s AppState) string {
+//	    return s.Title
+//	})
+//	setState(AppState{Title: "goli", Count: 1}) // title's subscribers don't re-run
+func CreateSelector[S any, T comparable](store Accessor[S], selector func(S) T) Accessor[T] {
+	value, setValue := CreateSignalWithEquals(*new(T), func(a, b T) bool {
+		return a == b
+	})
+
+	CreateEffect(func() CleanupFunc {
+		s := store()
+		setValue(Untrack(func() T { return selector(s) }))
+		return nil
+	})
+
+	return value
+}
+
+// depsEqual compares dependency values shallowly.
+// Values that can't be compared with == are treated as changed.
+func depsEqual(a, b []any) (equal bool) {
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -122,7 +122,7 @@ func main() {
 	fmt.Println(formatResult("2. Layout computation", layoutComputation))
 
 	// 3. Render to buffer
-	layoutResult := goli.ComputeLayout(tree, goli.LayoutContext{X: 0, Y: 0, Width: Cols, Height: Rows})
+	layoutResult := goli.ComputeLayout(tree, goli.LayoutContext{X: 0, Y: 0, Width: Cols, Height: Rows}).Tree()
 	renderToBufferResult := benchmark("Render to buffer", BenchmarkIterations, func() {
 		buf := goli.NewCellBuffer(Cols, Rows)
 		goli.RenderToBuffer(layoutResult, buf, nil)
@@ -143,7 +143,7 @@ func main() {
 	// 5. Buffer diffing (all changes)
 	buf3 := goli.NewCellBuffer(Cols, Rows)
 	tree2 := generateTree(1) // Different frame
-	layout2 := goli.ComputeLayout(tree2, goli.LayoutContext{X: 0, Y: 0, Width: Cols, Height: Rows})
+	layout2 := goli.ComputeLayout(tree2, goli.LayoutContext{X: 0, Y: 0, Width: Cols, Height: Rows}).Tree()
 	goli.RenderToBuffer(layout2, buf3, nil)
 
 	diffAllChanges := benchmark("Diff (all changes)", BenchmarkIterations, func() {
@@ -253,7 +253,8 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	// Input channel - read keys in a goroutine, send to channel
+	// Input channel - read keys in a goroutine, send to channel until the
+	// app is disposed
 	keyCh := make(chan string, 10)
 	go func() {
 		buf := make([]byte, 64)
@@ -262,7 +263,11 @@ func main() {
 			if err != nil {
 				return
 			}
-			keyCh <- string(buf[:n])
+			select {
+			case keyCh <- string(buf[:n]):
+			case <-application.Context().Done():
+				return
+			}
 		}
 	}()
 
@@ -1,6 +1,7 @@
 package goli
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -16,11 +17,52 @@ type Focusable interface {
 
 // FocusManager manages focus state for terminal UI components.
 type FocusManager struct {
-	mu                  sync.RWMutex
-	currentFocused      Accessor[Focusable]
-	setCurrentFocused   Setter[Focusable]
-	registered          []Focusable
-	globalKeyHandler func(key string) bool
+	mu                sync.RWMutex
+	currentFocused    Accessor[Focusable]
+	setCurrentFocused Setter[Focusable]
+	registered        []Focusable
+	globalKeyHandlers []*globalKeyHandler // Stack; last is consulted first
+	keySequences      *keySequenceMatcher // Created by RegisterKeySequence
+	activeBoxes       []BoxKeyHandler
+	layout            map[Focusable]layoutPosition
+	history           []Focusable
+	historyPos        int             // Number of entries up to and including the current one
+	inputRunner       func(fn func()) // Set by Run, see postInput
+
+	// HistoryMaxLen caps the focus history (0 = DefaultHistoryMaxLen).
+	HistoryMaxLen int
+	// BackNavOnBackspace makes an unconsumed Backspace call Back.
+	BackNavOnBackspace bool
+}
+
+// layoutPosition is where a focusable was found in the last layout.
+type layoutPosition struct {
+	tabIndex int // Explicit "tabIndex" prop, 0 if unset
+	order    int // Tree walk order; later boxes are drawn on top
+	x, y     int
+	w, h     int
+}
+
+// BoxKeyHandler is a key handler installed by a box with an "onKey" prop.
+// Handlers of boxes with "active": true are consulted after the focused
+// element and before the global key handler, so a panel can react to keys
+// (e.g., Page Up/Down) without taking focus away from an input.
+type BoxKeyHandler struct {
+	// ID is the box's "id" prop, which must be unique among active boxes.
+	ID string
+	// OnKey returns true if the key was consumed.
+	OnKey func(key string) bool
+}
+
+// globalKeyHandler is a stack entry; its address identifies it for removal.
+type globalKeyHandler struct {
+	handle func(key string) bool
+}
+
+// tabCapturer is implemented by focusables that may consume Tab or
+// Shift+Tab before it is used for focus navigation.
+type tabCapturer interface {
+	capturesTab(key string) bool
 }
 
 // Manager returns the global focus manager.
@@ -29,6 +71,28 @@ func Manager() *FocusManager {
 	return Global.FocusManager()
 }
 
+// setInputRunner routes work posted with postInput through run, which Run
+// points at its input goroutine. Pass nil to run posted work directly.
+func (m *FocusManager) setInputRunner(run func(fn func())) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputRunner = run
+}
+
+// postInput runs fn on the input goroutine, so that work started outside
+// it, such as a key sequence timing out, is handled in order with keys.
+// Without a running input loop (e.g., in tests) fn runs immediately.
+func (m *FocusManager) postInput(fn func()) {
+	m.mu.RLock()
+	run := m.inputRunner
+	m.mu.RUnlock()
+	if run == nil {
+		fn()
+		return
+	}
+	run(fn)
+}
+
 // Register adds a focusable to the manager.
 func (m *FocusManager) Register(f Focusable) {
 	m.mu.Lock()
@@ -48,17 +112,26 @@ func (m *FocusManager) Unregister(f Focusable) {
 		}
 	}
 
+	m.removeFromHistory(f)
+
 	// If this was focused, clear focus
 	if m.currentFocused() == f {
 		m.setCurrentFocused(nil)
 	}
 }
 
-// RequestFocus focuses a specific focusable.
+// RequestFocus focuses a specific focusable and records it in the history.
 func (m *FocusManager) RequestFocus(f Focusable) {
+	if m.focus(f) {
+		m.PushHistory(f)
+	}
+}
+
+// focus moves focus to f. Returns false if f was already focused.
+func (m *FocusManager) focus(f Focusable) bool {
 	current := m.currentFocused()
 	if current == f {
-		return
+		return false
 	}
 
 	BatchVoid(func() {
@@ -68,6 +141,7 @@ func (m *FocusManager) RequestFocus(f Focusable) {
 		f.SetFocused(true)
 		m.setCurrentFocused(f)
 	})
+	return true
 }
 
 // RequestBlur blurs a specific focusable.
@@ -85,7 +159,120 @@ func (m *FocusManager) Current() Focusable {
 	return m.currentFocused()
 }
 
-// Next focuses the next element in registration order.
+// SetLayout records the layout position and "tabIndex" prop of every
+// registered focusable found in the tree. A box belongs to a focusable when
+// one of its props holds it (e.g. "input", "button", "select", "url").
+// Renderers call this after each layout pass.
+func (m *FocusManager) SetLayout(root *LayoutBox) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	registered := make(map[Focusable]bool, len(m.registered))
+	for _, f := range m.registered {
+		registered[f] = true
+	}
+
+	layout := make(map[Focusable]layoutPosition)
+	var walk func(box *LayoutBox)
+	walk = func(box *LayoutBox) {
+		if box == nil {
+			return
+		}
+		for _, value := range box.Node.Props {
+			f, ok := value.(Focusable)
+			if !ok || !registered[f] {
+				continue
+			}
+			if _, seen := layout[f]; !seen {
+				layout[f] = layoutPosition{
+					tabIndex: GetIntProp(box.Node.Props, "tabIndex", 0),
+					order:    len(layout),
+					x:        box.X,
+					y:        box.Y,
+					w:        box.Width,
+					h:        box.Height,
+				}
+			}
+		}
+		for _, child := range box.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	m.layout = layout
+}
+
+// SortByTabIndex reorders the registered focusables for Tab navigation.
+// Elements with a positive "tabIndex" prop come first, in ascending order;
+// the rest follow in reading order (top-to-bottom, left-to-right) of the
+// layout recorded by SetLayout. Elements absent from the layout keep their
+// registration order at the end.
+func (m *FocusManager) SortByTabIndex() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.layout) == 0 {
+		return
+	}
+
+	sort.SliceStable(m.registered, func(i, j int) bool {
+		a, aOk := m.layout[m.registered[i]]
+		b, bOk := m.layout[m.registered[j]]
+		if aOk != bOk {
+			return aOk
+		}
+		if !aOk {
+			return false
+		}
+		aExplicit, bExplicit := a.tabIndex > 0, b.tabIndex > 0
+		if aExplicit != bExplicit {
+			return aExplicit
+		}
+		if aExplicit && a.tabIndex != b.tabIndex {
+			return a.tabIndex < b.tabIndex
+		}
+		if a.y != b.y {
+			return a.y < b.y
+		}
+		return a.x < b.x
+	})
+}
+
+// HandleMouse routes a mouse event to the topmost focusable under the
+// pointer, using the layout recorded by SetLayout. A left-button press
+// focuses it, and focusables implementing MouseHandler receive the event.
+// Returns true if the event was consumed.
+func (m *FocusManager) HandleMouse(ev MouseEvent) bool {
+	m.mu.RLock()
+	var target Focusable
+	topOrder := -1
+	for f, pos := range m.layout {
+		inside := ev.X >= pos.x && ev.X < pos.x+pos.w && ev.Y >= pos.y && ev.Y < pos.y+pos.h
+		if inside && pos.order > topOrder {
+			target, topOrder = f, pos.order
+		}
+	}
+	m.mu.RUnlock()
+
+	if target == nil {
+		return false
+	}
+
+	consumed := false
+	if ev.Action == MousePress && ev.Button == MouseLeft {
+		if !target.Focused() {
+			target.Focus()
+		}
+		consumed = true
+	}
+	if h, ok := target.(MouseHandler); ok && h.HandleMouse(ev) {
+		consumed = true
+	}
+	return consumed
+}
+
+// Next focuses the next element in tab order.
 func (m *FocusManager) Next() {
 	m.mu.RLock()
 	focusables := make([]Focusable, len(m.registered))
@@ -114,7 +301,7 @@ func (m *FocusManager) Next() {
 	focusables[nextIndex].Focus()
 }
 
-// Prev focuses the previous element in registration order.
+// Prev focuses the previous element in tab order.
 func (m *FocusManager) Prev() {
 	m.mu.RLock()
 	focusables := make([]Focusable, len(m.registered))
@@ -147,47 +334,139 @@ func (m *FocusManager) Prev() {
 // Handles Tab/Shift+Tab for focus navigation.
 // Returns true if the key was consumed.
 func (m *FocusManager) HandleKey(key string) bool {
-	// Handle focus navigation
-	if key == Tab {
-		m.Next()
-		return true
-	}
-	if key == ShiftTab {
-		m.Prev()
+	current := m.currentFocused()
+
+	// Handle focus navigation, unless the focused element uses Tab itself
+	// (e.g., an input with Tab completion)
+	if key == Tab || key == ShiftTab {
+		if c, ok := current.(tabCapturer); ok && c.capturesTab(key) && current.HandleKey(key) {
+			return true
+		}
+		if key == Tab {
+			m.Next()
+		} else {
+			m.Prev()
+		}
 		return true
 	}
 
 	// Route to focused element
-	current := m.currentFocused()
 	if current != nil && current.HandleKey(key) {
 		return true
 	}
 
-	// Try unhandled handler
+	// Route to active boxes, innermost (last laid out) first
+	for _, box := range m.ActiveBoxes() {
+		if box.OnKey(key) {
+			return true
+		}
+	}
+
+	if key == Backspace && m.BackNavOnBackspace && m.Back() {
+		return true
+	}
+
+	m.mu.RLock()
+	sequences := m.keySequences
+	m.mu.RUnlock()
+	if sequences != nil && sequences.handle(key) {
+		return true
+	}
+
+	return m.handleGlobalKey(key)
+}
+
+// handleGlobalKey tries the global handlers, most recently pushed first.
+func (m *FocusManager) handleGlobalKey(key string) bool {
 	m.mu.RLock()
-	handler := m.globalKeyHandler
+	handlers := make([]*globalKeyHandler, len(m.globalKeyHandlers))
+	copy(handlers, m.globalKeyHandlers)
 	m.mu.RUnlock()
 
-	if handler != nil {
-		return handler(key)
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if handlers[i].handle(key) {
+			return true
+		}
 	}
 
 	return false
 }
 
-// SetGlobalKeyHandler sets a handler for app-wide keyboard shortcuts.
-// This handler is called for keys that no focused element consumes.
-// Returns a cleanup function to remove the handler.
+// SetGlobalKeyHandler pushes a handler for app-wide keyboard shortcuts.
+// Handlers are called for keys that no focused element consumes, most
+// recently pushed first, until one returns true.
+// Returns a cleanup function that removes the handler; calling it more
+// than once is safe.
 func (m *FocusManager) SetGlobalKeyHandler(handler func(key string) bool) func() {
+	entry := &globalKeyHandler{handle: handler}
 	m.mu.Lock()
-	m.globalKeyHandler = handler
+	m.globalKeyHandlers = append(m.globalKeyHandlers, entry)
 	m.mu.Unlock()
 
 	return func() {
 		m.mu.Lock()
-		m.globalKeyHandler = nil
-		m.mu.Unlock()
+		defer m.mu.Unlock()
+		for i, h := range m.globalKeyHandlers {
+			if h == entry {
+				m.globalKeyHandlers = append(m.globalKeyHandlers[:i:i], m.globalKeyHandlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SetActiveBoxes replaces the set of active box key handlers.
+// Renderers call this after each layout pass.
+func (m *FocusManager) SetActiveBoxes(boxes []BoxKeyHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.activeBoxes = boxes
+}
+
+// ActiveBoxes returns the active box key handlers in priority order.
+func (m *FocusManager) ActiveBoxes() []BoxKeyHandler {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]BoxKeyHandler, len(m.activeBoxes))
+	for i, box := range m.activeBoxes {
+		result[len(result)-1-i] = box
 	}
+	return result
+}
+
+// CollectBoxKeyHandlers walks a layout tree and returns the key handlers of
+// boxes that have an "id", an "onKey" handler and "active": true.
+// Boxes sharing an id keep only the last occurrence.
+func CollectBoxKeyHandlers(root *LayoutBox) []BoxKeyHandler {
+	var handlers []BoxKeyHandler
+	index := make(map[string]int)
+
+	var walk func(box *LayoutBox)
+	walk = func(box *LayoutBox) {
+		if box == nil {
+			return
+		}
+		props := box.Node.Props
+		if box.Node.Type == "box" && GetBoolProp(props, "active", false) {
+			id := GetStringProp(props, "id", "")
+			onKey, _ := props["onKey"].(func(key string) bool)
+			if id != "" && onKey != nil {
+				handler := BoxKeyHandler{ID: id, OnKey: onKey}
+				if i, ok := index[id]; ok {
+					handlers[i] = handler
+				} else {
+					index[id] = len(handlers)
+					handlers = append(handlers, handler)
+				}
+			}
+		}
+		for _, child := range box.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return handlers
 }
 
 // Set manually sets the focused element. Pass nil to blur all.
@@ -212,17 +491,47 @@ func (m *FocusManager) GetAll() []Focusable {
 }
 
 // Clear removes all registered focusables and handlers.
+// Every registered element and the focused one are blurred, after the
+// manager's lock is released, since SetFocused and subscribers of the
+// focused element may call back into the manager.
 func (m *FocusManager) Clear() {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
+	registered := m.registered
 	current := m.currentFocused()
-	if current != nil {
-		current.SetFocused(false)
-	}
-	m.setCurrentFocused(nil)
 	m.registered = nil
-	m.globalKeyHandler = nil
+	m.globalKeyHandlers = nil
+	if m.keySequences != nil {
+		m.keySequences.reset()
+		m.keySequences = nil
+	}
+	m.activeBoxes = nil
+	m.layout = nil
+	m.history = nil
+	m.historyPos = 0
+	m.mu.Unlock()
+
+	BatchVoid(func() {
+		for _, f := range registered {
+			f.SetFocused(false)
+		}
+		if current != nil {
+			current.SetFocused(false)
+		}
+		m.setCurrentFocused(nil)
+	})
+}
+
+// notifyFocusChange calls onFocus or onBlur when a focusable's state
+// actually transitions. Primitives call it from SetFocused.
+func notifyFocusChange(wasFocused, focused bool, onFocus, onBlur func()) {
+	if wasFocused == focused {
+		return
+	}
+	if focused && onFocus != nil {
+		onFocus()
+	} else if !focused && onBlur != nil {
+		onBlur()
+	}
 }
 
 // Convenience functions that use the global manager
@@ -251,3 +560,8 @@ func RequestBlur(f Focusable) {
 func HandleKey(key string) bool {
 	return Manager().HandleKey(key)
 }
+
+// HandleMouse routes a mouse event using the global manager.
+func HandleMouse(ev MouseEvent) bool {
+	return Manager().HandleMouse(ev)
+}
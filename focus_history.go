@@ -0,0 +1,97 @@
+package goli
+
+// DefaultHistoryMaxLen is the focus history cap when HistoryMaxLen is 0.
+const DefaultHistoryMaxLen = 100
+
+// PushHistory records f as the current focus history entry, discarding any
+// forward entries like a browser does. RequestFocus calls it automatically;
+// Back and Forward don't. Pushing the current entry again is a no-op.
+func (m *FocusManager) PushHistory(f Focusable) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.historyPos > 0 && m.history[m.historyPos-1] == f {
+		return
+	}
+	m.history = append(m.history[:m.historyPos], f)
+
+	maxLen := m.HistoryMaxLen
+	if maxLen <= 0 {
+		maxLen = DefaultHistoryMaxLen
+	}
+	if over := len(m.history) - maxLen; over > 0 {
+		m.history = append(m.history[:0], m.history[over:]...)
+	}
+	m.historyPos = len(m.history)
+}
+
+// Back focuses the previous history entry.
+// Returns false if there is nothing to go back to.
+func (m *FocusManager) Back() bool {
+	m.mu.Lock()
+	if m.historyPos <= 1 {
+		m.mu.Unlock()
+		return false
+	}
+	m.historyPos--
+	target := m.history[m.historyPos-1]
+	m.mu.Unlock()
+
+	m.focus(target)
+	return true
+}
+
+// Forward focuses the next history entry after going Back.
+// Returns false if there is nothing to go forward to.
+func (m *FocusManager) Forward() bool {
+	m.mu.Lock()
+	if m.historyPos >= len(m.history) {
+		m.mu.Unlock()
+		return false
+	}
+	m.historyPos++
+	target := m.history[m.historyPos-1]
+	m.mu.Unlock()
+
+	m.focus(target)
+	return true
+}
+
+// HistoryLen returns the number of entries in the focus history.
+func (m *FocusManager) HistoryLen() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.history)
+}
+
+// CanGoBack returns whether Back would move focus.
+func (m *FocusManager) CanGoBack() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.historyPos > 1
+}
+
+// CanGoForward returns whether Forward would move focus.
+func (m *FocusManager) CanGoForward() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.historyPos < len(m.history)
+}
+
+// removeFromHistory drops every entry for f, e.g. when it is disposed.
+// Callers must hold m.mu.
+func (m *FocusManager) removeFromHistory(f Focusable) {
+	kept := m.history[:0]
+	pos := m.historyPos
+	for i, entry := range m.history {
+		if entry == f {
+			if i < m.historyPos {
+				pos--
+			}
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	m.history = kept
+	m.historyPos = pos
+}
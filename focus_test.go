@@ -2,6 +2,9 @@ package goli
 
 import (
 	"testing"
+	"time"
+
+	"github.com/germtb/gox"
 )
 
 // mockFocusable is a test implementation of Focusable
@@ -292,3 +295,396 @@ func TestFocusManager_GlobalKeyHandler(t *testing.T) {
 		t.Error("handler should be removed after cleanup")
 	}
 }
+
+func TestFocusManager_GlobalKeyHandlerStack(t *testing.T) {
+	setupTest(t)
+
+	var calls []string
+	cleanupFirst := Manager().SetGlobalKeyHandler(func(key string) bool {
+		calls = append(calls, "first:"+key)
+		return key == "q"
+	})
+	cleanupSecond := Manager().SetGlobalKeyHandler(func(key string) bool {
+		calls = append(calls, "second:"+key)
+		return key == "d"
+	})
+	defer cleanupFirst()
+
+	HandleKey("q")
+	if len(calls) != 2 || calls[0] != "second:q" || calls[1] != "first:q" {
+		t.Errorf("expected [second:q first:q], got %v", calls)
+	}
+
+	calls = nil
+	HandleKey("d")
+	if len(calls) != 1 || calls[0] != "second:d" {
+		t.Errorf("expected [second:d], got %v", calls)
+	}
+
+	// Cleanup is idempotent and only removes its own handler
+	cleanupSecond()
+	cleanupSecond()
+	calls = nil
+	HandleKey("q")
+	if len(calls) != 1 || calls[0] != "first:q" {
+		t.Errorf("expected [first:q], got %v", calls)
+	}
+}
+
+func TestFocusManager_ClearResetsManager(t *testing.T) {
+	setupTest(t)
+
+	old1, old2 := newMockFocusable(), newMockFocusable()
+	Register(old1)
+	Register(old2)
+	old1.Focus()
+	globalCalls := 0
+	Manager().SetGlobalKeyHandler(func(key string) bool {
+		globalCalls++
+		return true
+	})
+
+	// A subscriber that calls back into the manager must not deadlock
+	currentChanges := 0
+	CreateEffect(func() CleanupFunc {
+		Manager().Current()
+		Manager().GetAll()
+		currentChanges++
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		Manager().Clear()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Clear deadlocked")
+	}
+
+	if old1.Focused() || Manager().Current() != nil || currentChanges != 2 {
+		t.Errorf("expected focus cleared once, got focused=%v current=%v changes=%d", old1.Focused(), Manager().Current(), currentChanges)
+	}
+	if len(Manager().GetAll()) != 0 {
+		t.Errorf("expected no focusables, got %d", len(Manager().GetAll()))
+	}
+	if HandleKey("q") || globalCalls != 0 {
+		t.Error("expected the global key handler to be removed")
+	}
+
+	// Fresh elements cycle with Tab and Shift+Tab
+	f1, f2 := newMockFocusable(), newMockFocusable()
+	Register(f1)
+	Register(f2)
+	for i, want := range []Focusable{f1, f2, f1} {
+		HandleKey(Tab)
+		if Manager().Current() != want {
+			t.Errorf("tab %d: expected %p focused, got %p", i, want, Manager().Current())
+		}
+	}
+	HandleKey(ShiftTab)
+	if Manager().Current() != f2 || !f2.Focused() || f1.Focused() {
+		t.Error("expected shift+tab to focus f2")
+	}
+	if old1.Focused() || old2.Focused() {
+		t.Error("expected cleared elements to stay blurred")
+	}
+}
+
+func TestFocusCallbacks_OnFocusFiresOnce(t *testing.T) {
+	setupTest(t)
+
+	focusCount := 0
+	btn := NewButton(ButtonOptions{OnFocus: func() { focusCount++ }})
+	defer btn.Dispose()
+
+	btn.Focus()
+	btn.Focus()
+	if focusCount != 1 {
+		t.Errorf("expected OnFocus once, got %d", focusCount)
+	}
+}
+
+func TestFocusCallbacks_OnBlurWhenAnotherGainsFocus(t *testing.T) {
+	setupTest(t)
+
+	blurCount := 0
+	focusCount := 0
+	inp := NewInput(InputOptions{OnBlur: func() { blurCount++ }})
+	defer inp.Dispose()
+	sel := NewSelect(SelectOptions[string]{OnFocus: func() { focusCount++ }})
+	defer sel.Dispose()
+
+	inp.Focus()
+	if blurCount != 0 {
+		t.Errorf("expected no OnBlur yet, got %d", blurCount)
+	}
+
+	sel.Focus()
+	if blurCount != 1 {
+		t.Errorf("expected OnBlur once, got %d", blurCount)
+	}
+	if focusCount != 1 {
+		t.Errorf("expected select OnFocus once, got %d", focusCount)
+	}
+}
+
+func TestFocusCallbacks_OnBlurOnExplicitBlur(t *testing.T) {
+	setupTest(t)
+
+	var events []string
+	link := NewLink(LinkOptions{
+		URL:     "https://example.com",
+		OnFocus: func() { events = append(events, "focus") },
+		OnBlur:  func() { events = append(events, "blur") },
+	})
+	defer link.Dispose()
+
+	link.Focus()
+	link.Blur()
+	link.Blur()
+
+	if len(events) != 2 || events[0] != "focus" || events[1] != "blur" {
+		t.Errorf("expected [focus blur], got %v", events)
+	}
+}
+
+func TestLink_HandleShortcutGlobally(t *testing.T) {
+	setupTest(t)
+
+	clicks := 0
+	link := NewLink(LinkOptions{
+		OnClick:        func() { clicks++ },
+		ShortcutKey:    CtrlO,
+		HandleShortcut: true,
+	})
+
+	// Not focused: the global handler still activates it
+	HandleKey(CtrlO)
+	if clicks != 1 {
+		t.Errorf("expected 1 click, got %d", clicks)
+	}
+
+	link.Dispose()
+	HandleKey(CtrlO)
+	if clicks != 1 {
+		t.Errorf("expected shortcut removed on Dispose, got %d clicks", clicks)
+	}
+}
+
+func TestBoxKeyHandler_ActiveBoxReceivesUnconsumedKeys(t *testing.T) {
+	setupTest(t)
+
+	inp := NewInput(InputOptions{})
+	defer inp.Dispose()
+	inp.Focus()
+
+	var boxKeys []string
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{
+			"id":     "log",
+			"active": true,
+			"onKey": func(key string) bool {
+				boxKeys = append(boxKeys, key)
+				return key == PageUp
+			},
+		}, gox.Element("input", gox.Props{"input": inp, "width": 10}))
+	}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	if !HandleKey(PageUp) {
+		t.Error("expected PageUp to be consumed by the active box")
+	}
+	if !HandleKey("a") {
+		t.Error("expected 'a' to be consumed by the focused input")
+	}
+	if inp.Value() != "a" {
+		t.Errorf("expected input value 'a', got %q", inp.Value())
+	}
+	if len(boxKeys) != 1 || boxKeys[0] != PageUp {
+		t.Errorf("expected box to see only PageUp, got %q", boxKeys)
+	}
+}
+
+func TestBoxKeyHandler_InactiveBoxIgnored(t *testing.T) {
+	setupTest(t)
+
+	called := false
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{
+			"id":    "panel",
+			"onKey": func(key string) bool { called = true; return true },
+		})
+	}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	if HandleKey(PageDown) {
+		t.Error("expected PageDown to be unhandled")
+	}
+	if called {
+		t.Error("inactive box handler should not be called")
+	}
+}
+
+func TestFocusManager_TabFollowsReadingOrder(t *testing.T) {
+	setupTest(t)
+
+	// Registered column by column, laid out as a 2x2 grid
+	firstName := NewInput(InputOptions{})
+	email := NewInput(InputOptions{})
+	lastName := NewInput(InputOptions{})
+	phone := NewInput(InputOptions{})
+	for _, inp := range []*Input{firstName, email, lastName, phone} {
+		defer inp.Dispose()
+	}
+
+	field := func(inp *Input) gox.VNode {
+		return gox.Element("input", gox.Props{"input": inp, "width": 10, "height": 1})
+	}
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{"direction": "column"},
+			gox.Element("box", gox.Props{"direction": "row"}, field(firstName), field(lastName)),
+			gox.Element("box", gox.Props{"direction": "row"}, field(email), field(phone)),
+		)
+	}, Options{Width: 20, Height: 2, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	want := []*Input{firstName, lastName, email, phone}
+	for i, inp := range want {
+		HandleKey(Tab)
+		if !inp.Focused() {
+			t.Errorf("Tab %d: expected input %d in reading order to be focused", i+1, i)
+		}
+	}
+}
+
+func TestFocusManager_ExplicitTabIndexTakesPrecedence(t *testing.T) {
+	setupTest(t)
+
+	top := NewInput(InputOptions{})
+	bottom := NewInput(InputOptions{})
+	defer top.Dispose()
+	defer bottom.Dispose()
+
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{"direction": "column"},
+			gox.Element("input", gox.Props{"input": top, "width": 10, "height": 1}),
+			gox.Element("input", gox.Props{"input": bottom, "width": 10, "height": 1, "tabIndex": 1}),
+		)
+	}, Options{Width: 20, Height: 2, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	HandleKey(Tab)
+	if !bottom.Focused() {
+		t.Error("expected explicit tabIndex to be focused first")
+	}
+	HandleKey(Tab)
+	if !top.Focused() {
+		t.Error("expected layout order after explicit tabIndex")
+	}
+}
+
+func TestFocusHistory_BackAndForward(t *testing.T) {
+	setupTest(t)
+	a, b, c := newMockFocusable(), newMockFocusable(), newMockFocusable()
+	for _, f := range []*mockFocusable{a, b, c} {
+		Register(f)
+		f.Focus()
+	}
+
+	m := Manager()
+	if m.HistoryLen() != 3 || !m.CanGoBack() {
+		t.Fatalf("expected 3 entries with back available, got %d", m.HistoryLen())
+	}
+	if !m.Back() || !b.Focused() {
+		t.Error("expected Back to focus b")
+	}
+	if !m.Back() || !a.Focused() {
+		t.Error("expected Back to focus a")
+	}
+	if m.Back() || m.CanGoBack() {
+		t.Error("expected no more history before a")
+	}
+	if !m.Forward() || !b.Focused() {
+		t.Error("expected Forward to focus b")
+	}
+	if m.HistoryLen() != 3 {
+		t.Errorf("expected Back/Forward not to push, got %d entries", m.HistoryLen())
+	}
+}
+
+func TestFocusHistory_PushDiscardsForwardEntries(t *testing.T) {
+	setupTest(t)
+	a, b, c := newMockFocusable(), newMockFocusable(), newMockFocusable()
+	Register(a)
+	Register(b)
+	Register(c)
+
+	a.Focus()
+	b.Focus()
+	Manager().Back()
+	c.Focus()
+
+	if Manager().HistoryLen() != 2 || Manager().CanGoForward() {
+		t.Errorf("expected [a c] with no forward entries, got %d entries", Manager().HistoryLen())
+	}
+	Manager().Back()
+	if !a.Focused() {
+		t.Error("expected Back to return to a")
+	}
+}
+
+func TestFocusHistory_MaxLen(t *testing.T) {
+	setupTest(t)
+	Manager().HistoryMaxLen = 2
+	a, b, c := newMockFocusable(), newMockFocusable(), newMockFocusable()
+	for _, f := range []*mockFocusable{a, b, c} {
+		Register(f)
+		f.Focus()
+	}
+
+	if Manager().HistoryLen() != 2 {
+		t.Errorf("expected history capped at 2, got %d", Manager().HistoryLen())
+	}
+	Manager().Back()
+	if !b.Focused() || Manager().CanGoBack() {
+		t.Error("expected oldest entry to be dropped")
+	}
+}
+
+func TestFocusHistory_BackspaceNavigatesBack(t *testing.T) {
+	setupTest(t)
+	a, b := newMockFocusable(), newMockFocusable()
+	Register(a)
+	Register(b)
+	a.Focus()
+	b.Focus()
+
+	if HandleKey(Backspace) {
+		t.Error("expected Backspace to be unhandled by default")
+	}
+	Manager().BackNavOnBackspace = true
+	if !HandleKey(Backspace) || !a.Focused() {
+		t.Error("expected Backspace to go back")
+	}
+}
+
+func TestFocusHistory_DisposedEntriesRemoved(t *testing.T) {
+	setupTest(t)
+	a, b, c := newMockFocusable(), newMockFocusable(), newMockFocusable()
+	for _, f := range []*mockFocusable{a, b, c} {
+		Register(f)
+		f.Focus()
+	}
+
+	b.Dispose()
+	if Manager().HistoryLen() != 2 {
+		t.Errorf("expected disposed entry removed, got %d entries", Manager().HistoryLen())
+	}
+	Manager().Back()
+	if !a.Focused() {
+		t.Error("expected Back to skip the disposed entry")
+	}
+}
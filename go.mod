@@ -2,9 +2,9 @@ module github.com/germtb/goli
 
 go 1.23
 
-require github.com/germtb/gox v0.1.4
-
 require (
-	github.com/clipperhouse/uax29/v2 v2.2.0 // indirect
-	github.com/mattn/go-runewidth v0.0.19 // indirect
+	github.com/germtb/gox v0.1.4
+	github.com/mattn/go-runewidth v0.0.19
 )
+
+require github.com/clipperhouse/uax29/v2 v2.2.0 // indirect
@@ -0,0 +1,69 @@
+package goli
+
+import (
+	"bytes"
+	"strings"
+)
+
+// HeadlessRenderer is a Renderer that writes to memory, with helpers for
+// inspecting the rendered screen in tests.
+type HeadlessRenderer struct {
+	*Renderer
+	output *bytes.Buffer
+}
+
+// NewHeadless creates a renderer that keeps its ANSI output in memory.
+// opts.Output is ignored.
+//
+// Example:
+//
+//	app := goli.Render(view, goli.Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+//	app.Headless().AssertContains(t, "Submit")
+func NewHeadless(opts Options) *HeadlessRenderer {
+	output := &bytes.Buffer{}
+	opts.Output = output
+	return &HeadlessRenderer{Renderer: NewRenderer(opts), output: output}
+}
+
+// AnsiOutput returns everything written to the terminal so far.
+func (h *HeadlessRenderer) AnsiOutput() string {
+	return h.output.String()
+}
+
+// PlainOutput returns the current screen as text, one line per row with
+// trailing spaces removed.
+func (h *HeadlessRenderer) PlainOutput() string {
+	lines := strings.Split(h.CurrentBuffer().ToDebugString(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ContainsText reports whether s appears on the current screen.
+// Matches spanning rows must include the "\n" between them.
+func (h *HeadlessRenderer) ContainsText(s string) bool {
+	return strings.Contains(h.PlainOutput(), s)
+}
+
+// GetCell returns the cell at (x, y) on the current screen.
+func (h *HeadlessRenderer) GetCell(x, y int) Cell {
+	return h.CurrentBuffer().Get(x, y)
+}
+
+// AssertCell fails t unless the cell at (x, y) has the given char and style.
+func (h *HeadlessRenderer) AssertCell(t TB, x, y int, char rune, style Style) {
+	t.Helper()
+	cell := h.GetCell(x, y)
+	if cell.Char != char || !cell.Style.Equal(style) {
+		t.Errorf("expected %q %+v at (%d,%d), got %q %+v", char, style, x, y, cell.Char, cell.Style)
+	}
+}
+
+// AssertContains fails t unless text appears on the current screen.
+func (h *HeadlessRenderer) AssertContains(t TB, text string) {
+	t.Helper()
+	if !h.ContainsText(text) {
+		t.Errorf("expected screen to contain %q, got:\n%s", text, h.PlainOutput())
+	}
+}
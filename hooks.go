@@ -0,0 +1,154 @@
+package goli
+
+import "sync"
+
+// visibilityMu guards every visibilityTracker and the watchers in them.
+var visibilityMu sync.Mutex
+
+// visibilityTracker holds watchers created by CreateVisibilityEffect. Each
+// App has one that it updates from its own frames; Runtime.visibility holds
+// the watchers that no App has drawn a frame for yet.
+type visibilityTracker struct {
+	watchers map[*visibilityWatcher]struct{}
+}
+
+type visibilityWatcher struct {
+	id         string
+	visible    bool               // Last value set
+	tracker    *visibilityTracker // Holding this watcher, nil once removed
+	setVisible Setter[bool]
+	fn         func(visible bool)
+}
+
+// CreateVisibilityEffect returns an accessor that is true while the box
+// whose "id" prop is id is visible: at least one of its cells is on screen
+// and inside the content area of every overflow hidden/scroll ancestor.
+// fn, when non-nil, is called with the new value each time it changes.
+//
+// The watcher follows the App that draws the next frame after it is
+// created, so it starts false and follows the box across that App's
+// renders even though layout boxes are rebuilt every frame. It is not
+// updated by renderers that lay out asynchronously, such as
+// PipelineRenderer.
+//
+// CreateVisibilityEffect must be called with an owner (e.g., inside
+// CreateRoot) and panics otherwise; the watcher is removed when the owner
+// is disposed, or when its App is.
+//
+// Example:
+//
+//	visible := goli.CreateVisibilityEffect("row-42", func(visible bool) {
+//	    if visible {
+//	        loadRow(42)
+//	    }
+//	})
+//
+//	// In the app's VNode tree:
+//	<box id="row-42">{visible() ? details : placeholder}</box>
+func CreateVisibilityEffect(id string, fn func(visible bool)) Accessor[bool] {
+	if GetOwner() == nil {
+		panic("goli: CreateVisibilityEffect called without an owner")
+	}
+	visible, setVisible := CreateSignalWithEquals(false, func(a, b bool) bool { return a == b })
+	w := &visibilityWatcher{id: id, setVisible: setVisible, fn: fn}
+
+	visibilityMu.Lock()
+	Global.visibility.add(w)
+	visibilityMu.Unlock()
+
+	OnCleanup(func() {
+		visibilityMu.Lock()
+		defer visibilityMu.Unlock()
+		if w.tracker != nil {
+			delete(w.tracker.watchers, w)
+			w.tracker = nil
+		}
+	})
+	return visible
+}
+
+// add registers w with t. Must be called with visibilityMu held.
+func (t *visibilityTracker) add(w *visibilityWatcher) {
+	if t.watchers == nil {
+		t.watchers = make(map[*visibilityWatcher]struct{})
+	}
+	t.watchers[w] = struct{}{}
+	w.tracker = t
+}
+
+// clear removes every watcher from t.
+func (t *visibilityTracker) clear() {
+	visibilityMu.Lock()
+	defer visibilityMu.Unlock()
+	for w := range t.watchers {
+		w.tracker = nil
+	}
+	t.watchers = nil
+}
+
+// update takes over the watchers waiting in unbound, then sets every
+// watcher from the boxes visible in root within viewport, calling the
+// callbacks of those that changed in one batch.
+func (t *visibilityTracker) update(unbound *visibilityTracker, root *LayoutBox, viewport ClipRegion) {
+	visibilityMu.Lock()
+	for w := range unbound.watchers {
+		t.add(w)
+	}
+	unbound.watchers = nil
+	if len(t.watchers) == 0 {
+		visibilityMu.Unlock()
+		return
+	}
+	visibleIDs := make(map[string]bool)
+	collectVisibleIDs(root, viewport, visibleIDs)
+
+	var changed []*visibilityWatcher
+	for w := range t.watchers {
+		if visible := visibleIDs[w.id]; visible != w.visible {
+			w.visible = visible
+			changed = append(changed, w)
+		}
+	}
+	visibilityMu.Unlock()
+
+	BatchVoid(func() {
+		for _, w := range changed {
+			visible := visibleIDs[w.id]
+			w.setVisible(visible)
+			if w.fn != nil {
+				w.fn(visible)
+			}
+		}
+	})
+}
+
+// collectVisibleIDs records the "id" of every box under box with a cell
+// inside clip. Children of overflow hidden/scroll boxes are clipped to
+// their parent's content area, as when rendering.
+func collectVisibleIDs(box *LayoutBox, clip ClipRegion, visible map[string]bool) {
+	if box == nil {
+		return
+	}
+	if id := GetStringProp(box.Node.Props, "id", ""); id != "" && boxIntersects(box, clip) {
+		visible[id] = true
+	}
+
+	if overflow := GetOverflow(box.Node.Props); overflow == OverflowHidden || overflow == OverflowScroll {
+		clip = *IntersectClip(&clip, &ClipRegion{
+			MinX: box.InnerX,
+			MinY: box.InnerY,
+			MaxX: box.InnerX + box.InnerWidth,
+			MaxY: box.InnerY + box.InnerHeight,
+		})
+	}
+	for _, child := range box.Children {
+		collectVisibleIDs(child, clip, visible)
+	}
+}
+
+// boxIntersects reports whether box has at least one cell inside clip.
+func boxIntersects(box *LayoutBox, clip ClipRegion) bool {
+	minX, minY := max(box.X, clip.MinX), max(box.Y, clip.MinY)
+	maxX, maxY := min(box.X+box.Width, clip.MaxX), min(box.Y+box.Height, clip.MaxY)
+	return minX < maxX && minY < maxY
+}
@@ -0,0 +1,159 @@
+package goli
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+func idRows(n int) []gox.VNode {
+	rows := make([]gox.VNode, n)
+	for i := range rows {
+		rows[i] = gox.Element("box", gox.Props{"id": fmt.Sprintf("row-%d", i), "height": 1},
+			gox.Element("text", nil, gox.Text(fmt.Sprintf("row %d", i))),
+		)
+	}
+	return rows
+}
+
+func TestCollectVisibleIDs_ClipsToOverflowAncestors(t *testing.T) {
+	node := gox.Element("box", nil,
+		gox.Element("box", gox.Props{"overflow": "hidden", "height": 3}, idRows(5)...),
+		gox.Element("box", gox.Props{"id": "offscreen", "position": "absolute", "x": 50, "y": 0, "width": 2, "height": 1}),
+		gox.Element("box", gox.Props{"id": "empty", "height": 0}),
+	)
+	root := ComputeLayout(node, LayoutContext{Width: 20, Height: 10}).Tree()
+
+	visible := make(map[string]bool)
+	collectVisibleIDs(root, ClipRegion{MaxX: 20, MaxY: 10}, visible)
+	for _, id := range []string{"row-0", "row-1", "row-2"} {
+		if !visible[id] {
+			t.Errorf("expected %s visible", id)
+		}
+	}
+	for _, id := range []string{"row-3", "row-4", "offscreen", "empty"} {
+		if visible[id] {
+			t.Errorf("expected %s not visible", id)
+		}
+	}
+}
+
+func TestCreateVisibilityEffect(t *testing.T) {
+	Reset()
+	height, setHeight := CreateSignal(2)
+
+	var changes []bool
+	var visible Accessor[bool]
+	disposeRoot := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		visible = CreateVisibilityEffect("row-3", func(v bool) { changes = append(changes, v) })
+		return dispose
+	})
+
+	app := Render(func() gox.VNode {
+		status := "hidden"
+		if visible() {
+			status = "shown"
+		}
+		return gox.Element("box", nil,
+			gox.Element("text", nil, gox.Text("row-3 "+status)),
+			gox.Element("box", gox.Props{"overflow": "hidden", "height": height()}, idRows(5)...),
+		)
+	}, Options{Width: 20, Height: 8, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+	app.WaitForRender(time.Second)
+
+	if visible() || len(changes) != 0 {
+		t.Errorf("expected row-3 hidden with no callbacks, got %v and %v", visible(), changes)
+	}
+
+	setHeight(5)
+	app.WaitForRender(time.Second)
+	if !visible() || len(changes) != 1 || !changes[0] {
+		t.Errorf("expected row-3 visible after growing the list, got %v and %v", visible(), changes)
+	}
+	app.Headless().AssertContains(t, "row-3 shown")
+
+	setHeight(1)
+	app.WaitForRender(time.Second)
+	if visible() || len(changes) != 2 || changes[1] {
+		t.Errorf("expected row-3 hidden again, got %v and %v", visible(), changes)
+	}
+
+	disposeRoot()
+	setHeight(5)
+	app.WaitForRender(time.Second)
+	if len(changes) != 2 {
+		t.Errorf("expected no callbacks after dispose, got %v", changes)
+	}
+}
+
+func TestCreateVisibilityEffect_FollowsOneApp(t *testing.T) {
+	Reset()
+	var changes []bool
+	var visible Accessor[bool]
+	disposeRoot := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		visible = CreateVisibilityEffect("row-0", func(v bool) { changes = append(changes, v) })
+		return dispose
+	})
+	defer disposeRoot()
+
+	shown := Render(func() gox.VNode {
+		return gox.Element("box", nil, idRows(1)...)
+	}, Options{Width: 20, Height: 4, Headless: true, DisableThrottle: true})
+	defer shown.Dispose()
+	shown.WaitForRender(time.Second)
+	if !visible() {
+		t.Fatal("expected row-0 visible in the first app")
+	}
+
+	// A second app without the box must not hide it
+	other := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("other"))
+	}, Options{Width: 20, Height: 4, Headless: true, DisableThrottle: true})
+	other.WaitForRender(time.Second)
+	other.Rerender()
+	shown.Rerender()
+	other.Dispose()
+
+	if !visible() || len(changes) != 1 {
+		t.Errorf("expected row-0 to stay visible with one callback, got %v and %v", visible(), changes)
+	}
+}
+
+func TestCreateVisibilityEffect_DroppedWithApp(t *testing.T) {
+	Reset()
+	var visible Accessor[bool]
+	disposeRoot := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		visible = CreateVisibilityEffect("row-0", nil)
+		return dispose
+	})
+	defer disposeRoot()
+
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil, idRows(1)...)
+	}, Options{Width: 20, Height: 4, Headless: true, DisableThrottle: true})
+	app.WaitForRender(time.Second)
+	app.Dispose()
+
+	next := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("next"))
+	}, Options{Width: 20, Height: 4, Headless: true, DisableThrottle: true})
+	defer next.Dispose()
+	next.WaitForRender(time.Second)
+
+	if !visible() {
+		t.Error("expected the watcher of a disposed app not to follow the next app")
+	}
+}
+
+func TestCreateVisibilityEffect_RequiresOwner(t *testing.T) {
+	Reset()
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic without an owner")
+		}
+	}()
+	CreateVisibilityEffect("row-0", nil)
+}
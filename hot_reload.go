@@ -0,0 +1,149 @@
+package goli
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+// Watcher reports file changes for hot reloading.
+type Watcher interface {
+	// Changes delivers a value whenever a watched file changes.
+	Changes() <-chan struct{}
+	// Close stops watching and closes the Changes channel.
+	Close()
+}
+
+// DefaultPollInterval is how often NewPollingWatcher checks for changes.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// pollingWatcher detects changes by comparing modification times.
+type pollingWatcher struct {
+	paths    []string
+	interval time.Duration
+	changes  chan struct{}
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// NewPollingWatcher watches files (directories are walked recursively) by
+// polling their modification times. It needs no platform-specific
+// notification support, at the cost of up to one interval of latency.
+func NewPollingWatcher(paths []string, interval time.Duration) Watcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	w := &pollingWatcher{
+		paths:    paths,
+		interval: interval,
+		changes:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *pollingWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *pollingWatcher) Close() {
+	w.once.Do(func() { close(w.stop) })
+}
+
+func (w *pollingWatcher) run() {
+	defer close(w.changes)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	last := w.snapshot()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			current := w.snapshot()
+			if !sameSnapshot(last, current) {
+				// Coalesce: one pending notification is enough
+				select {
+				case w.changes <- struct{}{}:
+				default:
+				}
+			}
+			last = current
+		}
+	}
+}
+
+// snapshot records the modification time of every watched file.
+func (w *pollingWatcher) snapshot() map[string]time.Time {
+	result := make(map[string]time.Time)
+	for _, root := range w.paths {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				result[path] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return result
+}
+
+func sameSnapshot(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, t := range a {
+		if other, ok := b[path]; !ok || !other.Equal(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// WatchAndReload runs the app like Run and reloads it whenever a file under
+// paths changes. On each change, opts.PreReload is called, the app's
+// reactive root is disposed and appFn re-runs in a fresh root, then
+// opts.PostReload is called. Signals defined outside appFn survive reloads.
+//
+// Example:
+//
+//	goli.WatchAndReload(App, goli.RunOptions{}, []string{"./theme.json"})
+func WatchAndReload(appFn func() gox.VNode, opts RunOptions, paths []string) {
+	WatchAndReloadWith(appFn, opts, NewPollingWatcher(paths, DefaultPollInterval))
+}
+
+// WatchAndReloadWith is like WatchAndReload but uses the given watcher.
+// The watcher is closed when the app exits, before the app is disposed.
+func WatchAndReloadWith(appFn func() gox.VNode, opts RunOptions, w Watcher) {
+	onMount := opts.OnMount
+	opts.OnMount = func(app *App) {
+		app.addBeforeDispose(w.Close)
+		go reloadOnChanges(app, w, opts.PreReload, opts.PostReload)
+		if onMount != nil {
+			onMount(app)
+		}
+	}
+
+	Run(appFn, opts)
+}
+
+// reloadOnChanges reloads app for every change until the watcher closes.
+func reloadOnChanges(app *App, w Watcher, preReload, postReload func()) {
+	for range w.Changes() {
+		if preReload != nil {
+			preReload()
+		}
+		app.Reload()
+		if postReload != nil {
+			postReload()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package goli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+// fakeWatcher lets tests deliver change events in-process.
+type fakeWatcher struct {
+	changes chan struct{}
+}
+
+func (w *fakeWatcher) Changes() <-chan struct{} { return w.changes }
+func (w *fakeWatcher) Close()                   { close(w.changes) }
+
+func TestReloadOnChanges_RerunsAppFn(t *testing.T) {
+	Reset()
+
+	// Global signal survives reloads
+	count, setCount := CreateSignal(7)
+	mounts := 0
+
+	var output strings.Builder
+	app := Render(func() gox.VNode {
+		mounts++
+		return gox.Element("text", nil, gox.Text(strings.Repeat("x", count())))
+	}, Options{Width: 20, Height: 2, Output: &output, DisableThrottle: true})
+	defer app.Dispose()
+
+	w := &fakeWatcher{changes: make(chan struct{})}
+	var events []string
+	done := make(chan struct{})
+	go func() {
+		reloadOnChanges(app, w,
+			func() { events = append(events, "pre") },
+			func() { events = append(events, "post") })
+		close(done)
+	}()
+
+	w.changes <- struct{}{}
+	w.Close()
+	<-done
+
+	if mounts != 2 {
+		t.Errorf("expected appFn to run twice, got %d", mounts)
+	}
+	if len(events) != 2 || events[0] != "pre" || events[1] != "post" {
+		t.Errorf("expected [pre post], got %v", events)
+	}
+
+	// The reloaded root is reactive and the old one is gone
+	setCount(3)
+	if mounts != 3 {
+		t.Errorf("expected one re-run after signal change, got %d total", mounts)
+	}
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.CurrentBuffer().ToDebugString(); !strings.HasPrefix(got, "xxx ") {
+		t.Errorf("expected rerendered output, got %q", got)
+	}
+}
+
+func TestApp_ReloadAfterDispose(t *testing.T) {
+	Reset()
+	mounts := 0
+	app := Render(func() gox.VNode {
+		mounts++
+		return gox.Element("text", nil, gox.Text("x"))
+	}, Options{Width: 10, Height: 1, Headless: true, DisableThrottle: true})
+
+	app.Dispose()
+	app.Reload()
+	if mounts != 1 {
+		t.Errorf("expected Reload after Dispose not to re-mount, got %d mounts", mounts)
+	}
+}
+
+func TestApp_ReloadConcurrentWithDispose(t *testing.T) {
+	Reset()
+	app := Render(func() gox.VNode {
+		return gox.Element("text", nil, gox.Text("x"))
+	}, Options{Width: 10, Height: 1, Headless: true, DisableThrottle: true})
+
+	var stopped []string
+	app.addBeforeDispose(func() { stopped = append(stopped, "watcher") })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			app.Reload()
+		}
+	}()
+	app.Dispose()
+	<-done
+
+	if len(stopped) != 1 {
+		t.Errorf("expected the watcher stopped once, got %v", stopped)
+	}
+	app.addBeforeDispose(func() { stopped = append(stopped, "late") })
+	if len(stopped) != 2 || stopped[1] != "late" {
+		t.Errorf("expected a stop registered after Dispose to run immediately, got %v", stopped)
+	}
+}
+
+func TestPollingWatcher_DetectsModification(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.txt")
+	if err := os.WriteFile(path, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewPollingWatcher([]string{dir}, 5*time.Millisecond)
+	defer w.Close()
+
+	time.Sleep(20 * time.Millisecond)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-w.Changes():
+	case <-time.After(time.Second):
+		t.Fatal("expected a change notification")
+	}
+}
@@ -2,12 +2,15 @@
 package goli
 
 import (
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // InputState represents the state of an input field.
 type InputState struct {
-	Value     string
+	Value string
+	// CursorPos is the cursor position as a rune (not byte) index into Value.
 	CursorPos int
 }
 
@@ -19,6 +22,10 @@ type InputKeyHandler func(key string, state InputState) *InputState
 type InputOptions struct {
 	// InitialValue is the starting text.
 	InitialValue string
+	// Value, when set, binds the text to an external signal instead of an
+	// internal one; InitialValue is ignored. Edits are written through
+	// Value.Set, and changes made elsewhere show up in the input.
+	Value ReadWrite[string]
 	// MaxLength limits the number of characters (0 = unlimited).
 	MaxLength int
 	// Mask character for passwords (e.g., "*").
@@ -27,6 +34,16 @@ type InputOptions struct {
 	Placeholder string
 	// OnKeypress is a custom keypress handler.
 	OnKeypress InputKeyHandler
+	// Multiline makes Enter insert a newline (default handler only).
+	Multiline bool
+	// MaxLines limits the number of lines (0 = unlimited).
+	MaxLines int
+	// OnFocus is called when the input gains focus.
+	OnFocus func()
+	// OnBlur is called when the input loses focus.
+	OnBlur func()
+	// CompleteFunc enables Tab completion (see input_completion.go).
+	CompleteFunc CompleteFunc
 }
 
 // Input represents a text input field.
@@ -39,20 +56,32 @@ type Input struct {
 	setFocused Setter[bool]
 
 	maxLength   int
+	maxLines    int
+	multiline   bool
 	mask        rune
 	placeholder string
 	onKeypress  InputKeyHandler
+	onFocus     func()
+	onBlur      func()
+	completion  *completionState
 }
 
 // NewInput creates a new input field.
 func NewInput(opts InputOptions) *Input {
-	value, setValue := CreateSignal(opts.InitialValue)
-	cursorPos, setCursor := CreateSignal(len(opts.InitialValue))
+	value, setValue := opts.Value.Get, opts.Value.Set
+	if value == nil || setValue == nil {
+		value, setValue = CreateSignal(opts.InitialValue)
+	}
+	cursorPos, setCursor := CreateSignal(utf8.RuneCountInString(Untrack(value)))
 	focused, setFocused := CreateSignal(false)
 
 	handler := opts.OnKeypress
 	if handler == nil {
-		handler = DefaultInputHandler
+		if opts.Multiline {
+			handler = MultilineInputHandler(opts.MaxLines)
+		} else {
+			handler = DefaultInputHandler
+		}
 	}
 
 	inp := &Input{
@@ -63,9 +92,14 @@ func NewInput(opts InputOptions) *Input {
 		focused:     focused,
 		setFocused:  setFocused,
 		maxLength:   opts.MaxLength,
+		maxLines:    opts.MaxLines,
+		multiline:   opts.Multiline,
 		mask:        opts.Mask,
 		placeholder: opts.Placeholder,
 		onKeypress:  handler,
+		onFocus:     opts.OnFocus,
+		onBlur:      opts.OnBlur,
+		completion:  newCompletionState(opts.CompleteFunc),
 	}
 
 	// Register with focus manager
@@ -79,9 +113,25 @@ func (i *Input) Value() string {
 	return i.value()
 }
 
-// CursorPos returns the cursor position.
+// CursorPos returns the cursor position as a rune index. It never exceeds
+// the value's length, even after a bound value was shortened elsewhere.
 func (i *Input) CursorPos() int {
-	return i.cursorPos()
+	return min(i.cursorPos(), utf8.RuneCountInString(i.value()))
+}
+
+// CursorLineCol returns the cursor's line index and rune column.
+func (i *Input) CursorLineCol() (line, col int) {
+	return RuneOffsetToLineCol(i.value(), i.CursorPos())
+}
+
+// LineCount returns the number of lines in the current value.
+func (i *Input) LineCount() int {
+	return strings.Count(i.value(), "\n") + 1
+}
+
+// Multiline returns whether Enter inserts newlines.
+func (i *Input) Multiline() bool {
+	return i.multiline
 }
 
 // Focused returns whether the input is focused.
@@ -101,7 +151,9 @@ func (i *Input) Blur() {
 
 // SetFocused sets the focused state (called by focus manager).
 func (i *Input) SetFocused(f bool) {
+	wasFocused := Untrack(func() bool { return i.focused() })
 	i.setFocused(f)
+	notifyFocusChange(wasFocused, f, i.onFocus, i.onBlur)
 }
 
 // Dispose unregisters from the focus manager.
@@ -116,6 +168,10 @@ func (i *Input) HandleKey(key string) bool {
 		return false
 	}
 
+	if i.handleCompletionKey(key) {
+		return true
+	}
+
 	state := i.GetState()
 	newState := i.onKeypress(key, state)
 	if newState == nil {
@@ -127,16 +183,16 @@ func (i *Input) HandleKey(key string) bool {
 
 // SetValue updates the text value.
 func (i *Input) SetValue(value string) {
-	limited := i.applyMaxLength(value)
+	limited := i.applyMaxLines(i.applyMaxLength(value))
 	BatchVoid(func() {
 		i.setValue(limited)
-		i.setCursor(i.clampCursor(i.cursorPos(), len(limited)))
+		i.setCursor(i.clampCursor(i.CursorPos(), utf8.RuneCountInString(limited)))
 	})
 }
 
-// SetCursorPos updates the cursor position.
+// SetCursorPos updates the cursor position, given as a rune index.
 func (i *Input) SetCursorPos(pos int) {
-	i.setCursor(i.clampCursor(pos, len(i.value())))
+	i.setCursor(i.clampCursor(pos, utf8.RuneCountInString(i.value())))
 }
 
 // Clear clears the input.
@@ -154,9 +210,14 @@ func (i *Input) DisplayValue() string {
 		return i.placeholder
 	}
 	if i.mask != 0 {
-		masked := make([]rune, len(val))
-		for j := range masked {
-			masked[j] = i.mask
+		// One mask rune per character; newlines keep the line structure
+		masked := make([]rune, 0, len(val))
+		for _, r := range val {
+			if r == '\n' {
+				masked = append(masked, r)
+			} else {
+				masked = append(masked, i.mask)
+			}
 		}
 		return string(masked)
 	}
@@ -172,13 +233,13 @@ func (i *Input) ShowingPlaceholder() bool {
 func (i *Input) GetState() InputState {
 	return InputState{
 		Value:     i.value(),
-		CursorPos: i.cursorPos(),
+		CursorPos: i.CursorPos(),
 	}
 }
 
 func (i *Input) setState(state InputState) {
-	limited := i.applyMaxLength(state.Value)
-	clamped := i.clampCursor(state.CursorPos, len(limited))
+	limited := i.applyMaxLines(i.applyMaxLength(state.Value))
+	clamped := i.clampCursor(state.CursorPos, utf8.RuneCountInString(limited))
 	BatchVoid(func() {
 		i.setValue(limited)
 		i.setCursor(clamped)
@@ -186,8 +247,26 @@ func (i *Input) setState(state InputState) {
 }
 
 func (i *Input) applyMaxLength(val string) string {
-	if i.maxLength > 0 && len(val) > i.maxLength {
-		return val[:i.maxLength]
+	if i.maxLength > 0 && utf8.RuneCountInString(val) > i.maxLength {
+		return string([]rune(val)[:i.maxLength])
+	}
+	return val
+}
+
+func (i *Input) applyMaxLines(val string) string {
+	if i.maxLines <= 0 {
+		return val
+	}
+	idx := 0
+	for n := 0; n < i.maxLines; n++ {
+		next := strings.IndexByte(val[idx:], '\n')
+		if next < 0 {
+			return val
+		}
+		if n == i.maxLines-1 {
+			return val[:idx+next]
+		}
+		idx += next + 1
 	}
 	return val
 }
@@ -226,10 +305,11 @@ func ComposeInputHandlers(handlers ...InputKeyHandler) InputKeyHandler {
 // InputPrintableHandler inserts printable characters at cursor.
 func InputPrintableHandler(key string, state InputState) *InputState {
 	if len(key) >= 1 && isPrintable(key) {
-		newValue := state.Value[:state.CursorPos] + key + state.Value[state.CursorPos:]
+		runes := []rune(state.Value)
+		pos := clampRuneIndex(state.CursorPos, len(runes))
 		return &InputState{
-			Value:     newValue,
-			CursorPos: state.CursorPos + len(key),
+			Value:     spliceRunes(runes, pos, pos, key),
+			CursorPos: pos + utf8.RuneCountInString(key),
 		}
 	}
 	return nil
@@ -237,59 +317,62 @@ func InputPrintableHandler(key string, state InputState) *InputState {
 
 // InputNavigationHandler handles arrow keys, home/end, word navigation.
 func InputNavigationHandler(key string, state InputState) *InputState {
+	runes := []rune(state.Value)
+	pos := clampRuneIndex(state.CursorPos, len(runes))
+
 	switch key {
 	case Left:
-		if state.CursorPos > 0 {
-			return &InputState{Value: state.Value, CursorPos: state.CursorPos - 1}
+		if pos > 0 {
+			return &InputState{Value: state.Value, CursorPos: pos - 1}
 		}
 		return &state
 
 	case Right:
-		if state.CursorPos < len(state.Value) {
-			return &InputState{Value: state.Value, CursorPos: state.CursorPos + 1}
+		if pos < len(runes) {
+			return &InputState{Value: state.Value, CursorPos: pos + 1}
 		}
 		return &state
 
 	case AltLeft, AltLeftCSI:
 		// Move to start of previous word
-		newPos := state.CursorPos
-		for newPos > 0 && !isWordChar(rune(state.Value[newPos-1])) {
+		newPos := pos
+		for newPos > 0 && !isWordChar(runes[newPos-1]) {
 			newPos--
 		}
-		for newPos > 0 && isWordChar(rune(state.Value[newPos-1])) {
+		for newPos > 0 && isWordChar(runes[newPos-1]) {
 			newPos--
 		}
 		return &InputState{Value: state.Value, CursorPos: newPos}
 
 	case AltRight, AltRightCSI:
 		// Move to end of next word
-		newPos := state.CursorPos
-		for newPos < len(state.Value) && !isWordChar(rune(state.Value[newPos])) {
+		newPos := pos
+		for newPos < len(runes) && !isWordChar(runes[newPos]) {
 			newPos++
 		}
-		for newPos < len(state.Value) && isWordChar(rune(state.Value[newPos])) {
+		for newPos < len(runes) && isWordChar(runes[newPos]) {
 			newPos++
 		}
 		return &InputState{Value: state.Value, CursorPos: newPos}
 
 	case Home, HomeAlt, CtrlA:
-		lineStart := getLineStart(state.Value, state.CursorPos)
+		lineStart := getLineStart(runes, pos)
 		return &InputState{Value: state.Value, CursorPos: lineStart}
 
 	case End, EndAlt, CtrlE:
-		lineEnd := getLineEnd(state.Value, state.CursorPos)
+		lineEnd := getLineEnd(runes, pos)
 		return &InputState{Value: state.Value, CursorPos: lineEnd}
 
 	case Up:
-		newPos := moveCursorUp(state.Value, state.CursorPos)
-		if newPos != state.CursorPos {
+		newPos := moveCursorUp(runes, pos)
+		if newPos != pos {
 			return &InputState{Value: state.Value, CursorPos: newPos}
 		}
 		return &state
 
 	case Down:
-		newPos := moveCursorDown(state.Value, state.CursorPos)
-		if newPos != state.CursorPos {
+		newPos := moveCursorDown(runes, pos)
+		if newPos != pos {
 			return &InputState{Value: state.Value, CursorPos: newPos}
 		}
 		return &state
@@ -298,49 +381,74 @@ func InputNavigationHandler(key string, state InputState) *InputState {
 	return nil
 }
 
-// InputDeletionHandler handles backspace, delete, word delete.
+// InputDeletionHandler handles backspace, delete, word and line deletion,
+// and transposing characters.
 func InputDeletionHandler(key string, state InputState) *InputState {
+	runes := []rune(state.Value)
+	pos := clampRuneIndex(state.CursorPos, len(runes))
+
 	switch key {
 	case Backspace, BackspaceCtrl:
-		if state.CursorPos == 0 {
+		if pos == 0 {
 			return &state
 		}
 		return &InputState{
-			Value:     state.Value[:state.CursorPos-1] + state.Value[state.CursorPos:],
-			CursorPos: state.CursorPos - 1,
+			Value:     spliceRunes(runes, pos-1, pos, ""),
+			CursorPos: pos - 1,
 		}
 
-	case Delete:
-		if state.CursorPos >= len(state.Value) {
+	case Delete, CtrlD:
+		if pos >= len(runes) {
 			return &state
 		}
 		return &InputState{
-			Value:     state.Value[:state.CursorPos] + state.Value[state.CursorPos+1:],
-			CursorPos: state.CursorPos,
+			Value:     spliceRunes(runes, pos, pos+1, ""),
+			CursorPos: pos,
 		}
 
 	case CtrlU:
 		// Delete from cursor to start of line
-		lineStart := getLineStart(state.Value, state.CursorPos)
+		lineStart := getLineStart(runes, pos)
 		return &InputState{
-			Value:     state.Value[:lineStart] + state.Value[state.CursorPos:],
+			Value:     spliceRunes(runes, lineStart, pos, ""),
 			CursorPos: lineStart,
 		}
 
+	case CtrlK:
+		// Delete from cursor to end of line
+		lineEnd := getLineEnd(runes, pos)
+		return &InputState{
+			Value:     spliceRunes(runes, pos, lineEnd, ""),
+			CursorPos: pos,
+		}
+
+	case CtrlT:
+		// Swap the characters before and at the cursor, or the last two
+		// at the end of a line, and move past them
+		i := pos
+		if i == getLineEnd(runes, pos) {
+			i--
+		}
+		if i-1 < getLineStart(runes, pos) {
+			return &state
+		}
+		runes[i-1], runes[i] = runes[i], runes[i-1]
+		return &InputState{Value: string(runes), CursorPos: i + 1}
+
 	case CtrlW, AltBackspace:
 		// Delete previous word
-		if state.CursorPos == 0 {
+		if pos == 0 {
 			return &state
 		}
-		newPos := state.CursorPos
-		for newPos > 0 && !isWordChar(rune(state.Value[newPos-1])) {
+		newPos := pos
+		for newPos > 0 && !isWordChar(runes[newPos-1]) {
 			newPos--
 		}
-		for newPos > 0 && isWordChar(rune(state.Value[newPos-1])) {
+		for newPos > 0 && isWordChar(runes[newPos-1]) {
 			newPos--
 		}
 		return &InputState{
-			Value:     state.Value[:newPos] + state.Value[state.CursorPos:],
+			Value:     spliceRunes(runes, newPos, pos, ""),
 			CursorPos: newPos,
 		}
 	}
@@ -351,30 +459,99 @@ func InputDeletionHandler(key string, state InputState) *InputState {
 // InputNewlineHandler inserts newline on Enter (for multiline editors).
 func InputNewlineHandler(key string, state InputState) *InputState {
 	if key == Enter || key == EnterLF || key == ShiftEnter {
-		return &InputState{
-			Value:     state.Value[:state.CursorPos] + "\n" + state.Value[state.CursorPos:],
-			CursorPos: state.CursorPos + 1,
-		}
+		return insertNewline(state)
 	}
 	return nil
 }
 
+// insertNewline inserts a newline at the cursor.
+func insertNewline(state InputState) *InputState {
+	runes := []rune(state.Value)
+	pos := clampRuneIndex(state.CursorPos, len(runes))
+	return &InputState{
+		Value:     spliceRunes(runes, pos, pos, "\n"),
+		CursorPos: pos + 1,
+	}
+}
+
+// InputNewlineHandlerWithMaxLines is like InputNewlineHandler but lets the
+// key bubble up once the value already has maxLines lines (0 = unlimited).
+func InputNewlineHandlerWithMaxLines(maxLines int) InputKeyHandler {
+	return func(key string, state InputState) *InputState {
+		if maxLines > 0 && strings.Count(state.Value, "\n")+1 >= maxLines {
+			return nil
+		}
+		return InputNewlineHandler(key, state)
+	}
+}
+
+// MultilineInputHandler is the default handler for multiline inputs:
+// like DefaultInputHandler, but bare Enter also inserts a newline.
+func MultilineInputHandler(maxLines int) InputKeyHandler {
+	return ComposeInputHandlers(
+		InputNavigationHandler,
+		InputDeletionHandler,
+		InputNewlineHandlerWithMaxLines(maxLines),
+		InputPrintableHandler,
+	)
+}
+
 // InputShiftEnterHandler inserts newline only on Shift+Enter.
 func InputShiftEnterHandler(key string, state InputState) *InputState {
 	if key == ShiftEnter || key == EnterLF {
-		return &InputState{
-			Value:     state.Value[:state.CursorPos] + "\n" + state.Value[state.CursorPos:],
-			CursorPos: state.CursorPos + 1,
-		}
+		return insertNewline(state)
 	}
 	return nil
 }
 
 // Helper functions
 
+// RuneOffsetToLineCol maps a rune offset in s to a line index and a rune
+// column within that line. Offsets past the end map to the end of s.
+func RuneOffsetToLineCol(s string, offset int) (line, col int) {
+	for _, r := range s {
+		if offset <= 0 {
+			break
+		}
+		offset--
+		if r == '\n' {
+			line++
+			col = 0
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+// RuneOffsetToByteOffset converts a rune index in s to a byte offset.
+// Offsets past the end map to len(s).
+func RuneOffsetToByteOffset(s string, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	n := 0
+	for i := range s {
+		if n == offset {
+			return i
+		}
+		n++
+	}
+	return len(s)
+}
+
+// spliceRunes returns runes with [start, end) replaced by insert.
+func spliceRunes(runes []rune, start, end int, insert string) string {
+	return string(runes[:start]) + insert + string(runes[end:])
+}
+
+func clampRuneIndex(pos, length int) int {
+	return max(0, min(pos, length))
+}
+
 func isPrintable(s string) bool {
 	for _, r := range s {
-		if r < ' ' || r > '~' {
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
 			return false
 		}
 	}
@@ -385,7 +562,7 @@ func isWordChar(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
 }
 
-func getLineStart(value string, pos int) int {
+func getLineStart(value []rune, pos int) int {
 	for i := pos - 1; i >= 0; i-- {
 		if value[i] == '\n' {
 			return i + 1
@@ -394,7 +571,7 @@ func getLineStart(value string, pos int) int {
 	return 0
 }
 
-func getLineEnd(value string, pos int) int {
+func getLineEnd(value []rune, pos int) int {
 	for i := pos; i < len(value); i++ {
 		if value[i] == '\n' {
 			return i
@@ -403,7 +580,7 @@ func getLineEnd(value string, pos int) int {
 	return len(value)
 }
 
-func moveCursorUp(value string, pos int) int {
+func moveCursorUp(value []rune, pos int) int {
 	lineStarts := []int{0}
 	for i := 0; i < len(value); i++ {
 		if value[i] == '\n' {
@@ -436,7 +613,7 @@ func moveCursorUp(value string, pos int) int {
 	return newPos
 }
 
-func moveCursorDown(value string, pos int) int {
+func moveCursorDown(value []rune, pos int) int {
 	lineStarts := []int{0}
 	for i := 0; i < len(value); i++ {
 		if value[i] == '\n' {
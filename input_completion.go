@@ -0,0 +1,179 @@
+package goli
+
+import (
+	"unicode/utf8"
+
+	"github.com/germtb/gox"
+)
+
+// CompleteFunc returns completion candidates for the text left of the
+// cursor. Each candidate replaces that text when accepted.
+type CompleteFunc func(partial string) []string
+
+// completionZIndex keeps the completion overlay above regular content.
+const completionZIndex = 1000
+
+// completionState holds an input's completion overlay state.
+type completionState struct {
+	complete      CompleteFunc
+	candidates    Accessor[[]string]
+	setCandidates Setter[[]string]
+	selected      Accessor[int]
+	setSelected   Setter[int]
+}
+
+func newCompletionState(complete CompleteFunc) *completionState {
+	if complete == nil {
+		return nil
+	}
+	candidates, setCandidates := CreateSignal[[]string](nil)
+	selected, setSelected := CreateSignal(0)
+	return &completionState{
+		complete:      complete,
+		candidates:    candidates,
+		setCandidates: setCandidates,
+		selected:      selected,
+		setSelected:   setSelected,
+	}
+}
+
+// CompletionVisible returns whether the completion overlay is shown.
+func (i *Input) CompletionVisible() bool {
+	return i.completion != nil && len(i.completion.candidates()) > 0
+}
+
+// Completions returns the candidates shown in the completion overlay.
+func (i *Input) Completions() []string {
+	if i.completion == nil {
+		return nil
+	}
+	return i.completion.candidates()
+}
+
+// CompletionIndex returns the highlighted candidate in the overlay.
+func (i *Input) CompletionIndex() int {
+	if i.completion == nil {
+		return 0
+	}
+	return i.completion.selected()
+}
+
+// DismissCompletions hides the completion overlay.
+func (i *Input) DismissCompletions() {
+	if i.CompletionVisible() {
+		i.completion.setCandidates(nil)
+	}
+}
+
+// capturesTab reports whether Tab or Shift+Tab should reach the input
+// instead of moving focus. Implements tabCapturer.
+func (i *Input) capturesTab(key string) bool {
+	if i.completion == nil {
+		return false
+	}
+	if key == Tab {
+		return true
+	}
+	return len(Untrack(i.completion.candidates)) > 0
+}
+
+// handleCompletionKey handles Tab completion and overlay navigation.
+// Returns true if the key was consumed.
+func (i *Input) handleCompletionKey(key string) bool {
+	c := i.completion
+	if c == nil {
+		return false
+	}
+
+	if candidates := Untrack(c.candidates); len(candidates) > 0 {
+		selected := Untrack(c.selected)
+		switch key {
+		case Escape:
+			c.setCandidates(nil)
+			return true
+		case Up, ShiftTab:
+			c.setSelected((selected - 1 + len(candidates)) % len(candidates))
+			return true
+		case Down:
+			c.setSelected((selected + 1) % len(candidates))
+			return true
+		case Enter, Tab:
+			i.acceptCompletion(candidates[selected])
+			return true
+		}
+		// Any other key closes the overlay and is handled normally
+		c.setCandidates(nil)
+		return false
+	}
+
+	if key != Tab {
+		return false
+	}
+
+	state := i.GetState()
+	candidates := c.complete(state.Value[:RuneOffsetToByteOffset(state.Value, state.CursorPos)])
+	switch len(candidates) {
+	case 0:
+		// Nothing to complete: let Tab move focus as usual
+		return false
+	case 1:
+		i.acceptCompletion(candidates[0])
+	default:
+		BatchVoid(func() {
+			c.setSelected(0)
+			c.setCandidates(candidates)
+		})
+	}
+	return true
+}
+
+// acceptCompletion replaces the text left of the cursor with candidate.
+func (i *Input) acceptCompletion(candidate string) {
+	state := i.GetState()
+	rest := state.Value[RuneOffsetToByteOffset(state.Value, state.CursorPos):]
+	BatchVoid(func() {
+		i.completion.setCandidates(nil)
+		i.setState(InputState{Value: candidate + rest, CursorPos: utf8.RuneCountInString(candidate)})
+	})
+}
+
+// completionOverlay returns the overlay listing candidates, highlighting the
+// selected one. layoutInput positions it below the input.
+func (i *Input) completionOverlay() gox.VNode {
+	candidates := i.completion.candidates()
+	selected := i.completion.selected()
+
+	options := make([]gox.VNode, len(candidates))
+	for idx, candidate := range candidates {
+		pointer := "  "
+		props := gox.Props{}
+		if idx == selected {
+			pointer = "> "
+			props["inverse"] = true
+		}
+		options[idx] = gox.Element("text", props, gox.Text(pointer+candidate))
+	}
+
+	return gox.Element("box", gox.Props{
+		"position":  "absolute",
+		"zIndex":    completionZIndex,
+		"direction": "column",
+	}, options...)
+}
+
+// layoutCompletionOverlay lays out the completion overlay directly below
+// the input box, or returns nil when it is hidden.
+func layoutCompletionOverlay(inputPrim any, box *LayoutBox) *LayoutBox {
+	inp, ok := inputPrim.(*Input)
+	if !ok || !inp.CompletionVisible() {
+		return nil
+	}
+	overlay := inp.completionOverlay()
+	w, h := measureNode(overlay)
+	return layoutNode(overlay, LayoutContext{
+		X:      box.X,
+		Y:      box.Y + box.Height,
+		Width:  w,
+		Height: h,
+	}).Box
+}
@@ -1,8 +1,10 @@
 package goli
 
 import (
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/germtb/gox"
 )
@@ -57,32 +59,26 @@ func TestInput_HorizontalScroll(t *testing.T) {
 			input.SetValue(tt.value)
 			input.SetCursorPos(tt.cursorPos)
 
-			var output strings.Builder
 			app := Render(func() gox.VNode {
 				return gox.Element("input", gox.Props{
 					"input": input,
 					"width": 10,
 				})
-			}, Options{Width: 20, Height: 5, Output: &output, DisableThrottle: true})
+			}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+			if err := app.WaitForRender(time.Second); err != nil {
+				t.Fatal(err)
+			}
 
-			buf := app.Renderer().CurrentBuffer()
-			debugStr := buf.ToDebugString()
+			screen := app.Headless().PlainOutput()
 
 			// Get the first 10 characters (our input width)
-			lines := strings.Split(debugStr, "\n")
-			if len(lines) == 0 {
-				t.Fatal("No output lines")
-			}
-			firstLine := lines[0]
+			firstLine := strings.SplitN(screen, "\n", 2)[0]
 			if len(firstLine) > 10 {
-				firstLine = firstLine[:10]
+				firstLine = strings.TrimRight(firstLine[:10], " ")
 			}
-
-			// Trim trailing spaces for comparison
-			trimmed := strings.TrimRight(firstLine, " ")
-			if trimmed != tt.visibleText {
-				t.Errorf("Expected visible text %q, got %q", tt.visibleText, trimmed)
-				t.Logf("Full buffer:\n%s", debugStr)
+			if firstLine != tt.visibleText {
+				t.Errorf("Expected visible text %q, got %q", tt.visibleText, firstLine)
+				t.Logf("Full buffer:\n%s", screen)
 			}
 
 			// Verify cursor is visible (within the width)
@@ -99,6 +95,42 @@ func TestInput_HorizontalScroll(t *testing.T) {
 	input.Dispose()
 }
 
+func TestInput_HorizontalScrollEmoji(t *testing.T) {
+	Reset()
+
+	input := NewInput(InputOptions{})
+	input.Focus()
+	defer input.Dispose()
+
+	// Thirteen runes, cursor at the end: the window scrolls by runes
+	input.SetValue("🎉🎉🎉🎉🎉🎉🎉🎉🎉🎉ABC")
+	input.SetCursorPos(13)
+
+	app := Render(func() gox.VNode {
+		return gox.Element("input", gox.Props{
+			"input": input,
+			"width": 10,
+		})
+	}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	firstLine := strings.SplitN(app.Headless().PlainOutput(), "\n", 2)[0]
+	if got := strings.TrimRight(firstLine, " "); got != "🎉🎉🎉🎉🎉🎉ABC" {
+		t.Errorf("expected visible text %q, got %q", "🎉🎉🎉🎉🎉🎉ABC", got)
+	}
+
+	// Backspacing through the emoji never leaves a partial rune behind
+	for i := 0; i < 5; i++ {
+		input.HandleKey(Backspace)
+	}
+	if got := input.Value(); got != "🎉🎉🎉🎉🎉🎉🎉🎉" || input.CursorPos() != 8 {
+		t.Errorf("expected 8 emoji with cursor 8, got %q at %d", got, input.CursorPos())
+	}
+}
+
 func TestInput_VerticalScroll(t *testing.T) {
 	// Clear runtime state
 	Reset()
@@ -112,32 +144,27 @@ func TestInput_VerticalScroll(t *testing.T) {
 	// Cursor at end of line 5 (past the visible 3-line window)
 	input.SetCursorPos(len("line1\nline2\nline3\nline4\nline5"))
 
-	var output strings.Builder
 	app := Render(func() gox.VNode {
 		return gox.Element("input", gox.Props{
 			"input":  input,
 			"width":  10,
 			"height": 3,
 		})
-	}, Options{Width: 20, Height: 10, Output: &output, DisableThrottle: true})
+	}, Options{Width: 20, Height: 10, Headless: true, DisableThrottle: true})
+	if err := app.WaitForRender(time.Second); err != nil {
+		t.Fatal(err)
+	}
 
-	buf := app.Renderer().CurrentBuffer()
-	debugStr := buf.ToDebugString()
+	h := app.Headless()
 
 	// With cursor on line 5 (index 4), and height 3, we should see lines 3, 4, 5
 	// scrollY = cursorLine - height + 1 = 4 - 3 + 1 = 2
 	// So visible lines start at index 2: line3, line4, line5
-	if !strings.Contains(debugStr, "line3") {
-		t.Error("Expected 'line3' to be visible")
-	}
-	if !strings.Contains(debugStr, "line4") {
-		t.Error("Expected 'line4' to be visible")
-	}
-	if !strings.Contains(debugStr, "line5") {
-		t.Error("Expected 'line5' to be visible")
-	}
+	h.AssertContains(t, "line3")
+	h.AssertContains(t, "line4")
+	h.AssertContains(t, "line5")
 	// line1 and line2 should NOT be visible (scrolled out)
-	lines := strings.Split(debugStr, "\n")
+	lines := strings.Split(h.PlainOutput(), "\n")
 	firstThree := strings.Join(lines[:3], "\n")
 	if strings.Contains(firstThree, "line1") {
 		t.Error("line1 should be scrolled out")
@@ -164,17 +191,18 @@ func TestInput_CursorVisible_AfterTyping(t *testing.T) {
 		input.SetValue(chars[:i+1])
 		input.SetCursorPos(i + 1)
 
-		var output strings.Builder
 		app := Render(func() gox.VNode {
 			return gox.Element("input", gox.Props{
 				"input": input,
 				"width": 5,
 			})
-		}, Options{Width: 20, Height: 5, Output: &output, DisableThrottle: true})
+		}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+		if err := app.WaitForRender(time.Second); err != nil {
+			t.Fatal(err)
+		}
 
-		// Just verify no panic and cursor is within visible range
-		buf := app.Renderer().CurrentBuffer()
-		_ = buf.ToDebugString() // Ensure render succeeded
+		// The character just typed is on screen
+		app.Headless().AssertContains(t, string(c))
 
 		// Calculate expected scroll
 		cursorPos := i + 1
@@ -195,3 +223,398 @@ func TestInput_CursorVisible_AfterTyping(t *testing.T) {
 
 	input.Dispose()
 }
+
+func TestInput_MultilineEnterInsertsNewline(t *testing.T) {
+	Reset()
+
+	single := NewInput(InputOptions{InitialValue: "ab"})
+	single.Focus()
+	if single.HandleKey(Enter) {
+		t.Error("single-line input should not consume Enter")
+	}
+	single.Dispose()
+
+	multi := NewInput(InputOptions{InitialValue: "ab", Multiline: true})
+	defer multi.Dispose()
+	multi.Focus()
+	multi.SetCursorPos(1)
+
+	if !multi.HandleKey(Enter) {
+		t.Fatal("multiline input should consume Enter")
+	}
+	if multi.Value() != "a\nb" {
+		t.Errorf("expected %q, got %q", "a\nb", multi.Value())
+	}
+	if line, col := multi.CursorLineCol(); line != 1 || col != 0 {
+		t.Errorf("expected cursor at (1, 0), got (%d, %d)", line, col)
+	}
+}
+
+func TestInput_MaxLines(t *testing.T) {
+	Reset()
+
+	inp := NewInput(InputOptions{Multiline: true, MaxLines: 2})
+	defer inp.Dispose()
+	inp.Focus()
+
+	inp.HandleKey("a")
+	inp.HandleKey(Enter)
+	inp.HandleKey("b")
+	if inp.HandleKey(Enter) {
+		t.Error("Enter should bubble up once MaxLines is reached")
+	}
+	if inp.Value() != "a\nb" || inp.LineCount() != 2 {
+		t.Errorf("expected 2 lines %q, got %q", "a\nb", inp.Value())
+	}
+
+	inp.SetValue("1\n2\n3\n4")
+	if inp.Value() != "1\n2" {
+		t.Errorf("expected SetValue to be capped at 2 lines, got %q", inp.Value())
+	}
+}
+
+func TestRuneOffsetToLineCol(t *testing.T) {
+	tests := []struct {
+		value     string
+		offset    int
+		line, col int
+	}{
+		{"hello", 3, 0, 3},
+		{"ab\ncd", 3, 1, 0},
+		{"ab\ncd", 5, 1, 2},
+		{"日本\n語", 4, 1, 1},
+		{"日本語", 2, 0, 2},
+		{"x", 10, 0, 1},
+	}
+	for _, tt := range tests {
+		line, col := RuneOffsetToLineCol(tt.value, tt.offset)
+		if line != tt.line || col != tt.col {
+			t.Errorf("RuneOffsetToLineCol(%q, %d) = (%d, %d), want (%d, %d)",
+				tt.value, tt.offset, line, col, tt.line, tt.col)
+		}
+	}
+}
+
+func TestInput_CursorRenderedOnMultibyteLine(t *testing.T) {
+	Reset()
+
+	inp := NewInput(InputOptions{InitialValue: "ab\néxyz", Multiline: true})
+	defer inp.Dispose()
+	inp.Focus()
+	// Cursor before 'x' on the second line: rune offset 3 + 1 for 'é'
+	inp.SetCursorPos(4)
+
+	app := Render(func() gox.VNode {
+		return gox.Element("input", gox.Props{"input": inp, "width": 10, "height": 2})
+	}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	h := app.Headless()
+	cell := h.GetCell(1, 1)
+	if cell.Char != 'x' {
+		t.Fatalf("expected 'x' at (1,1), got %q", cell.Char)
+	}
+	if cell.Style.Background != ColorWhite {
+		t.Errorf("expected cursor on 'x', got style %+v", cell.Style)
+	}
+	if h.GetCell(0, 0).Style.Background == ColorWhite || h.GetCell(2, 1).Style.Background == ColorWhite {
+		t.Error("cursor should be drawn only once")
+	}
+}
+
+func stubCompleter(candidates ...string) CompleteFunc {
+	return func(partial string) []string {
+		var matches []string
+		for _, c := range candidates {
+			if strings.HasPrefix(c, partial) {
+				matches = append(matches, c)
+			}
+		}
+		return matches
+	}
+}
+
+func TestInput_TabCompletesSingleResult(t *testing.T) {
+	Reset()
+	input := NewInput(InputOptions{
+		InitialValue: "che",
+		CompleteFunc: stubCompleter("checkout", "commit"),
+	})
+	input.Focus()
+
+	if !input.HandleKey(Tab) {
+		t.Fatal("expected Tab to be consumed")
+	}
+	if input.Value() != "checkout" || input.CursorPos() != 8 {
+		t.Errorf("expected checkout with cursor 8, got %q at %d", input.Value(), input.CursorPos())
+	}
+	if input.CompletionVisible() {
+		t.Error("expected no overlay for a single result")
+	}
+}
+
+func TestInput_TabShowsOverlayForMultipleResults(t *testing.T) {
+	Reset()
+	input := NewInput(InputOptions{
+		InitialValue: "c",
+		CompleteFunc: stubCompleter("checkout", "commit", "push"),
+	})
+	input.Focus()
+
+	input.HandleKey(Tab)
+	if !input.CompletionVisible() {
+		t.Fatal("expected overlay for multiple results")
+	}
+
+	node := gox.Element("box", gox.Props{"direction": "column", "width": 20, "height": 4},
+		gox.Element("input", gox.Props{"input": input, "width": 10, "height": 1}),
+		gox.Element("text", nil, gox.Text("below")),
+	)
+	result := sprintWith(node, PrintOptions{Width: 20, Height: 4, StripStyles: true, TrimTrailingSpaces: true})
+	lines := strings.Split(result, "\n")
+	if len(lines) < 3 || lines[1] != "> checkout" || lines[2] != "  commit" {
+		t.Errorf("expected overlay below input, got %q", result)
+	}
+
+	// Down then Enter accepts the second candidate
+	input.HandleKey(Down)
+	input.HandleKey(Enter)
+	if input.Value() != "commit" {
+		t.Errorf("expected commit, got %q", input.Value())
+	}
+	if input.CompletionVisible() {
+		t.Error("expected overlay to close after accepting")
+	}
+}
+
+func TestInput_EscapeDismissesCompletions(t *testing.T) {
+	Reset()
+	input := NewInput(InputOptions{CompleteFunc: stubCompleter("a1", "a2")})
+	input.Focus()
+
+	input.HandleKey(Tab)
+	input.HandleKey(Escape)
+	if input.CompletionVisible() || input.Value() != "" {
+		t.Errorf("expected overlay dismissed and value unchanged, got %q", input.Value())
+	}
+}
+
+func TestInput_TabCompletionThroughFocusManager(t *testing.T) {
+	Reset()
+	input := NewInput(InputOptions{InitialValue: "pu", CompleteFunc: stubCompleter("push", "pull")})
+	other := NewInput(InputOptions{})
+	input.Focus()
+
+	HandleKey(Tab)
+	if !input.Focused() || !input.CompletionVisible() {
+		t.Fatal("expected Tab to open completions instead of moving focus")
+	}
+	HandleKey(ShiftTab)
+	if input.CompletionIndex() != 1 {
+		t.Errorf("expected Shift+Tab to move selection up, got %d", input.CompletionIndex())
+	}
+	HandleKey(Tab)
+	if input.Value() != "pull" {
+		t.Errorf("expected pull, got %q", input.Value())
+	}
+
+	// No candidates: Tab moves focus as usual
+	input.SetValue("zz")
+	input.SetCursorPos(2)
+	HandleKey(Tab)
+	if !other.Focused() {
+		t.Error("expected Tab to move focus when there is nothing to complete")
+	}
+}
+
+func TestInput_EmojiCursorNavigation(t *testing.T) {
+	state := InputState{Value: "🎉foo", CursorPos: 0}
+
+	right := InputNavigationHandler(Right, state)
+	if right.CursorPos != 1 {
+		t.Errorf("expected cursor 1 after the emoji, got %d", right.CursorPos)
+	}
+	end := InputNavigationHandler(End, state)
+	if end.CursorPos != 4 {
+		t.Errorf("expected cursor 4 at end, got %d", end.CursorPos)
+	}
+	left := InputNavigationHandler(Left, *end)
+	if left.CursorPos != 3 {
+		t.Errorf("expected cursor 3, got %d", left.CursorPos)
+	}
+}
+
+func TestInput_EmojiInsertAndDelete(t *testing.T) {
+	state := InputState{Value: "🎉foo", CursorPos: 1}
+
+	inserted := InputPrintableHandler("🚀", state)
+	if inserted.Value != "🎉🚀foo" || inserted.CursorPos != 2 {
+		t.Errorf("expected 🎉🚀foo with cursor 2, got %q at %d", inserted.Value, inserted.CursorPos)
+	}
+
+	deleted := InputDeletionHandler(Backspace, *inserted)
+	if deleted.Value != "🎉foo" || deleted.CursorPos != 1 {
+		t.Errorf("expected 🎉foo with cursor 1, got %q at %d", deleted.Value, deleted.CursorPos)
+	}
+
+	forward := InputDeletionHandler(Delete, InputState{Value: "a🎉b", CursorPos: 1})
+	if forward.Value != "ab" || forward.CursorPos != 1 {
+		t.Errorf("expected ab with cursor 1, got %q at %d", forward.Value, forward.CursorPos)
+	}
+}
+
+func TestInput_SetCursorPosClampsToRuneCount(t *testing.T) {
+	Reset()
+	inp := NewInput(InputOptions{InitialValue: "🎉🎉"})
+	defer inp.Dispose()
+
+	if got := inp.CursorPos(); got != 2 {
+		t.Errorf("expected initial cursor 2, got %d", got)
+	}
+	inp.SetCursorPos(10)
+	if got := inp.CursorPos(); got != 2 {
+		t.Errorf("expected cursor clamped to 2, got %d", got)
+	}
+}
+
+func TestInputDeletionHandler_Backspace(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      InputState
+		wantValue  string
+		wantCursor int
+	}{
+		{"ascii", InputState{Value: "abc", CursorPos: 3}, "ab", 2},
+		{"start", InputState{Value: "🎉", CursorPos: 0}, "🎉", 0},
+		{"emoji at end", InputState{Value: "hi🎉🚀", CursorPos: 4}, "hi🎉", 3},
+		{"only emoji", InputState{Value: "🎉", CursorPos: 1}, "", 0},
+		{"cjk at end", InputState{Value: "日本語", CursorPos: 3}, "日本", 2},
+		{"accented middle", InputState{Value: "héllo", CursorPos: 2}, "hllo", 1},
+		{"cursor past end", InputState{Value: "a🎉", CursorPos: 9}, "a", 1},
+	}
+	for _, tt := range tests {
+		got := InputDeletionHandler(Backspace, tt.state)
+		if got == nil || got.Value != tt.wantValue || got.CursorPos != tt.wantCursor {
+			t.Errorf("%s: expected %q with cursor %d, got %+v", tt.name, tt.wantValue, tt.wantCursor, got)
+		}
+	}
+}
+
+func TestInputDeletionHandler_CtrlW(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      InputState
+		wantValue  string
+		wantCursor int
+	}{
+		{"ascii", InputState{Value: "foo bar", CursorPos: 7}, "foo ", 4},
+		{"cjk word", InputState{Value: "foo 日本語", CursorPos: 7}, "foo ", 4},
+		{"emoji separators", InputState{Value: "ab🎉🎉", CursorPos: 4}, "", 0},
+		{"accented word", InputState{Value: "dé jà", CursorPos: 5}, "dé ", 3},
+	}
+	for _, key := range []string{CtrlW, AltBackspace} {
+		for _, tt := range tests {
+			got := InputDeletionHandler(key, tt.state)
+			if got == nil || got.Value != tt.wantValue || got.CursorPos != tt.wantCursor {
+				t.Errorf("%s %q: expected %q with cursor %d, got %+v", tt.name, key, tt.wantValue, tt.wantCursor, got)
+			}
+		}
+	}
+}
+
+func TestInputDeletionHandler_CtrlK(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     InputState
+		wantValue string
+	}{
+		{"start", InputState{Value: "hello", CursorPos: 0}, ""},
+		{"middle", InputState{Value: "hello", CursorPos: 2}, "he"},
+		{"end", InputState{Value: "hello", CursorPos: 5}, "hello"},
+		{"multiline", InputState{Value: "one\ntwo\nsix", CursorPos: 5}, "one\nt\nsix"},
+		{"end of line keeps newline", InputState{Value: "one\ntwo", CursorPos: 3}, "one\ntwo"},
+	}
+	for _, tt := range tests {
+		got := InputDeletionHandler(CtrlK, tt.state)
+		if got == nil || got.Value != tt.wantValue || got.CursorPos != tt.state.CursorPos {
+			t.Errorf("%s: expected %q with cursor %d, got %+v", tt.name, tt.wantValue, tt.state.CursorPos, got)
+		}
+	}
+}
+
+func TestInputDeletionHandler_CtrlD(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     InputState
+		wantValue string
+	}{
+		{"start", InputState{Value: "hello", CursorPos: 0}, "ello"},
+		{"middle", InputState{Value: "hello", CursorPos: 2}, "helo"},
+		{"end", InputState{Value: "hello", CursorPos: 5}, "hello"},
+		{"multiline joins lines", InputState{Value: "one\ntwo", CursorPos: 3}, "onetwo"},
+	}
+	for _, tt := range tests {
+		got := InputDeletionHandler(CtrlD, tt.state)
+		if got == nil || got.Value != tt.wantValue || got.CursorPos != tt.state.CursorPos {
+			t.Errorf("%s: expected %q with cursor %d, got %+v", tt.name, tt.wantValue, tt.state.CursorPos, got)
+		}
+	}
+}
+
+func TestInputDeletionHandler_CtrlT(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      InputState
+		wantValue  string
+		wantCursor int
+	}{
+		{"start", InputState{Value: "abc", CursorPos: 0}, "abc", 0},
+		{"middle", InputState{Value: "abc", CursorPos: 1}, "bac", 2},
+		{"end", InputState{Value: "abc", CursorPos: 3}, "acb", 3},
+		{"multibyte", InputState{Value: "é🎉", CursorPos: 2}, "🎉é", 2},
+		{"end of first line", InputState{Value: "ab\ncd", CursorPos: 2}, "ba\ncd", 2},
+		{"start of second line", InputState{Value: "ab\ncd", CursorPos: 3}, "ab\ncd", 3},
+		{"single char line", InputState{Value: "a\nb", CursorPos: 1}, "a\nb", 1},
+	}
+	for _, tt := range tests {
+		got := InputDeletionHandler(CtrlT, tt.state)
+		if got == nil || got.Value != tt.wantValue || got.CursorPos != tt.wantCursor {
+			t.Errorf("%s: expected %q with cursor %d, got %+v", tt.name, tt.wantValue, tt.wantCursor, got)
+		}
+	}
+}
+
+func TestInput_ValueBindingThroughMapReadWrite(t *testing.T) {
+	Reset()
+	type settings struct {
+		Name  string
+		Width int
+	}
+	store := NewReadWrite(settings{Name: "main", Width: 8})
+	width := MapReadWrite(store,
+		func(s settings) string { return strconv.Itoa(s.Width) },
+		func(s settings, v string) settings {
+			if n, err := strconv.Atoi(v); err == nil {
+				s.Width = n
+			}
+			return s
+		},
+	)
+
+	inp := NewInput(InputOptions{Value: width})
+	defer inp.Dispose()
+	inp.Focus()
+	if inp.Value() != "8" || inp.CursorPos() != 1 {
+		t.Errorf("expected value 8 with the cursor at the end, got %q at %d", inp.Value(), inp.CursorPos())
+	}
+
+	inp.HandleKey("0")
+	if got := store.Value(); got.Width != 80 || got.Name != "main" {
+		t.Errorf("expected width 80 and the name kept, got %+v", got)
+	}
+
+	store.Update(settings{Name: "main", Width: 3})
+	if inp.Value() != "3" || inp.CursorPos() != 1 {
+		t.Errorf("expected the store change in the input with the cursor clamped, got %q at %d", inp.Value(), inp.CursorPos())
+	}
+}
@@ -41,72 +41,33 @@ func init() {
 
 // Box handlers
 
-func measureBox(node gox.VNode, ctx *LayoutContext) (int, int) {
-	padding := GetSpacing(node.Props, "padding")
+func measureBox(node gox.VNode, ctx LayoutContext) (int, int) {
+	props := PropReader(node.Props)
+	padding := props.Spacing("padding", Spacing{})
 	border := GetBorderStyle(node.Props["border"])
 	borderSize := 0
 	if border != BorderNone {
 		borderSize = 1
 	}
 
-	direction := GetDirection(node.Props)
-	gap := GetIntProp(node.Props, "gap", 0)
-
-	contentWidth := 0
-	contentHeight := 0
-
-	relativeChildren := FilterRelativeChildren(node)
-	childSizes := make([]struct{ w, h int }, len(relativeChildren))
-	for i, c := range relativeChildren {
-		w, h := MeasureNode(c)
-		childSizes[i] = struct{ w, h int }{w, h}
-	}
-
-	if direction == Row {
-		for i, size := range childSizes {
-			contentWidth += size.w
-			if i > 0 {
-				contentWidth += gap
-			}
-			if size.h > contentHeight {
-				contentHeight = size.h
-			}
-		}
-	} else {
-		for i, size := range childSizes {
-			contentHeight += size.h
-			if i > 0 {
-				contentHeight += gap
-			}
-			if size.w > contentWidth {
-				contentWidth = size.w
-			}
-		}
-	}
+	contentWidth, contentHeight := measureFlexContent(node, FilterRelativeChildren(node), MeasureNode, padding, borderSize)
 
 	totalWidth := contentWidth + padding.Left + padding.Right + borderSize*2
 	totalHeight := contentHeight + padding.Top + padding.Bottom + borderSize*2
 
-	explicitWidth := GetIntProp(node.Props, "width", -1)
-	explicitHeight := GetIntProp(node.Props, "height", -1)
-	minWidth := GetIntProp(node.Props, "minWidth", 0)
-	minHeight := GetIntProp(node.Props, "minHeight", 0)
+	explicitWidth, explicitHeight := explicitSize(node.Props)
 
 	finalWidth := totalWidth
 	if explicitWidth >= 0 {
 		finalWidth = explicitWidth
 	}
-	if finalWidth < minWidth {
-		finalWidth = minWidth
-	}
+	finalWidth = clampSize(node.Props, finalWidth, "minWidth", "maxWidth")
 
 	finalHeight := totalHeight
 	if explicitHeight >= 0 {
 		finalHeight = explicitHeight
 	}
-	if finalHeight < minHeight {
-		finalHeight = minHeight
-	}
+	finalHeight = clampSize(node.Props, finalHeight, "minHeight", "maxHeight")
 
 	return finalWidth, finalHeight
 }
@@ -114,8 +75,9 @@ func measureBox(node gox.VNode, ctx *LayoutContext) (int, int) {
 func layoutBox(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
 	var absoluteBoxes []*LayoutBox
 
-	padding := GetSpacing(node.Props, "padding")
-	margin := GetSpacing(node.Props, "margin")
+	props := PropReader(node.Props)
+	padding := props.Spacing("padding", Spacing{})
+	margin := props.Spacing("margin", Spacing{})
 	border := GetBorderStyle(node.Props["border"])
 	borderSize := 0
 	if border != BorderNone {
@@ -125,14 +87,14 @@ func layoutBox(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 	direction := GetDirection(node.Props)
 	justify := GetJustify(node.Props)
 	align := GetAlign(node.Props)
-	gap := GetIntProp(node.Props, "gap", 0)
+	gap := GetGap(node.Props)
 
 	// Calculate box dimensions
 	// Both width and height fill available space by default (block-like)
 	// Use explicit width/height props to constrain size
 	// Use grow property for flex children to distribute extra space
-	measuredW, measuredH := measureBox(node, nil)
-	boxWidth := GetIntProp(node.Props, "width", -1)
+	measuredW, measuredH := measureBox(node, LayoutContext{})
+	boxWidth, boxHeight := explicitSize(node.Props)
 	if boxWidth < 0 {
 		// Width fills available space
 		boxWidth = availWidth - margin.Left - margin.Right
@@ -140,7 +102,6 @@ func layoutBox(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 			boxWidth = measuredW
 		}
 	}
-	boxHeight := GetIntProp(node.Props, "height", -1)
 	if boxHeight < 0 {
 		// Height fills available space
 		boxHeight = availHeight - margin.Top - margin.Bottom
@@ -148,6 +109,8 @@ func layoutBox(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 			boxHeight = measuredH
 		}
 	}
+	boxWidth = capSize(node.Props, boxWidth, "maxWidth")
+	boxHeight = capSize(node.Props, boxHeight, "maxHeight")
 
 	// Box position (respecting margin)
 	boxX := ctx.X + margin.Left
@@ -171,20 +134,18 @@ func layoutBox(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 	}
 
 	// Layout flex children
-	childBoxes := LayoutFlexChildren(
-		childMeasurements,
-		LayoutContext{X: innerX, Y: innerY, Width: innerWidth, Height: innerHeight},
-		direction,
-		justify,
-		align,
-		gap,
-		&absoluteBoxes,
-	)
+	innerCtx := LayoutContext{X: innerX, Y: innerY, Width: innerWidth, Height: innerHeight}
+	var childBoxes []*LayoutBox
+	if GetFlexWrap(node.Props) {
+		childBoxes = LayoutFlexWrap(childMeasurements, innerCtx, direction, justify, align, gap, GetCrossGap(node.Props), &absoluteBoxes)
+	} else {
+		childBoxes = LayoutFlexChildren(childMeasurements, innerCtx, direction, justify, align, gap, &absoluteBoxes)
+	}
 
 	// Layout absolute children
 	for _, absChild := range absoluteChildren {
-		absX := GetIntProp(absChild.Props, "x", 0)
-		absY := GetIntProp(absChild.Props, "y", 0)
+		absX := PropReader(absChild.Props).Int("x", 0)
+		absY := PropReader(absChild.Props).Int("y", 0)
 		result := LayoutNode(absChild, LayoutContext{
 			X:      boxX + absX,
 			Y:      boxY + absY,
@@ -195,23 +156,31 @@ func layoutBox(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 		absoluteBoxes = append(absoluteBoxes, result.AbsoluteBoxes...)
 	}
 
+	// Record the full content size before clipping hides part of it
+	var scrollWidth, scrollHeight int
+	if overflow := GetOverflow(node.Props); overflow == OverflowHidden || overflow == OverflowScroll {
+		scrollWidth, scrollHeight = contentExtent(childBoxes, innerX, innerY)
+	}
+
 	// Merge absolute boxes into children for rendering
 	allChildren := make([]*LayoutBox, len(childBoxes)+len(absoluteBoxes))
 	copy(allChildren, childBoxes)
 	copy(allChildren[len(childBoxes):], absoluteBoxes)
 
 	return &LayoutBox{
-		X:           boxX,
-		Y:           boxY,
-		Width:       boxWidth,
-		Height:      boxHeight,
-		InnerX:      innerX,
-		InnerY:      innerY,
-		InnerWidth:  innerWidth,
-		InnerHeight: innerHeight,
-		Node:        node,
-		Children:    allChildren,
-		ZIndex:      GetIntProp(node.Props, "zIndex", 0),
+		X:            boxX,
+		Y:            boxY,
+		Width:        boxWidth,
+		Height:       boxHeight,
+		InnerX:       innerX,
+		InnerY:       innerY,
+		InnerWidth:   innerWidth,
+		InnerHeight:  innerHeight,
+		Node:         node,
+		Children:     allChildren,
+		ZIndex:       props.Int("zIndex", 0),
+		ScrollWidth:  scrollWidth,
+		ScrollHeight: scrollHeight,
 	}
 }
 
@@ -293,6 +262,13 @@ func renderBox(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 	for _, childBox := range box.Children {
 		RenderToBuffer(childBox, buf, childClip)
 	}
+
+	// Draw the cursor over the children
+	if cx, cy, cursorStyle, ok := boxCursor(box, childClip); ok {
+		cell := buf.Get(cx, cy)
+		cell.Style = cell.Style.Merge(cursorStyle)
+		buf.Set(cx, cy, cell)
+	}
 }
 
 func renderBoxLogical(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion) {
@@ -373,21 +349,64 @@ func renderBoxLogical(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion) {
 	for _, childBox := range box.Children {
 		RenderToLogicalBuffer(childBox, buf, childClip)
 	}
+
+	// Draw the cursor over the children
+	if cx, cy, cursorStyle, ok := boxCursor(box, childClip); ok {
+		cell := buf.Get(cx, cy)
+		if cell.Char == 0 {
+			cell.Char = ' '
+		}
+		cell.Style = cell.Style.Merge(cursorStyle)
+		buf.Set(cx, cy, cell)
+	}
+}
+
+// boxCursor returns the cell and style of a box's "cursor" prop, given as
+// {"x": col, "y": row, "style": cursorStyle} relative to the box's inner
+// area. The style defaults to inverse. ok is false when the prop is unset or
+// the cursor lies outside the inner area or clip.
+func boxCursor(box *LayoutBox, clip *ClipRegion) (x, y int, style Style, ok bool) {
+	var cursor PropReader
+	switch c := box.Node.Props["cursor"].(type) {
+	case gox.Props:
+		cursor = PropReader(c)
+	case map[string]any:
+		cursor = PropReader(c)
+	default:
+		return 0, 0, Style{}, false
+	}
+
+	col, row := cursor.Int("x", 0), cursor.Int("y", 0)
+	if col < 0 || row < 0 || col >= box.InnerWidth || row >= box.InnerHeight {
+		return 0, 0, Style{}, false
+	}
+	x, y = box.InnerX+col, box.InnerY+row
+	if !IsInClip(x, y, clip) {
+		return 0, 0, Style{}, false
+	}
+	return x, y, cursor.Style("style", Style{Inverse: true}), true
 }
 
 // Text handlers
 
-func measureText(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureText(node gox.VNode, ctx LayoutContext) (int, int) {
 	text := CollectTextContent(node)
 	lines := strings.Split(text, "\n")
+	// Wrapping text capped by maxWidth is as tall as it is wrapped at the cap
+	capWidth := GetIntProp(node.Props, "maxWidth", -1)
+	if capWidth >= 0 && GetBoolProp(node.Props, "wrap", false) {
+		lines = WrapText(text, capWidth)
+	}
+	lines = limitTextLines(lines, node.Props, capWidth)
 	maxWidth := 0
 	for _, line := range lines {
 		if RuneWidth(line) > maxWidth {
 			maxWidth = RuneWidth(line)
 		}
 	}
+	width := capSize(node.Props, maxWidth, "maxWidth")
 	margin := GetSpacing(node.Props, "margin")
-	return maxWidth + margin.Left + margin.Right, len(lines) + margin.Top + margin.Bottom
+	return width + margin.Left + margin.Right, len(lines) + margin.Top + margin.Bottom
 }
 
 func layoutText(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
@@ -395,8 +414,8 @@ func layoutText(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 	shouldWrap := GetBoolProp(node.Props, "wrap", false)
 	margin := GetSpacing(node.Props, "margin")
 
-	// Shrink available width by margins before wrapping
-	contentWidth := availWidth - margin.Left - margin.Right
+	// Shrink available width by margins (and to maxWidth) before wrapping
+	contentWidth := capSize(node.Props, availWidth-margin.Left-margin.Right, "maxWidth")
 	if contentWidth < 0 {
 		contentWidth = 0
 	}
@@ -407,6 +426,7 @@ func layoutText(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 	} else {
 		lines = strings.Split(text, "\n")
 	}
+	lines = limitTextLines(lines, node.Props, contentWidth)
 
 	maxWidth := 0
 	for _, line := range lines {
@@ -449,6 +469,33 @@ func layoutText(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext)
 	}
 }
 
+// limitTextLines keeps the first "maxLines" lines of a text element, and no
+// more than "maxHeight" (all when neither is set). If lines were dropped, the
+// "truncateChar" prop, when set, is appended to the last line kept, which is
+// shortened first if the marker would not fit within width (unbounded when
+// negative).
+func limitTextLines(lines []string, props gox.Props, width int) []string {
+	limit := -1
+	if maxLines := GetIntProp(props, "maxLines", 0); maxLines > 0 {
+		limit = maxLines
+	}
+	if maxHeight := GetIntProp(props, "maxHeight", -1); maxHeight >= 0 && (limit < 0 || maxHeight < limit) {
+		limit = maxHeight
+	}
+	if limit < 0 || len(lines) <= limit {
+		return lines
+	}
+	lines = lines[:limit:limit]
+	if truncateChar := GetStringProp(props, "truncateChar", ""); truncateChar != "" && limit > 0 {
+		last := lines[limit-1]
+		if width >= 0 && RuneWidth(last)+RuneWidth(truncateChar) > width {
+			last = runewidth.Truncate(last, max(width-RuneWidth(truncateChar), 0), "")
+		}
+		lines[limit-1] = last + truncateChar
+	}
+	return lines
+}
+
 func renderText(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 	node := box.Node
 	x, y := box.X, box.Y
@@ -499,7 +546,7 @@ func renderTextLogical(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion) {
 
 // Input handlers
 
-func measureInput(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureInput(node gox.VNode, ctx LayoutContext) (int, int) {
 	explicitWidth := GetIntProp(node.Props, "width", -1)
 	explicitHeight := GetIntProp(node.Props, "height", -1)
 
@@ -533,9 +580,9 @@ func measureInput(node gox.VNode, ctx *LayoutContext) (int, int) {
 }
 
 func layoutInput(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
-	w, h := measureInput(node, ctx)
+	w, h := measureInput(node, *ctx)
 
-	return &LayoutBox{
+	box := &LayoutBox{
 		X:           ctx.X,
 		Y:           ctx.Y,
 		Width:       w,
@@ -548,27 +595,39 @@ func layoutInput(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext
 		Children:    nil,
 		ZIndex:      GetIntProp(node.Props, "zIndex", 0),
 	}
+
+	// The completion overlay is absolute, so ComputeLayout hoists it to the
+	// root and renders it above the rest of the tree
+	if overlay := layoutCompletionOverlay(node.Props["input"], box); overlay != nil {
+		box.Children = []*LayoutBox{overlay}
+	}
+
+	return box
 }
 
 // Select handlers
 
-func measureSelect(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureSelect(node gox.VNode, ctx LayoutContext) (int, int) {
 	pointerWidth := GetIntProp(node.Props, "pointerWidth", 2)
 	optionChildren := FilterChildren(node, "option")
 
 	maxOptionWidth := 0
 	for _, opt := range optionChildren {
 		optText := CollectTextContent(opt)
-		if len(optText) > maxOptionWidth {
-			maxOptionWidth = len(optText)
+		if w := RuneWidth(optText); w > maxOptionWidth {
+			maxOptionWidth = w
 		}
 	}
 
-	return pointerWidth + maxOptionWidth, len(optionChildren)
+	height := len(optionChildren)
+	if maxHeight := GetIntProp(node.Props, "maxHeight", 0); maxHeight > 0 {
+		height = min(height, maxHeight)
+	}
+	return pointerWidth + maxOptionWidth, height
 }
 
 func layoutSelect(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
-	w, h := measureSelect(node, ctx)
+	w, h := measureSelect(node, *ctx)
 
 	// Auto-register options from children (doesn't trigger re-renders)
 	selectPrim := node.Props["select"]
@@ -587,6 +646,13 @@ func layoutSelect(node gox.VNode, availWidth, availHeight int, ctx *LayoutContex
 			}
 		}
 	}
+	// Selection changes report their value once the options are known
+	if sel, ok := selectPrim.(interface{ flushPendingChange() }); ok {
+		sel.flushPendingChange()
+	}
+	if sel, ok := selectPrim.(interface{ scrollIntoView(int) }); ok && h < len(optionChildren) {
+		sel.scrollIntoView(h)
+	}
 
 	return &LayoutBox{
 		X:           ctx.X,
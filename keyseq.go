@@ -0,0 +1,181 @@
+package goli
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// sequenceTimeout is how long a partial key sequence waits for its next key.
+var sequenceTimeout = 200 * time.Millisecond
+
+// keySequenceMatcher accumulates keys until they spell a registered
+// sequence. Keys that only start a sequence are held; when no sequence
+// completes, the held keys are passed to flush one at a time.
+type keySequenceMatcher struct {
+	mu        sync.Mutex
+	sequences map[string]func() bool
+	pending   []string
+	timer     *time.Timer
+	gen       uint64 // Bumped by stopTimer, so a timer that already fired is ignored
+	flush     func(key string)
+}
+
+// NewKeySequenceMatcher returns a key handler for Vim-style multi-key
+// bindings such as "gg" or "dd". Keys that start a registered sequence are
+// consumed and held; when the sequence completes its handler is called.
+// If no further key arrives within 200ms, or the next key breaks the
+// sequence, the held keys are replayed one at a time and each runs its own
+// single-key sequence, if one is registered. A sequence that is also the
+// start of a longer one (e.g., "g" and "gg") runs when the timeout expires.
+//
+// Timed-out sequences are handled on Run's input goroutine, like keys
+// (see FocusManager.postInput).
+//
+// Example:
+//
+//	Manager().SetGlobalKeyHandler(goli.NewKeySequenceMatcher(map[string]func() bool{
+//	    "gg": func() bool { scrollToTop(); return true },
+//	    "G":  func() bool { scrollToBottom(); return true },
+//	}))
+func NewKeySequenceMatcher(sequences map[string]func() bool) func(key string) bool {
+	k := newKeySequenceMatcher(nil)
+	for seq, handler := range sequences {
+		k.sequences[seq] = handler
+	}
+	k.flush = func(key string) {
+		k.mu.Lock()
+		handler := k.sequences[key]
+		k.mu.Unlock()
+		if handler != nil {
+			handler()
+		}
+	}
+	return k.handle
+}
+
+func newKeySequenceMatcher(flush func(key string)) *keySequenceMatcher {
+	return &keySequenceMatcher{
+		sequences: make(map[string]func() bool),
+		flush:     flush,
+	}
+}
+
+// handle feeds key to the matcher. Returns true if the key was consumed,
+// either held as part of a sequence or completing one whose handler
+// returned true.
+func (k *keySequenceMatcher) handle(key string) bool {
+	k.mu.Lock()
+	k.stopTimer()
+	keys := append(k.pending, key)
+	seq := strings.Join(keys, "")
+	handler, exact := k.sequences[seq]
+	partial := k.hasLongerSequence(seq)
+
+	switch {
+	case partial:
+		k.pending = keys
+		gen, m := k.gen, Manager()
+		k.timer = time.AfterFunc(sequenceTimeout, func() {
+			m.postInput(func() { k.timeout(gen) })
+		})
+		k.mu.Unlock()
+		return true
+	case exact:
+		k.pending = nil
+		k.mu.Unlock()
+		return handler()
+	}
+
+	// The key breaks the held sequence: replay the held keys, then match
+	// the key on its own
+	held := k.pending
+	k.pending = nil
+	k.mu.Unlock()
+	if len(held) == 0 {
+		return false
+	}
+	for _, h := range held {
+		k.flush(h)
+	}
+	return k.handle(key)
+}
+
+// timeout runs the held sequence if it is registered, or replays its keys.
+// gen is the generation the timer was started in; if a key or reset has
+// come since, the timer is stale and the keys now held are left alone.
+func (k *keySequenceMatcher) timeout(gen uint64) {
+	k.mu.Lock()
+	if gen != k.gen {
+		k.mu.Unlock()
+		return
+	}
+	k.stopTimer()
+	held := k.pending
+	k.pending = nil
+	handler := k.sequences[strings.Join(held, "")]
+	k.mu.Unlock()
+
+	if handler != nil {
+		handler()
+		return
+	}
+	for _, key := range held {
+		k.flush(key)
+	}
+}
+
+// hasLongerSequence reports whether seq starts a longer registered
+// sequence. Must be called with mu held.
+func (k *keySequenceMatcher) hasLongerSequence(seq string) bool {
+	for s := range k.sequences {
+		if len(s) > len(seq) && strings.HasPrefix(s, seq) {
+			return true
+		}
+	}
+	return false
+}
+
+// stopTimer cancels a pending timeout, including one that has fired but
+// not yet run. Must be called with mu held.
+func (k *keySequenceMatcher) stopTimer() {
+	k.gen++
+	if k.timer != nil {
+		k.timer.Stop()
+		k.timer = nil
+	}
+}
+
+// reset drops held keys without replaying them.
+func (k *keySequenceMatcher) reset() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.stopTimer()
+	k.pending = nil
+}
+
+// RegisterKeySequence binds a multi-key sequence such as "gg" to handler.
+// Sequences are matched after active boxes and before global key handlers,
+// with the same timeout rules as NewKeySequenceMatcher; held keys that
+// don't complete a sequence are passed to the global key handlers one at a
+// time. Returns a cleanup function that removes the binding.
+func (m *FocusManager) RegisterKeySequence(seq string, handler func() bool) func() {
+	m.mu.Lock()
+	if m.keySequences == nil {
+		m.keySequences = newKeySequenceMatcher(func(key string) {
+			m.handleGlobalKey(key)
+		})
+	}
+	k := m.keySequences
+	m.mu.Unlock()
+
+	k.mu.Lock()
+	k.sequences[seq] = handler
+	k.mu.Unlock()
+
+	return func() {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		delete(k.sequences, seq)
+	}
+}
@@ -0,0 +1,189 @@
+package goli
+
+import (
+	"testing"
+	"time"
+)
+
+// withSequenceTimeout shortens the key sequence timeout for a test.
+func withSequenceTimeout(t *testing.T, d time.Duration) {
+	t.Helper()
+	prev := sequenceTimeout
+	sequenceTimeout = d
+	t.Cleanup(func() { sequenceTimeout = prev })
+}
+
+func TestKeySequenceMatcher_MatchesWithinTimeout(t *testing.T) {
+	withSequenceTimeout(t, time.Second)
+	fired := make(chan string, 4)
+	handle := NewKeySequenceMatcher(map[string]func() bool{
+		"gg": func() bool { fired <- "gg"; return true },
+		"g":  func() bool { fired <- "g"; return true },
+	})
+
+	if !handle("g") {
+		t.Error("expected g to be held")
+	}
+	if !handle("g") {
+		t.Error("expected gg to be consumed")
+	}
+	if got := <-fired; got != "gg" {
+		t.Errorf("expected gg, got %s", got)
+	}
+	select {
+	case got := <-fired:
+		t.Errorf("expected no other handler, got %s", got)
+	default:
+	}
+}
+
+func TestKeySequenceMatcher_TimeoutFlushesHeldKey(t *testing.T) {
+	withSequenceTimeout(t, 10*time.Millisecond)
+	fired := make(chan string, 4)
+	handle := NewKeySequenceMatcher(map[string]func() bool{
+		"gg": func() bool { fired <- "gg"; return true },
+		"g":  func() bool { fired <- "g"; return true },
+	})
+
+	handle("g")
+	select {
+	case got := <-fired:
+		if got != "g" {
+			t.Errorf("expected g after the timeout, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the held g to be flushed")
+	}
+}
+
+func TestKeySequenceMatcher_BrokenSequence(t *testing.T) {
+	withSequenceTimeout(t, time.Second)
+	var fired []string
+	handle := NewKeySequenceMatcher(map[string]func() bool{
+		"dd": func() bool { fired = append(fired, "dd"); return true },
+		"x":  func() bool { fired = append(fired, "x"); return true },
+	})
+
+	if handle("q") {
+		t.Error("expected an unbound key not to be consumed")
+	}
+	handle("d")
+	if !handle("x") {
+		t.Error("expected x to run its own sequence")
+	}
+	handle("d")
+	handle("d")
+	if len(fired) != 2 || fired[0] != "x" || fired[1] != "dd" {
+		t.Errorf("expected [x dd], got %v", fired)
+	}
+}
+
+func TestFocusManager_RegisterKeySequence(t *testing.T) {
+	setupTest(t)
+	withSequenceTimeout(t, time.Second)
+
+	matched := 0
+	cleanup := Manager().RegisterKeySequence("gg", func() bool {
+		matched++
+		return true
+	})
+	var global []string
+	Manager().SetGlobalKeyHandler(func(key string) bool {
+		global = append(global, key)
+		return true
+	})
+
+	HandleKey("g")
+	HandleKey("g")
+	if matched != 1 || len(global) != 0 {
+		t.Errorf("expected gg to match once, got %d matches and global keys %v", matched, global)
+	}
+
+	// A key that breaks the sequence replays the held key first
+	HandleKey("g")
+	HandleKey("j")
+	if len(global) != 2 || global[0] != "g" || global[1] != "j" {
+		t.Errorf("expected global keys [g j], got %v", global)
+	}
+
+	cleanup()
+	global = nil
+	HandleKey("g")
+	if len(global) != 1 || global[0] != "g" {
+		t.Errorf("expected g to reach the global handler after cleanup, got %v", global)
+	}
+}
+
+func TestFocusManager_KeySequenceTimeoutFlushesToGlobal(t *testing.T) {
+	setupTest(t)
+	withSequenceTimeout(t, 10*time.Millisecond)
+
+	Manager().RegisterKeySequence("gg", func() bool { return true })
+	keys := make(chan string, 1)
+	Manager().SetGlobalKeyHandler(func(key string) bool {
+		keys <- key
+		return true
+	})
+
+	HandleKey("g")
+	select {
+	case key := <-keys:
+		if key != "g" {
+			t.Errorf("expected g, got %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the held g to reach the global handler")
+	}
+}
+
+func TestKeySequenceMatcher_StaleTimeoutIgnored(t *testing.T) {
+	withSequenceTimeout(t, time.Hour)
+	var flushed []string
+	k := newKeySequenceMatcher(func(key string) { flushed = append(flushed, key) })
+	k.sequences["gg"] = func() bool { return true }
+
+	k.handle("g")
+	gen := k.gen
+	// A fired timer whose run was delayed past the next keys
+	k.handle("x")
+	k.handle("g")
+	k.timeout(gen)
+	if len(flushed) != 1 || flushed[0] != "g" {
+		t.Errorf("expected only the g broken by x to be flushed, got %v", flushed)
+	}
+	if len(k.pending) != 1 {
+		t.Errorf("expected the new g to stay held, got %v", k.pending)
+	}
+	k.reset()
+}
+
+func TestFocusManager_KeySequenceTimeoutRunsOnInputGoroutine(t *testing.T) {
+	setupTest(t)
+	withSequenceTimeout(t, 10*time.Millisecond)
+
+	posted := make(chan func(), 1)
+	Manager().setInputRunner(func(fn func()) { posted <- fn })
+	t.Cleanup(func() { Manager().setInputRunner(nil) })
+
+	Manager().RegisterKeySequence("gg", func() bool { return true })
+	var global []string
+	Manager().SetGlobalKeyHandler(func(key string) bool {
+		global = append(global, key)
+		return true
+	})
+
+	HandleKey("g")
+	var fn func()
+	select {
+	case fn = <-posted:
+	case <-time.After(time.Second):
+		t.Fatal("expected the timeout to be posted to the input goroutine")
+	}
+	if len(global) != 0 {
+		t.Fatalf("expected nothing flushed before the input goroutine runs, got %v", global)
+	}
+	fn()
+	if len(global) != 1 || global[0] != "g" {
+		t.Errorf("expected the held g flushed, got %v", global)
+	}
+}
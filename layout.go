@@ -2,6 +2,7 @@
 package goli
 
 import (
+	"math"
 	"strings"
 	"unicode/utf8"
 
@@ -150,24 +151,7 @@ func NormalizeSpacing(value any) Spacing {
 // For example, GetSpacing(props, "padding") reads "padding" and also
 // "paddingTop", "paddingRight", "paddingBottom", "paddingLeft" as overrides.
 func GetSpacing(props map[string]any, baseProp string) Spacing {
-	// Start with the base prop
-	spacing := NormalizeSpacing(props[baseProp])
-
-	// Override with directional props if present
-	if v, ok := props[baseProp+"Top"]; ok {
-		spacing.Top = getIntFromAny(v)
-	}
-	if v, ok := props[baseProp+"Right"]; ok {
-		spacing.Right = getIntFromAny(v)
-	}
-	if v, ok := props[baseProp+"Bottom"]; ok {
-		spacing.Bottom = getIntFromAny(v)
-	}
-	if v, ok := props[baseProp+"Left"]; ok {
-		spacing.Left = getIntFromAny(v)
-	}
-
-	return spacing
+	return PropReader(props).Spacing(baseProp, Spacing{})
 }
 
 // getIntFromAny converts various numeric types to int.
@@ -194,6 +178,43 @@ func getInt(m map[string]any, key string) int {
 	return 0
 }
 
+// explicitSize returns a box's "width" and "height" props, -1 when unset.
+// With an "aspectRatio" prop (width / height) and exactly one of them set,
+// the other is derived from it; when both are set the ratio is ignored, as
+// is a ratio that is not a positive finite number.
+func explicitSize(props gox.Props) (width, height int) {
+	width = GetIntProp(props, "width", -1)
+	height = GetIntProp(props, "height", -1)
+	ratio := GetFloatProp(props, "aspectRatio", 0)
+	switch {
+	case ratio <= 0 || math.IsNaN(ratio) || math.IsInf(ratio, 0):
+	case width >= 0 && height < 0:
+		height = int(float64(width) / ratio)
+	case height >= 0 && width < 0:
+		width = int(float64(height) * ratio)
+	}
+	return width, height
+}
+
+// clampSize limits size to the props named minKey and maxKey. A missing or
+// negative maximum means no limit; the minimum wins if the two conflict.
+func clampSize(props gox.Props, size int, minKey, maxKey string) int {
+	if maxSize := GetIntProp(props, maxKey, -1); maxSize >= 0 {
+		size = min(size, maxSize)
+	}
+	return max(size, GetIntProp(props, minKey, 0))
+}
+
+// capSize limits a laid-out size to the maxKey prop, if set. Unlike
+// clampSize it leaves smaller sizes alone: a box given less room than its
+// minimum still fits its parent.
+func capSize(props gox.Props, size int, maxKey string) int {
+	if maxSize := GetIntProp(props, maxKey, -1); maxSize >= 0 {
+		return min(size, maxSize)
+	}
+	return size
+}
+
 // GetBorderStyle normalizes border prop to BorderStyle.
 func GetBorderStyle(border any) BorderStyle {
 	if border == nil {
@@ -229,14 +250,7 @@ func GetStyle(props map[string]any) Style {
 	var style Style
 
 	// Start with style map if present
-	if styleVal, ok := props["style"]; ok && styleVal != nil {
-		switch s := styleVal.(type) {
-		case Style:
-			style = s
-		case map[string]any:
-			style = mapToStyle(s)
-		}
-	}
+	style = PropReader(props).Style("style", style)
 
 	// Override with direct attribute props
 	if v, ok := props["color"]; ok {
@@ -360,6 +374,22 @@ type LayoutBox struct {
 
 	// For z-index sorting
 	ZIndex int
+
+	// Full size of the content of an overflow hidden/scroll box, including
+	// children clipped by the viewport. Zero for other boxes.
+	ScrollWidth  int
+	ScrollHeight int
+}
+
+// contentExtent returns the size of the area covered by children, measured
+// from the content origin (originX, originY) and including their margins.
+func contentExtent(children []*LayoutBox, originX, originY int) (width, height int) {
+	for _, child := range children {
+		margin := GetSpacing(child.Node.Props, "margin")
+		width = max(width, child.X+child.Width+margin.Right-originX)
+		height = max(height, child.Y+child.Height+margin.Bottom-originY)
+	}
+	return width, height
 }
 
 // LayoutContext provides the available space for layout.
@@ -407,37 +437,57 @@ func RuneWidth(s string) int {
 }
 
 // ComputeLayout computes layout for a VNode tree.
-func ComputeLayout(node gox.VNode, ctx LayoutContext) *LayoutBox {
+// The result's Box is the laid-out tree; AbsoluteBoxes holds every
+// absolute-positioned box, sorted by ascending z-index so later boxes draw
+// on top. Use Tree to get a single box for rendering.
+func ComputeLayout(node gox.VNode, ctx LayoutContext) LayoutResult {
+	// Callbacks queued by handlers, such as Select.OnChange, run on return
+	rt := Global
+	rt.beginLayout()
+	defer rt.endLayout()
+
 	// First expand any functional components
-	expanded := Expand(node)
+	expanded, portals := extractPortals(Expand(node))
 
 	// Layout the tree
 	result := layoutNode(expanded, ctx)
 
-	// Merge absolute boxes back, sorted by z-index
+	// Gather absolute boxes, sorted by z-index
 	allAbsolute := collectAbsoluteBoxes(result.Box)
 	allAbsolute = append(allAbsolute, result.AbsoluteBoxes...)
-
-	// Sort by z-index
+	if len(portals) > 0 {
+		roots := append([]*LayoutBox{result.Box}, allAbsolute...)
+		allAbsolute = append(allAbsolute, resolvePortals(roots, portals)...)
+	}
 	sortByZIndex(allAbsolute)
 
-	// Return root with absolute boxes as additional children for rendering
-	newChildren := make([]*LayoutBox, len(result.Box.Children)+len(allAbsolute))
-	copy(newChildren, result.Box.Children)
-	copy(newChildren[len(result.Box.Children):], allAbsolute)
+	return LayoutResult{Box: result.Box, AbsoluteBoxes: allAbsolute}
+}
+
+// Tree returns the root box with the absolute boxes appended as trailing
+// children, so a single RenderToBuffer pass draws them over the rest of the
+// tree in z-index order.
+func (r LayoutResult) Tree() *LayoutBox {
+	if len(r.AbsoluteBoxes) == 0 {
+		return r.Box
+	}
+
+	newChildren := make([]*LayoutBox, len(r.Box.Children)+len(r.AbsoluteBoxes))
+	copy(newChildren, r.Box.Children)
+	copy(newChildren[len(r.Box.Children):], r.AbsoluteBoxes)
 
 	return &LayoutBox{
-		X:           result.Box.X,
-		Y:           result.Box.Y,
-		Width:       result.Box.Width,
-		Height:      result.Box.Height,
-		InnerX:      result.Box.InnerX,
-		InnerY:      result.Box.InnerY,
-		InnerWidth:  result.Box.InnerWidth,
-		InnerHeight: result.Box.InnerHeight,
-		Node:        result.Box.Node,
+		X:           r.Box.X,
+		Y:           r.Box.Y,
+		Width:       r.Box.Width,
+		Height:      r.Box.Height,
+		InnerX:      r.Box.InnerX,
+		InnerY:      r.Box.InnerY,
+		InnerWidth:  r.Box.InnerWidth,
+		InnerHeight: r.Box.InnerHeight,
+		Node:        r.Box.Node,
 		Children:    newChildren,
-		ZIndex:      result.Box.ZIndex,
+		ZIndex:      r.Box.ZIndex,
 	}
 }
 
@@ -506,7 +556,7 @@ func measureNode(node gox.VNode) (width, height int) {
 		panic("goli: unknown element type: " + typeStr)
 	}
 	if handler.Measure != nil {
-		return handler.Measure(node, nil)
+		return handler.Measure(node, LayoutContext{})
 	}
 
 	// Handler exists but no Measure - measure children as container
@@ -519,9 +569,6 @@ func measureNode(node gox.VNode) (width, height int) {
 
 	overflow := GetOverflow(node.Props)
 
-	direction := getDirection(node.Props)
-	gap := GetIntProp(node.Props, "gap", 0)
-
 	contentWidth := 0
 	contentHeight := 0
 
@@ -531,59 +578,25 @@ func measureNode(node gox.VNode) (width, height int) {
 	skipContentSize := overflow == OverflowHidden || overflow == OverflowScroll
 
 	if !skipContentSize {
-		relativeChildren := filterRelativeChildren(node)
-		childSizes := make([]struct{ w, h int }, len(relativeChildren))
-		for i, c := range relativeChildren {
-			w, h := measureNode(c)
-			childSizes[i] = struct{ w, h int }{w, h}
-		}
-
-		if direction == Row {
-			for i, size := range childSizes {
-				contentWidth += size.w
-				if i > 0 {
-					contentWidth += gap
-				}
-				if size.h > contentHeight {
-					contentHeight = size.h
-				}
-			}
-		} else {
-			for i, size := range childSizes {
-				contentHeight += size.h
-				if i > 0 {
-					contentHeight += gap
-				}
-				if size.w > contentWidth {
-					contentWidth = size.w
-				}
-			}
-		}
+		contentWidth, contentHeight = measureFlexContent(node, filterRelativeChildren(node), measureNode, padding, borderSize)
 	}
 
 	totalWidth := contentWidth + padding.Left + padding.Right + borderSize*2
 	totalHeight := contentHeight + padding.Top + padding.Bottom + borderSize*2
 
-	explicitWidth := GetIntProp(node.Props, "width", -1)
-	explicitHeight := GetIntProp(node.Props, "height", -1)
-	minWidth := GetIntProp(node.Props, "minWidth", 0)
-	minHeight := GetIntProp(node.Props, "minHeight", 0)
+	explicitWidth, explicitHeight := explicitSize(node.Props)
 
 	finalWidth := totalWidth
 	if explicitWidth >= 0 {
 		finalWidth = explicitWidth
 	}
-	if finalWidth < minWidth {
-		finalWidth = minWidth
-	}
+	finalWidth = clampSize(node.Props, finalWidth, "minWidth", "maxWidth")
 
 	finalHeight := totalHeight
 	if explicitHeight >= 0 {
 		finalHeight = explicitHeight
 	}
-	if finalHeight < minHeight {
-		finalHeight = minHeight
-	}
+	finalHeight = clampSize(node.Props, finalHeight, "minHeight", "maxHeight")
 
 	return finalWidth, finalHeight
 }
@@ -664,14 +677,14 @@ func layoutNode(node gox.VNode, ctx LayoutContext) LayoutResult {
 	direction := getDirection(node.Props)
 	justify := getJustify(node.Props)
 	align := getAlign(node.Props)
-	gap := GetIntProp(node.Props, "gap", 0)
+	gap := getGap(node.Props, direction)
 
 	// Calculate box dimensions
 	// Both width and height fill available space by default (block-like)
 	// Use explicit width/height props to constrain size
 	// Use grow property for flex children to distribute extra space
 	measuredW, measuredH := measureNode(node)
-	boxWidth := GetIntProp(node.Props, "width", -1)
+	boxWidth, boxHeight := explicitSize(node.Props)
 	if boxWidth < 0 {
 		// Width fills available space
 		boxWidth = ctx.Width - margin.Left - margin.Right
@@ -679,7 +692,6 @@ func layoutNode(node gox.VNode, ctx LayoutContext) LayoutResult {
 			boxWidth = measuredW
 		}
 	}
-	boxHeight := GetIntProp(node.Props, "height", -1)
 	if boxHeight < 0 {
 		// Height fills available space
 		boxHeight = ctx.Height - margin.Top - margin.Bottom
@@ -687,6 +699,8 @@ func layoutNode(node gox.VNode, ctx LayoutContext) LayoutResult {
 			boxHeight = measuredH
 		}
 	}
+	boxWidth = capSize(node.Props, boxWidth, "maxWidth")
+	boxHeight = capSize(node.Props, boxHeight, "maxHeight")
 
 	// Box position (respecting margin)
 	boxX := ctx.X + margin.Left
@@ -710,15 +724,13 @@ func layoutNode(node gox.VNode, ctx LayoutContext) LayoutResult {
 	}
 
 	// Layout flex children
-	childBoxes := layoutFlexChildren(
-		childMeasurements,
-		LayoutContext{X: innerX, Y: innerY, Width: innerWidth, Height: innerHeight},
-		direction,
-		justify,
-		align,
-		gap,
-		&absoluteBoxes,
-	)
+	innerCtx := LayoutContext{X: innerX, Y: innerY, Width: innerWidth, Height: innerHeight}
+	var childBoxes []*LayoutBox
+	if GetFlexWrap(node.Props) {
+		childBoxes = layoutFlexWrap(childMeasurements, innerCtx, direction, justify, align, gap, GetCrossGap(node.Props), &absoluteBoxes)
+	} else {
+		childBoxes = layoutFlexChildren(childMeasurements, innerCtx, direction, justify, align, gap, &absoluteBoxes)
+	}
 
 	// Layout absolute children
 	for _, absChild := range absoluteChildren {
@@ -844,6 +856,15 @@ func layoutFlexChildren(
 
 	isRow := direction == Row
 
+	// Main-axis size limits (minWidth/maxWidth for row, minHeight/maxHeight
+	// for column); -1 means no maximum
+	minKey, maxKey := "minWidth", "maxWidth"
+	if !isRow {
+		minKey, maxKey = "minHeight", "maxHeight"
+	}
+	mainSizes := make([]int, len(children))
+	maxSizes := make([]int, len(children))
+
 	// Calculate total size along main axis
 	totalMainSize := 0
 	for i, child := range children {
@@ -857,6 +878,9 @@ func layoutFlexChildren(
 			mainMargin = margin.Top + margin.Bottom
 			mainSize = child.height
 		}
+		maxSizes[i] = GetIntProp(child.node.Props, maxKey, -1)
+		mainSize = clampSize(child.node.Props, mainSize, minKey, maxKey)
+		mainSizes[i] = mainSize
 		totalMainSize += mainMargin + mainSize
 		if i > 0 {
 			totalMainSize += gap
@@ -898,28 +922,7 @@ func layoutFlexChildren(
 
 	// Pre-calculate grow shares with remainder distribution
 	// This ensures all extra space is used (no rounding loss)
-	growShares := make([]int, len(children))
-	if totalGrow > 0 && extraSpace > 0 {
-		remainingSpace := extraSpace
-		for i := range children {
-			if growValues[i] > 0 {
-				// Calculate this child's share
-				share := (extraSpace * growValues[i]) / totalGrow
-				growShares[i] = share
-				remainingSpace -= share
-			}
-		}
-		// Distribute remainder to growing children (1 extra pixel each until exhausted)
-		for i := range children {
-			if remainingSpace <= 0 {
-				break
-			}
-			if growValues[i] > 0 {
-				growShares[i]++
-				remainingSpace--
-			}
-		}
-	}
+	growShares := distributeGrow(extraSpace, growValues, mainSizes, maxSizes)
 
 	// Calculate starting position and spacing based on justify
 	mainPos := 0
@@ -952,13 +955,12 @@ func layoutFlexChildren(
 		var childMainSize, childCrossSize int
 		var mainMarginBefore, mainMarginAfter int
 
+		childMainSize = mainSizes[i]
 		if isRow {
-			childMainSize = child.width
 			childCrossSize = child.height
 			mainMarginBefore = margin.Left
 			mainMarginAfter = margin.Right
 		} else {
-			childMainSize = child.height
 			childCrossSize = child.width
 			mainMarginBefore = margin.Top
 			mainMarginAfter = margin.Bottom
@@ -1029,6 +1031,231 @@ func layoutFlexChildren(
 	return boxes
 }
 
+// LayoutFlexWrap lays out children like LayoutFlexChildren, but starts a
+// new line (a row for Row, a column for Column) whenever the next child
+// would overflow the main axis. Each line is as thick as its largest child
+// and is justified and aligned on its own; lines are crossGap apart.
+func LayoutFlexWrap(
+	children []ChildMeasurement,
+	ctx LayoutContext,
+	direction Direction,
+	justify Justify,
+	align Align,
+	gap, crossGap int,
+	absoluteBoxes *[]*LayoutBox,
+) []*LayoutBox {
+	internal := make([]childMeasurement, len(children))
+	for i, c := range children {
+		internal[i] = childMeasurement{node: c.Node, width: c.Width, height: c.Height}
+	}
+	return layoutFlexWrap(internal, ctx, direction, justify, align, gap, crossGap, absoluteBoxes)
+}
+
+func layoutFlexWrap(
+	children []childMeasurement,
+	ctx LayoutContext,
+	direction Direction,
+	justify Justify,
+	align Align,
+	gap, crossGap int,
+	absoluteBoxes *[]*LayoutBox,
+) []*LayoutBox {
+	isRow := direction == Row
+	availableMain := ctx.Width
+	minKey, maxKey := "minWidth", "maxWidth"
+	if !isRow {
+		availableMain = ctx.Height
+		minKey, maxKey = "minHeight", "maxHeight"
+	}
+
+	// Outer sizes, including margins, as layoutFlexChildren will place them
+	mainSizes := make([]int, len(children))
+	crossSizes := make([]int, len(children))
+	for i, child := range children {
+		margin := GetSpacing(child.node.Props, "margin")
+		mainSize, crossSize := child.width, child.height
+		mainMargin, crossMargin := margin.Left+margin.Right, margin.Top+margin.Bottom
+		if !isRow {
+			mainSize, crossSize = child.height, child.width
+			mainMargin, crossMargin = crossMargin, mainMargin
+		}
+		mainSizes[i] = clampSize(child.node.Props, mainSize, minKey, maxKey) + mainMargin
+		crossSizes[i] = crossSize + crossMargin
+	}
+
+	var boxes []*LayoutBox
+	crossPos := 0
+	starts := flexLines(mainSizes, gap, availableMain)
+	for l, start := range starts {
+		end := len(children)
+		if l+1 < len(starts) {
+			end = starts[l+1]
+		}
+		lineCross := 0
+		for _, size := range crossSizes[start:end] {
+			lineCross = max(lineCross, size)
+		}
+
+		lineCtx := LayoutContext{X: ctx.X, Y: ctx.Y + crossPos, Width: ctx.Width, Height: lineCross}
+		if !isRow {
+			lineCtx = LayoutContext{X: ctx.X + crossPos, Y: ctx.Y, Width: lineCross, Height: ctx.Height}
+		}
+		boxes = append(boxes, layoutFlexChildren(children[start:end], lineCtx, direction, justify, align, gap, absoluteBoxes)...)
+		crossPos += lineCross + crossGap
+	}
+	return boxes
+}
+
+// flexLines splits items with the given main-axis sizes into wrapped
+// lines, returning the index each line starts at. A line holds at least
+// one item, even one larger than available.
+func flexLines(sizes []int, gap, available int) []int {
+	if len(sizes) == 0 {
+		return nil
+	}
+	starts := []int{0}
+	used := sizes[0]
+	for i := 1; i < len(sizes); i++ {
+		if used+gap+sizes[i] > available {
+			starts = append(starts, i)
+			used = sizes[i]
+			continue
+		}
+		used += gap + sizes[i]
+	}
+	return starts
+}
+
+// measureFlexContent returns the content size of a box's relative
+// children, measured with measure. With flexWrap, children wrap within the
+// box's explicit or maximum width (Row) or height (Column); without one
+// the box's lines can't be known before layout, so they are measured as
+// one line.
+func measureFlexContent(node gox.VNode, children []gox.VNode, measure func(gox.VNode) (int, int), padding Spacing, borderSize int) (width, height int) {
+	direction := getDirection(node.Props)
+	mainSizes := make([]int, len(children))
+	crossSizes := make([]int, len(children))
+	for i, c := range children {
+		w, h := measure(c)
+		mainSizes[i], crossSizes[i] = w, h
+		if direction != Row {
+			mainSizes[i], crossSizes[i] = h, w
+		}
+	}
+
+	wrapMain := -1
+	if GetFlexWrap(node.Props) {
+		width, height := explicitSize(node.Props)
+		size, maxKey, inset := width, "maxWidth", padding.Left+padding.Right+borderSize*2
+		if direction != Row {
+			size, maxKey, inset = height, "maxHeight", padding.Top+padding.Bottom+borderSize*2
+		}
+		if size < 0 {
+			size = GetIntProp(node.Props, maxKey, -1)
+		} else {
+			size = capSize(node.Props, size, maxKey)
+		}
+		if size >= 0 {
+			wrapMain = max(0, size-inset)
+		}
+	}
+
+	mainSize, crossSize := flexContentSize(mainSizes, crossSizes, getGap(node.Props, direction), GetCrossGap(node.Props), wrapMain)
+	if direction != Row {
+		return crossSize, mainSize
+	}
+	return mainSize, crossSize
+}
+
+// flexContentSize returns the main- and cross-axis size of children laid
+// out in a line, or in wrapped lines when wrapMain >= 0.
+func flexContentSize(mainSizes, crossSizes []int, gap, crossGap, wrapMain int) (mainSize, crossSize int) {
+	if len(mainSizes) == 0 {
+		return 0, 0
+	}
+	starts := []int{0}
+	if wrapMain >= 0 {
+		starts = flexLines(mainSizes, gap, wrapMain)
+	}
+	for l, start := range starts {
+		end := len(mainSizes)
+		if l+1 < len(starts) {
+			end = starts[l+1]
+		}
+		lineMain, lineCross := gap*(end-start-1), 0
+		for i := start; i < end; i++ {
+			lineMain += mainSizes[i]
+			lineCross = max(lineCross, crossSizes[i])
+		}
+		mainSize = max(mainSize, lineMain)
+		crossSize += lineCross
+		if l > 0 {
+			crossSize += crossGap
+		}
+	}
+	return mainSize, crossSize
+}
+
+// distributeGrow splits extra main-axis space among children in proportion
+// to their grow values, with the rounding remainder going one cell at a
+// time to the first growing children. A child that would grow past its
+// maximum size (maxSizes[i] >= 0) is capped there, and the space it can't
+// take is shared among the remaining growing children.
+func distributeGrow(extra int, growValues, sizes, maxSizes []int) []int {
+	shares := make([]int, len(growValues))
+	active := append([]int(nil), growValues...)
+	split := make([]int, len(growValues))
+
+	for extra > 0 {
+		totalGrow := 0
+		for _, grow := range active {
+			totalGrow += grow
+		}
+		if totalGrow == 0 {
+			break
+		}
+
+		remaining := extra
+		for i, grow := range active {
+			split[i] = 0
+			if grow > 0 {
+				split[i] = (extra * grow) / totalGrow
+				remaining -= split[i]
+			}
+		}
+		for i, grow := range active {
+			if remaining <= 0 {
+				break
+			}
+			if grow > 0 {
+				split[i]++
+				remaining--
+			}
+		}
+
+		// Freeze children that would exceed their maximum, then redistribute
+		capped := false
+		for i, grow := range active {
+			if grow > 0 && maxSizes[i] >= 0 && sizes[i]+split[i] > maxSizes[i] {
+				shares[i] = max(0, maxSizes[i]-sizes[i])
+				extra -= shares[i]
+				active[i] = 0
+				capped = true
+			}
+		}
+		if !capped {
+			for i, grow := range active {
+				if grow > 0 {
+					shares[i] = split[i]
+				}
+			}
+			break
+		}
+	}
+
+	return shares
+}
+
 // CollectTextContent recursively collects all text content from a node.
 func CollectTextContent(node gox.VNode) string {
 	if IsTextNode(node) {
@@ -1044,8 +1271,11 @@ func CollectTextContent(node gox.VNode) string {
 }
 
 // WrapText wraps text to fit within a given width.
-// Handles ANSI escape sequences correctly: they don't count toward width
-// and are preserved across wrapped lines.
+// Width is measured in display columns: wide characters (CJK, emoji) take
+// two columns, and zero-width runes (joiners, combining marks, direction
+// marks) stay attached to the preceding character so clusters are never
+// split. Handles ANSI escape sequences correctly: they don't count toward
+// width and are preserved across wrapped lines.
 func WrapText(text string, maxWidth int) []string {
 	if maxWidth <= 0 {
 		return []string{text}
@@ -1060,142 +1290,130 @@ func WrapText(text string, maxWidth int) []string {
 			continue
 		}
 
-		// Strip ANSI for wrapping calculations, but preserve in output
-		stripped := StripAnsi(line)
-
-		if !ContainsAnsi(line) {
-			// No ANSI: use the original fast path
-			remaining := line
-			for RuneWidth(remaining) > maxWidth {
-				byteLimit := 0
-				displayWidth := 0
-				lastSpace := -1
-				lastSpaceDisplay := 0
-				for i, r := range remaining {
-					rw := runewidth.RuneWidth(r)
-					if displayWidth+rw > maxWidth {
-						byteLimit = i
-						break
-					}
-					displayWidth += rw
-					if r == ' ' {
-						lastSpace = i
-						lastSpaceDisplay = displayWidth
-					}
-					byteLimit = i + utf8.RuneLen(r)
-				}
+		remaining := line
+		for RuneWidth(remaining) > maxWidth {
+			byteLimit, lastSpace, lastSpaceDisplay := findWrapPoint(remaining, maxWidth)
 
-				if lastSpace > 0 && lastSpaceDisplay >= maxWidth/2 {
-					outputLines = append(outputLines, remaining[:lastSpace])
-					remaining = strings.TrimLeft(remaining[lastSpace:], " ")
-				} else {
-					outputLines = append(outputLines, remaining[:byteLimit])
-					remaining = remaining[byteLimit:]
-				}
-			}
-
-			if len(remaining) > 0 {
-				outputLines = append(outputLines, remaining)
-			}
-		} else {
-			// ANSI present: wrap based on visible width, include escape codes in output.
-			// Simple approach: split stripped text, then reconstruct with ANSI.
-			// For correctness with word-wrap, just hard-wrap at maxWidth on visible chars.
-			_ = stripped
-			remaining := line
-			for RuneWidth(remaining) > maxWidth {
-				// Walk the raw string, tracking visible width
-				displayWidth := 0
-				byteLimit := 0
-				lastSpace := -1
-				lastSpaceDisplay := 0
-				i := 0
-				for i < len(remaining) {
-					if remaining[i] == '\x1b' && i+1 < len(remaining) && remaining[i+1] == '[' {
-						// Skip CSI sequence (zero width)
-						i += 2
-						for i < len(remaining) && !(remaining[i] >= 0x40 && remaining[i] <= 0x7E) {
-							i++
-						}
-						if i < len(remaining) {
-							i++
-						}
-						continue
-					}
-					if remaining[i] == '\x1b' {
-						i += 2
-						continue
-					}
-
-					r, size := utf8.DecodeRuneInString(remaining[i:])
-					rw := runewidth.RuneWidth(r)
-					if displayWidth+rw > maxWidth {
-						byteLimit = i
-						break
-					}
-					displayWidth += rw
-					if r == ' ' {
-						lastSpace = i
-						lastSpaceDisplay = displayWidth
-					}
-					byteLimit = i + size
-					i += size
-				}
-				if byteLimit == 0 {
-					byteLimit = len(remaining)
-				}
-
-				if lastSpace > 0 && lastSpaceDisplay >= maxWidth/2 {
-					outputLines = append(outputLines, remaining[:lastSpace])
-					remaining = strings.TrimLeft(remaining[lastSpace:], " ")
-				} else {
-					outputLines = append(outputLines, remaining[:byteLimit])
-					remaining = remaining[byteLimit:]
-				}
+			if lastSpace > 0 && lastSpaceDisplay >= maxWidth/2 {
+				outputLines = append(outputLines, remaining[:lastSpace])
+				remaining = strings.TrimLeft(remaining[lastSpace:], " ")
+			} else {
+				outputLines = append(outputLines, remaining[:byteLimit])
+				remaining = remaining[byteLimit:]
 			}
+		}
 
-			if len(remaining) > 0 {
-				outputLines = append(outputLines, remaining)
-			}
+		if len(remaining) > 0 {
+			outputLines = append(outputLines, remaining)
 		}
 	}
 
 	return outputLines
 }
 
-// Helper functions
+// findWrapPoint walks s until maxWidth display columns are filled.
+// It returns the byte offset where a hard wrap should happen, plus the byte
+// offset and display width of the last space seen (lastSpace is -1 if none).
+// Escape sequences are skipped as zero-width. At least one character is
+// always consumed so wrapping makes progress even when a single wide
+// character exceeds maxWidth.
+func findWrapPoint(s string, maxWidth int) (byteLimit, lastSpace, lastSpaceDisplay int) {
+	displayWidth := 0
+	lastSpace = -1
+	i := 0
+	for i < len(s) {
+		if s[i] == '\x1b' {
+			i = skipEscape(s, i)
+			byteLimit = i
+			continue
+		}
 
-func GetIntProp(props gox.Props, key string, defaultVal int) int {
-	if props == nil {
-		return defaultVal
+		size, cw := nextCluster(s, i)
+		if displayWidth+cw > maxWidth && displayWidth > 0 {
+			break
+		}
+		displayWidth += cw
+		if s[i] == ' ' {
+			lastSpace = i
+			lastSpaceDisplay = displayWidth
+		}
+		i += size
+		byteLimit = i
 	}
-	v, ok := props[key]
-	if !ok {
-		return defaultVal
+	return byteLimit, lastSpace, lastSpaceDisplay
+}
+
+// nextCluster returns the byte size and display width of the character
+// starting at s[i], including any zero-width runes that attach to it and
+// runes joined to it with a zero-width joiner.
+func nextCluster(s string, i int) (size, width int) {
+	r, n := utf8.DecodeRuneInString(s[i:])
+	width = runewidth.RuneWidth(r)
+	size = n
+	joined := false
+	for i+size < len(s) {
+		next, nn := utf8.DecodeRuneInString(s[i+size:])
+		if next == '\x1b' {
+			break
+		}
+		if !joined && runewidth.RuneWidth(next) != 0 {
+			break
+		}
+		joined = next == '\u200d'
+		size += nn
 	}
-	switch i := v.(type) {
-	case int:
+	return size, width
+}
+
+// skipEscape returns the index just past the escape sequence at s[i].
+func skipEscape(s string, i int) int {
+	if i+1 < len(s) && s[i+1] == '[' {
+		// CSI: skip params until final byte (0x40-0x7E)
+		i += 2
+		for i < len(s) && !(s[i] >= 0x40 && s[i] <= 0x7E) {
+			i++
+		}
+		if i < len(s) {
+			i++
+		}
 		return i
-	case float64:
-		return int(i)
-	default:
-		return defaultVal
 	}
+	if i+1 < len(s) && s[i+1] == ']' {
+		_, end := parseOSC(s, i)
+		return end
+	}
+	if i+2 > len(s) {
+		return len(s)
+	}
+	return i + 2
+}
+
+// Helper functions
+
+// GetIntProp gets an int property with a default value.
+func GetIntProp(props gox.Props, key string, defaultVal int) int {
+	return PropReader(props).Int(key, defaultVal)
+}
+
+// GetFloatProp gets a float64 property with a default value.
+func GetFloatProp(props gox.Props, key string, defaultVal float64) float64 {
+	return PropReader(props).Float(key, defaultVal)
 }
 
 // GetBoolProp gets a boolean property with a default value.
 func GetBoolProp(props gox.Props, key string, defaultVal bool) bool {
-	if props == nil {
-		return defaultVal
-	}
-	v, ok := props[key]
-	if !ok {
-		return defaultVal
-	}
-	if b, ok := v.(bool); ok {
-		return b
-	}
-	return defaultVal
+	return PropReader(props).Bool(key, defaultVal)
+}
+
+// GetStringProp gets a string property with a default value.
+func GetStringProp(props gox.Props, key string, defaultVal string) string {
+	return PropReader(props).String(key, defaultVal)
+}
+
+// GetRuneProp gets a rune property with a default value.
+// A string value yields its first rune.
+func GetRuneProp(props gox.Props, key string, defaultVal rune) rune {
+	return PropReader(props).Rune(key, defaultVal)
 }
 
 // GetDirection returns the flex direction from props.
@@ -1204,20 +1422,39 @@ func GetDirection(props gox.Props) Direction {
 }
 
 func getDirection(props gox.Props) Direction {
-	if props == nil {
-		return Column
-	}
-	v, ok := props["direction"]
-	if !ok {
-		return Column
+	if d, ok := props["direction"].(Direction); ok {
+		return d
 	}
-	if s, ok := v.(string); ok {
-		return Direction(s)
+	return Direction(GetStringProp(props, "direction", string(Column)))
+}
+
+// GetGap returns the spacing between children along the main axis:
+// columnGap for rows and rowGap for columns, falling back to gap.
+func GetGap(props gox.Props) int {
+	return getGap(props, getDirection(props))
+}
+
+func getGap(props gox.Props, direction Direction) int {
+	gap := GetIntProp(props, "gap", 0)
+	if direction == Row {
+		return GetIntProp(props, "columnGap", gap)
 	}
-	if d, ok := v.(Direction); ok {
-		return d
+	return GetIntProp(props, "rowGap", gap)
+}
+
+// GetCrossGap returns the spacing between wrapped lines: rowGap for rows
+// and columnGap for columns, falling back to gap.
+func GetCrossGap(props gox.Props) int {
+	if getDirection(props) == Row {
+		return getGap(props, Column)
 	}
-	return Column
+	return getGap(props, Row)
+}
+
+// GetFlexWrap reports whether a box wraps its children onto new lines
+// (flexWrap="wrap"; the default is "nowrap").
+func GetFlexWrap(props gox.Props) bool {
+	return GetStringProp(props, "flexWrap", "nowrap") == "wrap"
 }
 
 // GetJustify returns the justify-content from props.
@@ -1226,20 +1463,10 @@ func GetJustify(props gox.Props) Justify {
 }
 
 func getJustify(props gox.Props) Justify {
-	if props == nil {
-		return JustifyStart
-	}
-	v, ok := props["justify"]
-	if !ok {
-		return JustifyStart
-	}
-	if s, ok := v.(string); ok {
-		return Justify(s)
-	}
-	if j, ok := v.(Justify); ok {
+	if j, ok := props["justify"].(Justify); ok {
 		return j
 	}
-	return JustifyStart
+	return Justify(GetStringProp(props, "justify", string(JustifyStart)))
 }
 
 // GetAlign returns the align-items from props.
@@ -1248,37 +1475,17 @@ func GetAlign(props gox.Props) Align {
 }
 
 func getAlign(props gox.Props) Align {
-	if props == nil {
-		return AlignStretch
-	}
-	v, ok := props["align"]
-	if !ok {
-		return AlignStretch
-	}
-	if s, ok := v.(string); ok {
-		return Align(s)
-	}
-	if a, ok := v.(Align); ok {
+	if a, ok := props["align"].(Align); ok {
 		return a
 	}
-	return AlignStretch
+	return Align(GetStringProp(props, "align", string(AlignStretch)))
 }
 
 func getPosition(props gox.Props) Position {
-	if props == nil {
-		return PositionRelative
-	}
-	v, ok := props["position"]
-	if !ok {
-		return PositionRelative
-	}
-	if s, ok := v.(string); ok {
-		return Position(s)
-	}
-	if p, ok := v.(Position); ok {
+	if p, ok := props["position"].(Position); ok {
 		return p
 	}
-	return PositionRelative
+	return Position(GetStringProp(props, "position", string(PositionRelative)))
 }
 
 func FilterChildren(node gox.VNode, typeStr string) []gox.VNode {
@@ -1298,7 +1505,7 @@ func FilterRelativeChildren(node gox.VNode) []gox.VNode {
 
 func filterRelativeChildren(node gox.VNode) []gox.VNode {
 	var result []gox.VNode
-	for _, child := range node.Children {
+	for _, child := range inlineFragments(node.Children) {
 		if getPosition(child.Props) != PositionAbsolute {
 			result = append(result, child)
 		}
@@ -1313,10 +1520,36 @@ func FilterAbsoluteChildren(node gox.VNode) []gox.VNode {
 
 func filterAbsoluteChildren(node gox.VNode) []gox.VNode {
 	var result []gox.VNode
-	for _, child := range node.Children {
+	for _, child := range inlineFragments(node.Children) {
 		if getPosition(child.Props) == PositionAbsolute {
 			result = append(result, child)
 		}
 	}
 	return result
 }
+
+// inlineFragments replaces fragment children with their own children, so a
+// fragment's children are laid out as children of the enclosing container
+// and follow its direction. Returns children unchanged if there are none.
+func inlineFragments(children []gox.VNode) []gox.VNode {
+	hasFragment := false
+	for _, child := range children {
+		if t, _ := TypeString(child); t == "fragment" || t == gox.FragmentNodeType {
+			hasFragment = true
+			break
+		}
+	}
+	if !hasFragment {
+		return children
+	}
+
+	result := make([]gox.VNode, 0, len(children))
+	for _, child := range children {
+		if t, _ := TypeString(child); t == "fragment" || t == gox.FragmentNodeType {
+			result = append(result, inlineFragments(child.Children)...)
+			continue
+		}
+		result = append(result, child)
+	}
+	return result
+}
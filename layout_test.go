@@ -2,6 +2,7 @@ package goli
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -60,6 +61,18 @@ func TestWrapText(t *testing.T) {
 			maxWidth: 10,
 			expected: []string{"short", "this line", "is too", "long"},
 		},
+		{
+			name:     "preserves blank lines",
+			text:     "a\n\nb",
+			maxWidth: 10,
+			expected: []string{"a", "", "b"},
+		},
+		{
+			name:     "preserves trailing newline",
+			text:     "a\n",
+			maxWidth: 10,
+			expected: []string{"a", ""},
+		},
 
 		// Wide characters (CJK, each 2 display columns)
 		{
@@ -115,6 +128,34 @@ func TestWrapText(t *testing.T) {
 			expected: []string{"hello", "🌐🎉"},
 		},
 
+		// Mixed CJK + emoji
+		{
+			name:     "mixed CJK and emoji",
+			text:     "日🌐本🎉語",
+			maxWidth: 4,
+			expected: []string{"日🌐", "本🎉", "語"},
+		},
+
+		// Zero-width runes stay attached to the preceding character
+		{
+			name:     "ZWJ sequence is not split",
+			text:     "ab👨\u200d👩\u200d👧cd",
+			maxWidth: 3,
+			expected: []string{"ab", "👨\u200d👩\u200d👧c", "d"},
+		},
+		{
+			name:     "RTL marks are zero width",
+			text:     "\u200fabc\u200f def",
+			maxWidth: 4,
+			expected: []string{"\u200fabc\u200f", "def"},
+		},
+		{
+			name:     "combining mark stays with base",
+			text:     "ae\u0301io",
+			maxWidth: 2,
+			expected: []string{"ae\u0301", "io"},
+		},
+
 		// Edge cases
 		{
 			name:     "zero width returns as-is",
@@ -169,6 +210,34 @@ func TestWrapText(t *testing.T) {
 	}
 }
 
+func TestWrapText_WideCharWiderThanMax(t *testing.T) {
+	// A single wide character can't fit in one column; it must still be
+	// emitted (one per line) rather than looping forever.
+	result := WrapText("日本", 1)
+	if len(result) != 2 || result[0] != "日" || result[1] != "本" {
+		t.Errorf("expected [日 本], got %q", result)
+	}
+}
+
+func TestWrapText_Ansi(t *testing.T) {
+	result := WrapText("\x1b[31m日本語\x1b[0m", 4)
+	if len(result) != 2 {
+		t.Fatalf("expected 2 lines, got %q", result)
+	}
+	if StripAnsi(result[0]) != "日本" || StripAnsi(result[1]) != "語" {
+		t.Errorf("unexpected wrap: %q", result)
+	}
+	if !strings.HasPrefix(result[0], "\x1b[31m") {
+		t.Errorf("expected color code kept on first line, got %q", result[0])
+	}
+
+	link := "\x1b]8;;https://example.com\x1b\\abcdef\x1b]8;;\x1b\\"
+	result = WrapText(link, 3)
+	if len(result) != 2 || StripAnsi(result[0]) != "abc" || StripAnsi(result[1]) != "def" {
+		t.Errorf("expected hyperlink URL to be zero width, got %q", result)
+	}
+}
+
 func TestWrapText_PreservesContent(t *testing.T) {
 	// Property: joining wrapped lines should give back the original words
 	inputs := []string{
@@ -239,6 +308,16 @@ func TestRuneWidth(t *testing.T) {
 			input:    "Hello 世界 🌍",
 			expected: 13, // 5 + 1 + 4 + 1 + 2
 		},
+		{
+			name:     "combining mark",
+			input:    "e\u0301",
+			expected: 1, // Zero-width accent on e
+		},
+		{
+			name:     "CJK with ANSI codes",
+			input:    "\x1b[31m世界\x1b[0m",
+			expected: 4, // Escape sequences are stripped
+		},
 	}
 
 	for _, tt := range tests {
@@ -287,7 +366,7 @@ func TestComputeLayout_Fragment(t *testing.T) {
 		},
 	}
 
-	box := ComputeLayout(node, LayoutContext{X: 0, Y: 0, Width: 20, Height: 5})
+	box := ComputeLayout(node, LayoutContext{X: 0, Y: 0, Width: 20, Height: 5}).Tree()
 	buf := NewCellBuffer(20, 5)
 	RenderToBuffer(box, buf, nil)
 	output := buf.ToDebugString()
@@ -302,3 +381,632 @@ func TestComputeLayout_Fragment(t *testing.T) {
 		t.Errorf("should contain CCC after fragment, got:\n%s", output)
 	}
 }
+
+func TestMeasureSelect_CJKOptions(t *testing.T) {
+	node := gox.Element("select", gox.Props{"pointerWidth": 2},
+		gox.Element("option", nil, gox.Text("日本語")),
+		gox.Element("option", nil, gox.Text("abc")),
+	)
+
+	w, h := measureSelect(node, LayoutContext{})
+	if w != 2+6 {
+		t.Errorf("expected width 8 (pointer + 3 wide runes), got %d", w)
+	}
+	if h != 2 {
+		t.Errorf("expected height 2, got %d", h)
+	}
+}
+
+func TestMeasureLink_CJKText(t *testing.T) {
+	node := gox.Element("link", gox.Props{"url": "https://example.com"}, gox.Text("中文 link"))
+
+	w, h := measureLink(node, LayoutContext{})
+	if w != 9 {
+		t.Errorf("expected width 9, got %d", w)
+	}
+	if h != 1 {
+		t.Errorf("expected height 1, got %d", h)
+	}
+}
+
+func TestMeasureLink_Shortcut(t *testing.T) {
+	node := gox.Element("link", gox.Props{"shortcut": "^O"}, gox.Text("Open"))
+
+	w, _ := measureLink(node, LayoutContext{})
+	if w != 8 {
+		t.Errorf("expected width 8 (text + 2 spaces + shortcut), got %d", w)
+	}
+}
+
+func TestRenderLink_ShortcutRightAligned(t *testing.T) {
+	node := gox.Element("link", gox.Props{"shortcut": "^O", "width": 12}, gox.Text("Open"))
+
+	box := ComputeLayout(node, LayoutContext{Width: 20, Height: 1}).Box
+	buf := NewCellBuffer(20, 1)
+	RenderToBuffer(box, buf, nil)
+
+	if got := strings.TrimRight(buf.ToDebugString(), " "); got != "Open      ^O" {
+		t.Errorf("expected shortcut right-aligned, got %q", got)
+	}
+	if c := buf.Get(10, 0); !c.Style.Dim || c.Style.HyperlinkURL != "" {
+		t.Errorf("expected dim shortcut outside the hyperlink, got %+v", c.Style)
+	}
+}
+
+func TestComputeLayout_AbsoluteBoxesSortedByZIndex(t *testing.T) {
+	overlay := func(z int, fill string) gox.VNode {
+		return gox.Element("box", gox.Props{
+			"position": "absolute", "x": 1, "y": 0, "width": 3, "height": 1, "zIndex": z,
+		}, gox.Element("text", nil, gox.Text(fill)))
+	}
+	// The higher z-index comes first in the tree but must draw last
+	node := gox.Element("box", gox.Props{"width": 10, "height": 2},
+		overlay(5, "HHH"),
+		overlay(1, "lll"),
+	)
+
+	result := ComputeLayout(node, LayoutContext{Width: 10, Height: 2})
+	if len(result.AbsoluteBoxes) != 2 {
+		t.Fatalf("expected 2 absolute boxes, got %d", len(result.AbsoluteBoxes))
+	}
+	if result.AbsoluteBoxes[0].ZIndex != 1 || result.AbsoluteBoxes[1].ZIndex != 5 {
+		t.Errorf("expected ascending z-index, got %d then %d",
+			result.AbsoluteBoxes[0].ZIndex, result.AbsoluteBoxes[1].ZIndex)
+	}
+
+	buf := NewCellBuffer(10, 2)
+	RenderToBuffer(result.Tree(), buf, nil)
+	if got := buf.Get(2, 0).Char; got != 'H' {
+		t.Errorf("expected higher z-index box on top, got %q", got)
+	}
+}
+
+func TestGetStringProp(t *testing.T) {
+	tests := []struct {
+		name  string
+		props gox.Props
+		want  string
+	}{
+		{"nil props", nil, "def"},
+		{"missing key", gox.Props{"other": "x"}, "def"},
+		{"int value", gox.Props{"key": 3}, "def"},
+		{"string value", gox.Props{"key": "row"}, "row"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetStringProp(tt.props, "key", "def"); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetRuneProp(t *testing.T) {
+	tests := []struct {
+		name  string
+		props gox.Props
+		want  rune
+	}{
+		{"nil props", nil, '-'},
+		{"missing key", gox.Props{"other": '*'}, '-'},
+		{"int value", gox.Props{"key": 3}, '-'},
+		{"rune value", gox.Props{"key": '*'}, '*'},
+		{"string value", gox.Props{"key": "│x"}, '│'},
+		{"empty string", gox.Props{"key": ""}, '-'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetRuneProp(tt.props, "key", '-'); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func scrollRows(n int) []gox.VNode {
+	rows := make([]gox.VNode, n)
+	for i := range rows {
+		rows[i] = gox.Element("text", nil, gox.Text("row"))
+	}
+	return rows
+}
+
+func TestLayoutBox_ScrollHeight(t *testing.T) {
+	node := gox.Element("box", gox.Props{"overflow": "scroll", "height": 5, "width": 10},
+		scrollRows(20)...,
+	)
+
+	box := ComputeLayout(node, LayoutContext{Width: 10, Height: 5}).Box
+	if box.ScrollHeight != 20 {
+		t.Errorf("expected ScrollHeight 20, got %d", box.ScrollHeight)
+	}
+	if box.ScrollWidth != 3 {
+		t.Errorf("expected ScrollWidth 3, got %d", box.ScrollWidth)
+	}
+}
+
+func TestLayoutBox_ScrollHeightCountsGaps(t *testing.T) {
+	node := gox.Element("box", gox.Props{"overflow": "hidden", "height": 5, "gap": 1},
+		scrollRows(3)...,
+	)
+
+	box := ComputeLayout(node, LayoutContext{Width: 10, Height: 5}).Box
+	if box.ScrollHeight != 5 {
+		t.Errorf("expected ScrollHeight 5 (3 rows + 2 gaps), got %d", box.ScrollHeight)
+	}
+}
+
+func TestLayoutBox_RowGap(t *testing.T) {
+	node := gox.Element("box", gox.Props{"rowGap": 2, "gap": 1},
+		scrollRows(3)...,
+	)
+
+	w, h := MeasureNode(node)
+	if w != 3 || h != 7 {
+		t.Errorf("expected 3x7 (3 rows + 2 gaps of 2), got %dx%d", w, h)
+	}
+
+	box := ComputeLayout(node, LayoutContext{Width: 10, Height: 10}).Box
+	for i, want := range []int{0, 3, 6} {
+		if got := box.Children[i].Y; got != want {
+			t.Errorf("child %d: expected y=%d, got %d", i, want, got)
+		}
+	}
+}
+
+func TestLayoutBox_ColumnGap(t *testing.T) {
+	tests := []struct {
+		name   string
+		props  gox.Props
+		wantW  int
+		wantXs []int
+	}{
+		{"columnGap", gox.Props{"direction": "row", "columnGap": 2}, 13, []int{0, 5, 10}},
+		{"overrides gap", gox.Props{"direction": "row", "gap": 1, "columnGap": 0}, 9, []int{0, 3, 6}},
+		{"rowGap ignored in row", gox.Props{"direction": "row", "rowGap": 4}, 9, []int{0, 3, 6}},
+	}
+	for _, tt := range tests {
+		node := gox.Element("box", tt.props, scrollRows(3)...)
+		if w, _ := MeasureNode(node); w != tt.wantW {
+			t.Errorf("%s: expected width %d, got %d", tt.name, tt.wantW, w)
+		}
+		box := ComputeLayout(node, LayoutContext{Width: 20, Height: 5}).Box
+		for i, want := range tt.wantXs {
+			if got := box.Children[i].X; got != want {
+				t.Errorf("%s: child %d expected x=%d, got %d", tt.name, i, want, got)
+			}
+		}
+	}
+}
+
+func TestLayoutBox_FlexWrap(t *testing.T) {
+	node := gox.Element("box", gox.Props{"direction": "row", "flexWrap": "wrap", "width": 10},
+		scrollRows(5)...,
+	)
+
+	if w, h := MeasureNode(node); w != 10 || h != 2 {
+		t.Errorf("expected 10x2 (two lines), got %dx%d", w, h)
+	}
+
+	box := ComputeLayout(node, LayoutContext{Width: 20, Height: 5}).Box
+	want := [][2]int{{0, 0}, {3, 0}, {6, 0}, {0, 1}, {3, 1}}
+	for i, pos := range want {
+		if got := box.Children[i]; got.X != pos[0] || got.Y != pos[1] {
+			t.Errorf("child %d: expected (%d,%d), got (%d,%d)", i, pos[0], pos[1], got.X, got.Y)
+		}
+	}
+}
+
+func TestLayoutBox_FlexWrapGapsAndAlign(t *testing.T) {
+	children := []gox.VNode{
+		gox.Element("box", gox.Props{"width": 4, "height": 2}),
+		gox.Element("box", gox.Props{"width": 4, "height": 1}),
+		gox.Element("box", gox.Props{"width": 4, "height": 1}),
+	}
+	node := gox.Element("box", gox.Props{
+		"direction": "row", "flexWrap": "wrap", "align": "end",
+		"width": 10, "columnGap": 1, "rowGap": 1,
+	}, children...)
+
+	// 4 + 1 + 4 fits in 10; the third child wraps below the 2-high first line
+	if _, h := MeasureNode(node); h != 4 {
+		t.Errorf("expected height 4, got %d", h)
+	}
+	box := ComputeLayout(node, LayoutContext{Width: 10, Height: 10}).Box
+	want := [][2]int{{0, 0}, {5, 1}, {0, 3}}
+	for i, pos := range want {
+		if got := box.Children[i]; got.X != pos[0] || got.Y != pos[1] {
+			t.Errorf("child %d: expected (%d,%d), got (%d,%d)", i, pos[0], pos[1], got.X, got.Y)
+		}
+	}
+}
+
+func TestLayoutBox_FlexWrapColumn(t *testing.T) {
+	node := gox.Element("box", gox.Props{"flexWrap": "wrap", "height": 2, "columnGap": 1},
+		scrollRows(3)...,
+	)
+	if w, h := MeasureNode(node); w != 7 || h != 2 {
+		t.Errorf("expected 7x2, got %dx%d", w, h)
+	}
+	box := ComputeLayout(node, LayoutContext{Width: 20, Height: 5}).Box
+	want := [][2]int{{0, 0}, {0, 1}, {4, 0}}
+	for i, pos := range want {
+		if got := box.Children[i]; got.X != pos[0] || got.Y != pos[1] {
+			t.Errorf("child %d: expected (%d,%d), got (%d,%d)", i, pos[0], pos[1], got.X, got.Y)
+		}
+	}
+}
+
+func TestScrollView_ContentSize(t *testing.T) {
+	Reset()
+	view := NewScrollView("list")
+
+	var output strings.Builder
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("box", gox.Props{"id": "list", "overflow": "scroll", "height": 5},
+				scrollRows(20)...,
+			),
+		)
+	}, Options{Width: 10, Height: 8, Output: &output, DisableThrottle: true})
+	defer app.Dispose()
+
+	app.SetLayoutCallback(view.Update)
+	app.Rerender()
+
+	if _, h := view.ContentSize(); h != 20 {
+		t.Errorf("expected content height 20, got %d", h)
+	}
+}
+
+func TestComputeLayout_FragmentInRowLaysOutInline(t *testing.T) {
+	node := gox.Element("box", gox.Props{"direction": "row"},
+		gox.Fragment(
+			gox.Element("text", nil, gox.Text("ab")),
+			gox.Element("text", nil, gox.Text("cd")),
+		),
+	)
+
+	buf := NewCellBuffer(10, 2)
+	RenderToBuffer(ComputeLayout(node, LayoutContext{Width: 10, Height: 2}).Tree(), buf, nil)
+
+	if got := strings.TrimRight(strings.SplitN(buf.ToDebugString(), "\n", 2)[0], " "); got != "abcd" {
+		t.Errorf("expected fragment children inline as %q, got %q", "abcd", got)
+	}
+	if w, h := measureNode(node); w != 4 || h != 1 {
+		t.Errorf("expected measured 4x1, got %dx%d", w, h)
+	}
+}
+
+func TestRenderToBuffer_FragmentChildrenClipped(t *testing.T) {
+	lines := []string{"l0", "l1", "l2", "l3", "l4", "l5", "l6", "l7", "l8", "l9"}
+	node := gox.Element("box", gox.Props{"overflow": "hidden", "height": 3},
+		gox.Fragment(gox.Map(lines, func(line string) gox.VNode {
+			return gox.Element("text", nil, gox.Text(line))
+		})...),
+	)
+
+	buf := NewCellBuffer(10, 10)
+	RenderToBuffer(ComputeLayout(node, LayoutContext{Width: 10, Height: 10}).Tree(), buf, nil)
+
+	rows := strings.Split(buf.ToDebugString(), "\n")
+	for y := 0; y < 10; y++ {
+		want := ""
+		if y < 3 {
+			want = lines[y]
+		}
+		if got := strings.TrimRight(rows[y], " "); got != want {
+			t.Errorf("row %d: expected %q, got %q", y, want, got)
+		}
+	}
+}
+
+func TestRenderToBuffer_FragmentElementAtRoot(t *testing.T) {
+	node := gox.Element("fragment", nil,
+		gox.Element("text", nil, gox.Text("a")),
+		gox.Element("text", nil, gox.Text("b")),
+	)
+
+	buf := NewCellBuffer(4, 2)
+	RenderToBuffer(ComputeLayout(node, LayoutContext{Width: 4, Height: 2}).Tree(), buf, nil)
+
+	if x, y, found := buf.FindText("a"); !found || x != 0 || y != 0 {
+		t.Errorf("expected a at (0,0), got (%d,%d) found=%v", x, y, found)
+	}
+	if x, y, found := buf.FindText("b"); !found || x != 0 || y != 1 {
+		t.Errorf("expected b at (0,1), got (%d,%d) found=%v", x, y, found)
+	}
+}
+
+func TestComputeLayout_FlexMinWidthKeptWhenSiblingGrows(t *testing.T) {
+	node := gox.Element("box", gox.Props{"direction": "row", "width": 40},
+		gox.Element("box", gox.Props{"minWidth": 20}, gox.Element("text", nil, gox.Text("a"))),
+		gox.Element("box", gox.Props{"grow": 1}, gox.Element("text", nil, gox.Text("b"))),
+	)
+
+	box := ComputeLayout(node, LayoutContext{Width: 40, Height: 1}).Box
+	if w := box.Children[0].Width; w != 20 {
+		t.Errorf("expected minWidth child to keep width 20, got %d", w)
+	}
+	if x, w := box.Children[1].X, box.Children[1].Width; x != 20 || w != 20 {
+		t.Errorf("expected growing sibling at x=20 with width 20, got x=%d width=%d", x, w)
+	}
+}
+
+func TestComputeLayout_FlexMaxWidthCapsGrow(t *testing.T) {
+	node := gox.Element("box", gox.Props{"direction": "row", "width": 40},
+		gox.Element("box", gox.Props{"grow": 5, "maxWidth": 10}, gox.Element("text", nil, gox.Text("a"))),
+		gox.Element("box", gox.Props{"grow": 1}, gox.Element("text", nil, gox.Text("b"))),
+	)
+
+	box := ComputeLayout(node, LayoutContext{Width: 40, Height: 1}).Box
+	if w := box.Children[0].Width; w != 10 {
+		t.Errorf("expected maxWidth child capped at 10, got %d", w)
+	}
+	if w := box.Children[1].Width; w != 30 {
+		t.Errorf("expected sibling to take the remaining 30, got %d", w)
+	}
+}
+
+func TestComputeLayout_FlexMaxHeightCapsGrowInColumn(t *testing.T) {
+	node := gox.Element("box", gox.Props{"direction": "column", "height": 12},
+		gox.Element("box", gox.Props{"grow": 1, "maxHeight": 3}),
+		gox.Element("box", gox.Props{"grow": 1}),
+	)
+
+	box := ComputeLayout(node, LayoutContext{Width: 5, Height: 12}).Box
+	if h := box.Children[0].Height; h != 3 {
+		t.Errorf("expected maxHeight child capped at 3, got %d", h)
+	}
+	if y, h := box.Children[1].Y, box.Children[1].Height; y != 3 || h != 9 {
+		t.Errorf("expected sibling at y=3 with height 9, got y=%d height=%d", y, h)
+	}
+}
+
+func TestComputeLayout_MaxSizeCapsFill(t *testing.T) {
+	node := gox.Element("box", nil,
+		gox.Element("box", gox.Props{"maxWidth": 40, "border": "single"}),
+		gox.Element("box", gox.Props{"direction": "row", "height": 5},
+			gox.Element("box", gox.Props{"maxHeight": 2}, gox.Element("text", nil, gox.Text("a"))),
+		),
+	)
+
+	box := ComputeLayout(node, LayoutContext{Width: 100, Height: 10}).Box
+	if w := box.Children[0].Width; w != 40 {
+		t.Errorf("expected stretched sidebar capped at 40 columns, got %d", w)
+	}
+	if h := box.Children[1].Children[0].Height; h != 2 {
+		t.Errorf("expected stretched row child capped at height 2, got %d", h)
+	}
+
+	root := ComputeLayout(gox.Element("box", gox.Props{"maxWidth": 30}), LayoutContext{Width: 100, Height: 10}).Box
+	if root.Width != 30 {
+		t.Errorf("expected root filling the terminal capped at 30, got %d", root.Width)
+	}
+}
+
+func TestMeasureNode_MaxSize(t *testing.T) {
+	tests := []struct {
+		name         string
+		node         gox.VNode
+		wantW, wantH int
+	}{
+		{"box content", gox.Element("box", gox.Props{"direction": "row", "maxWidth": 5}, scrollRows(3)...), 5, 1},
+		{"explicit size", gox.Element("box", gox.Props{"width": 30, "height": 9, "maxWidth": 20, "maxHeight": 4}), 20, 4},
+		{"min wins", gox.Element("box", gox.Props{"minWidth": 8, "maxWidth": 4}), 8, 0},
+		{"text", gox.Element("text", gox.Props{"maxWidth": 4, "maxHeight": 1}, gox.Text("hello\nworld")), 4, 1},
+		{"flexWrap within maxWidth", gox.Element("box", gox.Props{"direction": "row", "flexWrap": "wrap", "maxWidth": 7}, scrollRows(3)...), 6, 2},
+	}
+	for _, tt := range tests {
+		if w, h := MeasureNode(tt.node); w != tt.wantW || h != tt.wantH {
+			t.Errorf("%s: expected %dx%d, got %dx%d", tt.name, tt.wantW, tt.wantH, w, h)
+		}
+	}
+}
+
+func TestLayoutText_MaxWidthWraps(t *testing.T) {
+	node := gox.Element("text", gox.Props{"wrap": true, "maxWidth": 5, "maxHeight": 2}, gox.Text("one two three"))
+
+	box := ComputeLayout(node, LayoutContext{Width: 40, Height: 5}).Box
+	if box.Width > 5 || box.Height != 2 {
+		t.Errorf("expected at most 5 wide and 2 lines, got %dx%d", box.Width, box.Height)
+	}
+}
+
+func TestText_MaxHeightTruncateChar(t *testing.T) {
+	node := gox.Element("text", gox.Props{"maxHeight": 2, "truncateChar": "…"}, gox.Text("one\ntwo\nsix"))
+
+	if w, h := MeasureNode(node); w != 4 || h != 2 {
+		t.Errorf("expected measure 4x2, got %dx%d", w, h)
+	}
+	box := ComputeLayout(node, LayoutContext{Width: 10, Height: 5}).Tree()
+	if text, _ := GetTextContent(box.Node); text != "one\ntwo…" {
+		t.Errorf("expected lines cut by maxHeight to be marked, got %q", text)
+	}
+}
+
+func TestMeasureNode_WrapsAtMaxWidth(t *testing.T) {
+	node := gox.Element("text", gox.Props{"wrap": true, "maxWidth": 5}, gox.Text("one two three"))
+
+	box := ComputeLayout(node, LayoutContext{Width: 40, Height: 5}).Box
+	if w, h := MeasureNode(node); w != box.Width || h != box.Height {
+		t.Errorf("expected measure to match layout %dx%d, got %dx%d", box.Width, box.Height, w, h)
+	}
+	if box.Height != 3 {
+		t.Errorf("expected 3 wrapped lines, got %d", box.Height)
+	}
+}
+
+func TestLayoutBox_AspectRatio(t *testing.T) {
+	tests := []struct {
+		name         string
+		props        gox.Props
+		wantW, wantH int
+	}{
+		{"square from width", gox.Props{"width": 20, "aspectRatio": 1.0}, 20, 20},
+		{"wide from width", gox.Props{"width": 32, "aspectRatio": 16.0 / 9}, 32, 18},
+		{"width from height", gox.Props{"height": 5, "aspectRatio": 2}, 10, 5},
+		{"both explicit", gox.Props{"width": 8, "height": 3, "aspectRatio": 1.0}, 8, 3},
+		{"non-positive ratio", gox.Props{"width": 8, "height": 3, "aspectRatio": -1.0}, 8, 3},
+	}
+	for _, tt := range tests {
+		node := gox.Element("box", tt.props)
+		if w, h := MeasureNode(node); w != tt.wantW || h != tt.wantH {
+			t.Errorf("%s: expected measure %dx%d, got %dx%d", tt.name, tt.wantW, tt.wantH, w, h)
+		}
+		box := ComputeLayout(node, LayoutContext{Width: 40, Height: 30}).Box
+		if box.Width != tt.wantW || box.Height != tt.wantH {
+			t.Errorf("%s: expected layout %dx%d, got %dx%d", tt.name, tt.wantW, tt.wantH, box.Width, box.Height)
+		}
+	}
+}
+
+func TestLayoutBox_AspectRatioNotFinite(t *testing.T) {
+	// The content decides the size that isn't set
+	content := gox.Element("text", nil, gox.Text("hi"))
+	for _, ratio := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if w, h := MeasureNode(gox.Element("box", gox.Props{"width": 8, "aspectRatio": ratio}, content)); w != 8 || h != 1 {
+			t.Errorf("aspectRatio %v: expected the ratio ignored (8x1), got %dx%d", ratio, w, h)
+		}
+		if w, h := MeasureNode(gox.Element("box", gox.Props{"height": 3, "aspectRatio": ratio}, content)); w != 2 || h != 3 {
+			t.Errorf("aspectRatio %v: expected the ratio ignored (2x3), got %dx%d", ratio, w, h)
+		}
+	}
+}
+
+func TestLayoutBox_AspectRatioInStretchedColumn(t *testing.T) {
+	node := gox.Element("box", nil,
+		gox.Element("box", gox.Props{"width": 6, "aspectRatio": 2.0}),
+		gox.Element("text", nil, gox.Text("below")),
+	)
+	box := ComputeLayout(node, LayoutContext{Width: 20, Height: 10}).Box
+	if h := box.Children[0].Height; h != 3 {
+		t.Errorf("expected derived height 3, got %d", h)
+	}
+	if y := box.Children[1].Y; y != 3 {
+		t.Errorf("expected the next sibling at y=3, got %d", y)
+	}
+}
+
+func TestRenderBox_CursorProp(t *testing.T) {
+	Reset()
+	cursor, setCursor := CreateSignal(gox.Props{"x": 1, "y": 0, "style": Style{Background: ColorRed}})
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{"border": "single", "width": 9, "height": 3, "cursor": cursor()},
+			gox.Element("text", gox.Props{"color": "green"}, gox.Text("hello")),
+		)
+	}, Options{Width: 10, Height: 3, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+	h := app.Headless()
+
+	h.AssertCell(t, 2, 1, 'e', Style{Color: ColorGreen, Background: ColorRed})
+	h.AssertCell(t, 1, 1, 'h', Style{Color: ColorGreen})
+	h.AssertCell(t, 3, 1, 'l', Style{Color: ColorGreen})
+
+	// Past the text the cursor draws on a blank cell, inverse by default
+	setCursor(gox.Props{"x": 6, "y": 0})
+	h.AssertCell(t, 7, 1, ' ', Style{Inverse: true})
+	h.AssertCell(t, 2, 1, 'e', Style{Color: ColorGreen})
+}
+
+func TestRenderBox_CursorOutsideInnerAreaIgnored(t *testing.T) {
+	node := gox.Element("box", gox.Props{"width": 3, "height": 1, "cursor": gox.Props{"x": 5, "y": 0}},
+		gox.Element("text", nil, gox.Text("abc")),
+	)
+	buf := NewCellBuffer(8, 1)
+	RenderToBuffer(ComputeLayout(node, LayoutContext{Width: 8, Height: 1}).Tree(), buf, nil)
+
+	for x := 0; x < 8; x++ {
+		if got := buf.Get(x, 0).Style; !got.Equal(Style{}) {
+			t.Errorf("expected no cursor style at (%d,0), got %+v", x, got)
+		}
+	}
+}
+
+func TestText_MaxLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		props gox.Props
+		wantW int
+		wantH int
+		rows  []string
+	}{
+		{"unset", nil, 3, 5, []string{"one", "two", "six", "ten", "end"}},
+		{"clamped", gox.Props{"maxLines": 2}, 3, 2, []string{"one", "two"}},
+		{"truncate char", gox.Props{"maxLines": 3, "truncateChar": "…"}, 4, 3, []string{"one", "two", "six…"}},
+		{"fits", gox.Props{"maxLines": 5, "truncateChar": "…"}, 3, 5, []string{"one", "two", "six", "ten", "end"}},
+	}
+	for _, tt := range tests {
+		node := gox.Element("text", tt.props, gox.Text("one\ntwo\nsix\nten\nend"))
+		if w, h := MeasureNode(node); w != tt.wantW || h != tt.wantH {
+			t.Errorf("%s: expected %dx%d, got %dx%d", tt.name, tt.wantW, tt.wantH, w, h)
+		}
+
+		box := ComputeLayout(node, LayoutContext{Width: 10, Height: 10}).Tree()
+		if box.Height != tt.wantH {
+			t.Errorf("%s: expected layout height %d, got %d", tt.name, tt.wantH, box.Height)
+		}
+		buf := NewCellBuffer(10, 6)
+		RenderToBuffer(box, buf, nil)
+		rows := strings.Split(buf.ToDebugString(), "\n")
+		for y, row := range rows {
+			want := ""
+			if y < len(tt.rows) {
+				want = tt.rows[y]
+			}
+			if got := strings.TrimRight(row, " "); got != want {
+				t.Errorf("%s: row %d expected %q, got %q", tt.name, y, want, got)
+			}
+		}
+	}
+}
+
+func TestText_MaxLinesAfterWrap(t *testing.T) {
+	node := gox.Element("text", gox.Props{"wrap": true, "maxLines": 2, "truncateChar": "…"},
+		gox.Text("aaaa bbbb cccc"),
+	)
+	box := ComputeLayout(node, LayoutContext{Width: 5, Height: 10}).Tree()
+	if box.Height != 2 {
+		t.Errorf("expected wrapped text clamped to 2 lines, got %d", box.Height)
+	}
+	if text, _ := GetTextContent(box.Node); text != "aaaa\nbbbb…" {
+		t.Errorf("expected %q, got %q", "aaaa\nbbbb…", text)
+	}
+}
+
+func TestText_TruncateCharOnFullWidthLine(t *testing.T) {
+	node := gox.Element("text", gox.Props{"maxLines": 2, "truncateChar": "…"},
+		gox.Text("abcd\nefgh\nijkl"),
+	)
+	box := ComputeLayout(node, LayoutContext{Width: 4, Height: 10}).Tree()
+	if text, _ := GetTextContent(box.Node); text != "abcd\nefg…" {
+		t.Errorf("expected the marker to replace the last column, got %q", text)
+	}
+	if box.Width != 4 {
+		t.Errorf("expected width 4, got %d", box.Width)
+	}
+
+	buf := NewCellBuffer(4, 2)
+	RenderToBuffer(box, buf, nil)
+	if got := buf.Get(3, 1).Char; got != '…' {
+		t.Errorf("expected the marker in the last column, got %q", got)
+	}
+}
+
+func TestMeasureNode_WrappedTextUnconstrained(t *testing.T) {
+	node := gox.Element("text", gox.Props{"wrap": true}, gox.Text("a long line of text"))
+	if w, h := MeasureNode(node); w != 19 || h != 1 {
+		t.Errorf("expected the unwrapped size 19x1, got %dx%d", w, h)
+	}
+}
+
+func TestMeasureNode_ContextIsZeroValue(t *testing.T) {
+	RegisterIntrinsic("measureCtxTest", &IntrinsicHandler{
+		Measure: func(node gox.VNode, ctx LayoutContext) (int, int) {
+			return ctx.Width + 1, ctx.Height + 1
+		},
+	})
+	if w, h := MeasureNode(gox.Element("measureCtxTest", nil)); w != 1 || h != 1 {
+		t.Errorf("expected a zero context, got %dx%d", w-1, h-1)
+	}
+}
@@ -27,6 +27,17 @@ type LinkOptions struct {
 	OnClick func()
 	// DisableFocus disables focus management registration.
 	DisableFocus bool
+	// OnFocus is called when the link gains focus.
+	OnFocus func()
+	// OnBlur is called when the link loses focus.
+	OnBlur func()
+	// ShortcutKey is the key sequence (e.g. CtrlO) that activates the link.
+	// Show it next to the link with the element's "shortcut" prop.
+	ShortcutKey string
+	// HandleShortcut activates the link when ShortcutKey is pressed anywhere
+	// in the app, via a global key handler. Otherwise it only works while
+	// the link is focused.
+	HandleShortcut bool
 }
 
 // Link represents a clickable hyperlink component.
@@ -36,8 +47,13 @@ type Link struct {
 
 	url            string
 	onClick        func()
+	onFocus        func()
+	onBlur         func()
+	shortcutKey    string
 	shouldRegister bool
 	registered     bool
+
+	cleanupShortcut func()
 }
 
 // NewLink creates a new link.
@@ -54,6 +70,9 @@ func NewLink(opts LinkOptions) *Link {
 		setFocused:     setFocused,
 		url:            opts.URL,
 		onClick:        opts.OnClick,
+		onFocus:        opts.OnFocus,
+		onBlur:         opts.OnBlur,
+		shortcutKey:    opts.ShortcutKey,
 		shouldRegister: shouldRegister,
 	}
 
@@ -62,6 +81,16 @@ func NewLink(opts LinkOptions) *Link {
 		l.registered = true
 	}
 
+	if opts.HandleShortcut && opts.ShortcutKey != "" {
+		l.cleanupShortcut = Manager().SetGlobalKeyHandler(func(key string) bool {
+			if key != l.shortcutKey {
+				return false
+			}
+			l.Activate()
+			return true
+		})
+	}
+
 	return l
 }
 
@@ -92,7 +121,9 @@ func (l *Link) Blur() {
 
 // SetFocused sets the focused state (called by focus manager).
 func (l *Link) SetFocused(f bool) {
+	wasFocused := Untrack(func() bool { return l.focused() })
 	l.setFocused(f)
+	notifyFocusChange(wasFocused, f, l.onFocus, l.onBlur)
 }
 
 // Dispose unregisters from the focus manager.
@@ -101,6 +132,10 @@ func (l *Link) Dispose() {
 		Unregister(l)
 		l.registered = false
 	}
+	if l.cleanupShortcut != nil {
+		l.cleanupShortcut()
+		l.cleanupShortcut = nil
+	}
 }
 
 // HandleKey processes a key press.
@@ -117,6 +152,11 @@ func (l *Link) HandleKey(key string) bool {
 		return true
 	}
 
+	if l.shortcutKey != "" && key == l.shortcutKey {
+		l.Activate()
+		return true
+	}
+
 	return false
 }
 
@@ -152,7 +192,7 @@ func OpenURL(url string) error {
 
 // Link measure/layout/render functions
 
-func measureLink(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureLink(node gox.VNode, ctx LayoutContext) (int, int) {
 	// Measure text content
 	text := CollectTextContent(node)
 	lines := splitLines(text)
@@ -164,11 +204,18 @@ func measureLink(node gox.VNode, ctx *LayoutContext) (int, int) {
 		}
 	}
 
+	// Leave two spaces between the text and the shortcut
+	if shortcut := GetStringProp(node.Props, "shortcut", ""); shortcut != "" {
+		width += RuneWidth(shortcut) + 2
+	}
+
 	return width, len(lines)
 }
 
 func layoutLink(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
-	w, h := measureLink(node, ctx)
+	w, h := measureLink(node, *ctx)
+	// An explicit width leaves room to right-align the shortcut
+	w = max(w, GetIntProp(node.Props, "width", -1))
 
 	return &LayoutBox{
 		X:           ctx.X,
@@ -201,6 +248,17 @@ func splitLines(s string) []string {
 	return lines
 }
 
+// linkShortcut returns the shortcut text of a link and the cell where it
+// starts, right-aligned on the first line of the box.
+func linkShortcut(box *LayoutBox) (text string, x int, style Style) {
+	text = GetStringProp(box.Node.Props, "shortcut", "")
+	if text == "" {
+		return "", 0, Style{}
+	}
+	style = getStyleProp(box.Node.Props, "shortcutStyle", Style{Dim: true})
+	return text, box.X + box.Width - RuneWidth(text), style
+}
+
 // RenderLinkToBuffer renders a link to a CellBuffer.
 // Links use OSC 8 escape sequences for terminal hyperlinks.
 func RenderLinkToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
@@ -252,6 +310,14 @@ func RenderLinkToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 			charX += runewidth.RuneWidth(char)
 		}
 	}
+
+	shortcut, charX, shortcutStyle := linkShortcut(box)
+	for _, char := range shortcut {
+		if IsInClip(charX, y, clip) {
+			buf.Set(charX, y, New(char, shortcutStyle))
+		}
+		charX += runewidth.RuneWidth(char)
+	}
 }
 
 // RenderLinkToLogicalBuffer renders a link to a LogicalBuffer.
@@ -304,4 +370,12 @@ func RenderLinkToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipReg
 			charX += runewidth.RuneWidth(char)
 		}
 	}
+
+	shortcut, charX, shortcutStyle := linkShortcut(box)
+	for _, char := range shortcut {
+		if IsInClip(charX, y, clip) {
+			buf.Set(charX, y, New(char, shortcutStyle))
+		}
+		charX += runewidth.RuneWidth(char)
+	}
 }
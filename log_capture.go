@@ -1,6 +1,7 @@
 package goli
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -18,6 +19,21 @@ const (
 	LogLevelError LogLevel = "ERROR"
 )
 
+// severity orders levels from least to most severe. Unknown levels rank
+// with debug.
+func (l LogLevel) severity() int {
+	switch l {
+	case LogLevelInfo:
+		return 1
+	case LogLevelWarn:
+		return 2
+	case LogLevelError:
+		return 3
+	default:
+		return 0
+	}
+}
+
 // LogMessage represents a captured log message
 type LogMessage struct {
 	Timestamp time.Time
@@ -30,6 +46,7 @@ type LogCapture struct {
 	messages    Accessor[[]LogMessage]
 	setMessages Setter[[]LogMessage]
 	maxMessages int
+	filter      func(LogMessage) bool // Drops messages it returns false for
 	mu          sync.Mutex
 
 	// Original stdout/stderr for restoration
@@ -42,8 +59,8 @@ type LogCapture struct {
 	stderrReader *os.File
 	stderrWriter *os.File
 
-	// Stop channel
-	stopCh chan struct{}
+	// Pipe readers, waited for by Stop
+	readers sync.WaitGroup
 }
 
 // NewLogCapture creates a new log capture with the specified max message count
@@ -88,49 +105,39 @@ func (lc *LogCapture) Start() error {
 	os.Stdout = lc.stdoutWriter
 	os.Stderr = lc.stderrWriter
 
-	// Start reading from pipes
-	lc.stopCh = make(chan struct{})
-
-	go lc.readPipe(lc.stdoutReader, LogLevelInfo)
-	go lc.readPipe(lc.stderrReader, LogLevelError)
+	// Start reading from pipes. Stop closes the writers, which ends each
+	// reader with io.EOF once it has drained the pipe.
+	lc.readers.Add(2)
+	go lc.readPipe(lc.stdoutReader, LogLevelInfo, lc.origStderr)
+	go lc.readPipe(lc.stderrReader, LogLevelError, lc.origStderr)
 
 	return nil
 }
 
-// readPipe reads from a pipe and adds messages to the capture
-func (lc *LogCapture) readPipe(reader *os.File, level LogLevel) {
+// readPipe reads from a pipe and adds messages to the capture until the
+// pipe is closed. Read errors are reported to errOut.
+func (lc *LogCapture) readPipe(reader *os.File, level LogLevel, errOut io.Writer) {
+	defer lc.readers.Done()
 	buf := make([]byte, 4096)
 	for {
-		select {
-		case <-lc.stopCh:
-			return
-		default:
-			n, err := reader.Read(buf)
-			if err != nil {
-				if err != io.EOF {
-					// Log read error to original stderr if available
-					if lc.origStderr != nil {
-						fmt.Fprintf(lc.origStderr, "LogCapture read error: %v\n", err)
-					}
-				}
-				return
-			}
-			if n > 0 {
-				lc.addMessage(level, string(buf[:n]))
+		n, err := reader.Read(buf)
+		if n > 0 {
+			lc.addMessage(level, string(buf[:n]))
+		}
+		if err != nil {
+			// Stop closes the read end if the pipe doesn't drain in time
+			if err != io.EOF && !errors.Is(err, os.ErrClosed) {
+				fmt.Fprintf(errOut, "LogCapture read error: %v\n", err)
 			}
+			return
 		}
 	}
 }
 
-// Stop stops capturing and restores original stdout/stderr
+// Stop stops capturing and restores original stdout/stderr. Output written
+// before Stop is still captured.
 func (lc *LogCapture) Stop() {
 	lc.mu.Lock()
-	defer lc.mu.Unlock()
-
-	if lc.stopCh != nil {
-		close(lc.stopCh)
-		lc.stopCh = nil
-	}
 
 	// Restore original stdout/stderr
 	if lc.origStdout != nil {
@@ -142,25 +149,40 @@ func (lc *LogCapture) Stop() {
 		lc.origStderr = nil
 	}
 
-	// Close pipes
-	if lc.stdoutWriter != nil {
-		lc.stdoutWriter.Close()
-		lc.stdoutWriter = nil
-	}
-	if lc.stdoutReader != nil {
-		lc.stdoutReader.Close()
-		lc.stdoutReader = nil
+	pipes := []*os.File{lc.stdoutWriter, lc.stderrWriter, lc.stdoutReader, lc.stderrReader}
+	lc.stdoutWriter, lc.stderrWriter, lc.stdoutReader, lc.stderrReader = nil, nil, nil, nil
+	lc.mu.Unlock()
+
+	// Closing the writers ends the readers; wait for them outside mu, which
+	// addMessage takes, before closing the read ends. A copy of a write end
+	// held elsewhere (e.g. inherited by a child process) keeps a reader from
+	// seeing EOF, so after a timeout the read ends are closed under it.
+	for _, f := range pipes[:2] {
+		if f != nil {
+			f.Close()
+		}
 	}
-	if lc.stderrWriter != nil {
-		lc.stderrWriter.Close()
-		lc.stderrWriter = nil
+	drained := make(chan struct{})
+	go func() {
+		lc.readers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(logCaptureDrainTimeout):
 	}
-	if lc.stderrReader != nil {
-		lc.stderrReader.Close()
-		lc.stderrReader = nil
+	for _, f := range pipes[2:] {
+		if f != nil {
+			f.Close()
+		}
 	}
+	<-drained
 }
 
+// logCaptureDrainTimeout bounds how long Stop waits for output written
+// before it to be read.
+const logCaptureDrainTimeout = 200 * time.Millisecond
+
 // addMessage adds a message to the capture
 func (lc *LogCapture) addMessage(level LogLevel, message string) {
 	msg := LogMessage{
@@ -169,6 +191,13 @@ func (lc *LogCapture) addMessage(level LogLevel, message string) {
 		Message:   message,
 	}
 
+	lc.mu.Lock()
+	filter := lc.filter
+	lc.mu.Unlock()
+	if filter != nil && !filter(msg) {
+		return
+	}
+
 	SetWith(lc.setMessages, func(prev []LogMessage) []LogMessage {
 		next := append(prev, msg)
 		if len(next) > lc.maxMessages {
@@ -219,6 +248,30 @@ func (lc *LogCapture) LastMessages(n int) []LogMessage {
 	return msgs[len(msgs)-n:]
 }
 
+// Snapshot returns a copy of the current messages that later additions
+// don't affect. It doesn't track the messages signal.
+func (lc *LogCapture) Snapshot() []LogMessage {
+	msgs := Untrack(lc.messages)
+	return append([]LogMessage(nil), msgs...)
+}
+
+// SetFilter drops subsequent messages for which fn returns false.
+// A nil fn captures everything.
+func (lc *LogCapture) SetFilter(fn func(LogMessage) bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.filter = fn
+}
+
+// SetMinLevel drops subsequent messages less severe than level.
+// It replaces any filter set with SetFilter.
+func (lc *LogCapture) SetMinLevel(level LogLevel) {
+	minSeverity := level.severity()
+	lc.SetFilter(func(msg LogMessage) bool {
+		return msg.Level.severity() >= minSeverity
+	})
+}
+
 // Clear clears all captured messages
 func (lc *LogCapture) Clear() {
 	lc.setMessages([]LogMessage{})
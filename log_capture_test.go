@@ -0,0 +1,113 @@
+package goli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLogCapture_SetMinLevel(t *testing.T) {
+	Reset()
+	lc := NewLogCapture(10)
+	lc.SetMinLevel(LogLevelWarn)
+
+	lc.Debug("debug")
+	lc.Info("info")
+	lc.Warn("warn")
+	lc.Error("error")
+
+	msgs := lc.Messages()
+	if len(msgs) != 2 || msgs[0].Message != "warn" || msgs[1].Message != "error" {
+		t.Errorf("expected [warn error], got %+v", msgs)
+	}
+}
+
+func TestLogCapture_SetFilter(t *testing.T) {
+	Reset()
+	lc := NewLogCapture(10)
+	lc.SetFilter(func(msg LogMessage) bool {
+		return !strings.HasPrefix(msg.Message, "noise")
+	})
+
+	lc.Info("noise: tick")
+	lc.Info("saved")
+
+	if msgs := lc.Messages(); len(msgs) != 1 || msgs[0].Message != "saved" {
+		t.Errorf("expected [saved], got %+v", msgs)
+	}
+
+	lc.SetFilter(nil)
+	lc.Info("noise: tock")
+	if n := len(lc.Messages()); n != 2 {
+		t.Errorf("expected 2 messages after clearing filter, got %d", n)
+	}
+}
+
+func TestLogCapture_SnapshotIsIndependent(t *testing.T) {
+	Reset()
+	lc := NewLogCapture(10)
+	lc.Info("one")
+
+	snap := lc.Snapshot()
+	lc.Info("two")
+
+	if len(snap) != 1 || snap[0].Message != "one" {
+		t.Errorf("expected snapshot [one], got %+v", snap)
+	}
+	if n := len(lc.Messages()); n != 2 {
+		t.Errorf("expected 2 messages, got %d", n)
+	}
+}
+
+func TestLogCapture_StartStopRepeatedly(t *testing.T) {
+	Reset()
+	before := runtime.NumGoroutine()
+	lc := NewLogCapture(100)
+
+	for i := 0; i < 10; i++ {
+		if err := lc.Start(); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(os.Stdout, "out %d", i)
+		fmt.Fprintf(os.Stderr, "err %d", i)
+		lc.Stop()
+	}
+
+	if got := len(lc.Messages()); got != 20 {
+		t.Errorf("expected 20 captured messages, got %d", got)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected pipe readers to exit on Stop, goroutines went from %d to %d", before, after)
+	}
+}
+
+func TestLogCapture_StopWithWriterHeldElsewhere(t *testing.T) {
+	Reset()
+	lc := NewLogCapture(10)
+	if err := lc.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A duplicate of the write end, like one inherited by a child process,
+	// keeps the pipe from reaching EOF
+	fd, err := syscall.Dup(int(os.Stdout.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(fd)
+
+	done := make(chan struct{})
+	go func() {
+		lc.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Stop to return while a copy of the writer is open")
+	}
+}
@@ -1,6 +1,7 @@
 package goli
 
 import (
+	"reflect"
 	"sync"
 	"sync/atomic"
 
@@ -86,9 +87,188 @@ func Memo[K comparable, P Keyed[K]](
 	}
 }
 
-// BeginRender increments the generation counter. Call at start of each render.
+// memoComponentIDs numbers MemoComponents, so instances of different
+// components at the same position don't share a cache entry.
+var memoComponentIDs atomic.Uint64
+
+// memoSlot identifies a child among its siblings: by its "key" prop when
+// set, otherwise by its position.
+type memoSlot struct {
+	key   any
+	index int
+}
+
+// slotOf returns the slot of v, the index-th child of its parent.
+func slotOf(v gox.VNode, index int) memoSlot {
+	if key, ok := v.Props["key"]; ok && isComparable(key) {
+		return memoSlot{key: key, index: -1}
+	}
+	return memoSlot{index: index}
+}
+
+// memoKey identifies a MemoComponent instance within its parent's scope.
+type memoKey struct {
+	component uint64
+	slot      memoSlot
+}
+
+// memoScope holds the MemoComponent cache of one parent element: entries
+// for its memoized children, and scopes for the children that have memoized
+// descendants. Scopes and entries not seen in the previous render are
+// dropped by BeginRender.
+type memoScope struct {
+	children   map[memoSlot]*memoScope
+	entries    map[memoKey]*memoEntry[gox.Props]
+	generation int64
+}
+
+// memoScopeFor returns the scope of the element at path, creating it if
+// needed and marking the scopes along the way as seen in gen. Callers hold
+// rt.mu.
+func (rt *Runtime) memoScopeFor(path []memoSlot, gen int64) *memoScope {
+	if rt.memoRoot == nil {
+		rt.memoRoot = &memoScope{}
+	}
+	scope := rt.memoRoot
+	scope.generation = gen
+	for _, slot := range path {
+		child := scope.children[slot]
+		if child == nil {
+			if scope.children == nil {
+				scope.children = make(map[memoSlot]*memoScope)
+			}
+			child = &memoScope{}
+			scope.children[slot] = child
+		}
+		child.generation = gen
+		scope = child
+	}
+	return scope
+}
+
+// sweepMemo drops MemoComponent scopes and entries not seen since the
+// render before gen.
+func (rt *Runtime) sweepMemo(gen int64) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.memoRoot != nil {
+		rt.memoRoot.sweep(gen - 1)
+	}
+}
+
+func (s *memoScope) sweep(oldest int64) {
+	for key, e := range s.entries {
+		if e.generation < oldest {
+			delete(s.entries, key)
+		}
+	}
+	for slot, child := range s.children {
+		if child.generation < oldest {
+			delete(s.children, slot)
+			continue
+		}
+		child.sweep(oldest)
+	}
+}
+
+// MemoComponent memoizes a gox.Component used as an element type. Each
+// instance is cached within its parent element under its "key" prop, or its
+// position among its siblings when it has none, and re-renders only when
+// its props change. Give list items a stable key so reordering the list
+// reuses their output. equal compares props; nil uses ShallowPropsEqual.
+//
+// Instances are only cached while Expand renders them; called directly, the
+// component always renders.
+//
+// Usage:
+//
+//	var Row = goli.MemoComponent(func(props gox.Props) gox.VNode {
+//	    return <text>{props["label"].(string)}</text>
+//	}, nil)
+//
+//	rows := gox.Map(items, func(item Item) gox.VNode {
+//	    return gox.Element(Row, gox.Props{"key": item.ID, "label": item.Label})
+//	})
+func MemoComponent(render gox.Component, equal func(a, b gox.Props) bool) gox.Component {
+	if equal == nil {
+		equal = ShallowPropsEqual
+	}
+	id := memoComponentIDs.Add(1)
+
+	return func(props gox.Props) gox.VNode {
+		rt := Global
+		gen := memoGeneration.Load()
+
+		rt.mu.Lock()
+		ex := rt.expander
+		if ex == nil || len(ex.path) == 0 {
+			rt.mu.Unlock()
+			return render(props)
+		}
+		path := ex.path
+		scope := rt.memoScopeFor(path[:len(path)-1], gen)
+		key := memoKey{component: id, slot: path[len(path)-1]}
+		e := scope.entries[key]
+		if e != nil && e.generation >= gen-1 && equal(e.props, props) {
+			e.generation = gen
+			rt.mu.Unlock()
+			return e.result
+		}
+		rt.mu.Unlock()
+
+		result := render(props)
+
+		rt.mu.Lock()
+		if scope.entries == nil {
+			scope.entries = make(map[memoKey]*memoEntry[gox.Props])
+		}
+		scope.entries[key] = &memoEntry[gox.Props]{
+			props:      props,
+			result:     result,
+			generation: gen,
+		}
+		rt.mu.Unlock()
+
+		return result
+	}
+}
+
+// ShallowPropsEqual reports whether a and b have the same keys and values
+// compare ==. Children are equal only when both are empty, and values that
+// can't be compared with == are treated as changed.
+func ShallowPropsEqual(a, b gox.Props) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok {
+			return false
+		}
+		if k == "children" {
+			ac, _ := av.([]gox.VNode)
+			bc, _ := bv.([]gox.VNode)
+			if len(ac) > 0 || len(bc) > 0 {
+				return false
+			}
+			continue
+		}
+		if !valuesEqual(av, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+// isComparable reports whether v can be compared with == without panicking.
+func isComparable(v any) bool {
+	return v == nil || reflect.TypeOf(v).Comparable()
+}
+
+// BeginRender increments the generation counter and drops MemoComponent
+// cache entries not used in the previous render. Call at start of each render.
 func BeginRender() {
-	memoGeneration.Add(1)
+	Global.sweepMemo(memoGeneration.Add(1))
 }
 
 // MemoStats returns cache statistics (for debugging/benchmarking).
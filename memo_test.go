@@ -0,0 +1,144 @@
+package goli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/germtb/gox"
+)
+
+func renderList(row gox.Component, ids []string, keyed bool) {
+	BeginRender()
+	items := make([]gox.VNode, len(ids))
+	for i, id := range ids {
+		props := gox.Props{"label": "item " + id}
+		if keyed {
+			props["key"] = id
+		}
+		items[i] = gox.Element(row, props)
+	}
+	Expand(gox.Element("box", nil, items...))
+}
+
+func countingRow(renders *int) gox.Component {
+	return MemoComponent(func(props gox.Props) gox.VNode {
+		*renders++
+		return gox.Element("text", nil, gox.Text(props["label"].(string)))
+	}, nil)
+}
+
+func TestMemoComponent_KeyedChildrenSurviveReorder(t *testing.T) {
+	renders := 0
+	row := countingRow(&renders)
+
+	ids := []string{"a", "b", "c", "d"}
+	renderList(row, ids, true)
+	if renders != 4 {
+		t.Fatalf("expected 4 initial renders, got %d", renders)
+	}
+
+	reversed := []string{"d", "c", "b", "a"}
+	renderList(row, reversed, true)
+	if renders != 4 {
+		t.Errorf("expected keyed rows to hit the cache after reversing, got %d renders", renders)
+	}
+}
+
+func TestMemoComponent_UnkeyedChildrenMatchByPosition(t *testing.T) {
+	renders := 0
+	row := countingRow(&renders)
+
+	renderList(row, []string{"a", "b", "c", "d"}, false)
+	renderList(row, []string{"a", "b", "c", "d"}, false)
+	if renders != 4 {
+		t.Errorf("expected unchanged rows to hit the cache, got %d renders", renders)
+	}
+
+	renderList(row, []string{"d", "c", "b", "a"}, false)
+	if renders != 8 {
+		t.Errorf("expected every position to re-render after reversing, got %d renders", renders)
+	}
+}
+
+func TestMemoComponent_RerendersOnPropChange(t *testing.T) {
+	renders := 0
+	row := countingRow(&renders)
+
+	renderList(row, []string{"a"}, true)
+	BeginRender()
+	Expand(gox.Element("box", nil, gox.Element(row, gox.Props{"key": "a", "label": "renamed"})))
+	if renders != 2 {
+		t.Errorf("expected a re-render for changed props, got %d renders", renders)
+	}
+}
+
+func TestMemoComponent_ScopedToParent(t *testing.T) {
+	Reset()
+	renders := 0
+	row := countingRow(&renders)
+
+	// Two lists with the same keys and unkeyed rows at the same positions
+	lists := func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("box", nil,
+				gox.Element(row, gox.Props{"key": "a", "label": "left a"}),
+				gox.Element(row, gox.Props{"label": "left 1"}),
+			),
+			gox.Element("box", nil,
+				gox.Element(row, gox.Props{"key": "a", "label": "right a"}),
+				gox.Element(row, gox.Props{"label": "right 1"}),
+			),
+		)
+	}
+	BeginRender()
+	Expand(lists())
+	BeginRender()
+	Expand(lists())
+	if renders != 4 {
+		t.Errorf("expected rows in different parents to keep their own entries, got %d renders", renders)
+	}
+}
+
+func memoEntryCount(s *memoScope) int {
+	if s == nil {
+		return 0
+	}
+	n := len(s.entries)
+	for _, child := range s.children {
+		n += memoEntryCount(child)
+	}
+	return n
+}
+
+func TestMemoComponent_DropsUnusedEntries(t *testing.T) {
+	Reset()
+	renders := 0
+	row := countingRow(&renders)
+
+	for i := 0; i < 50; i++ {
+		renderList(row, []string{fmt.Sprint("id", i)}, true)
+	}
+	if n := memoEntryCount(Global.memoRoot); n > 2 {
+		t.Errorf("expected entries for old keys to be dropped, cache holds %d", n)
+	}
+}
+
+func TestShallowPropsEqual(t *testing.T) {
+	child := gox.Text("x")
+	tests := []struct {
+		a, b gox.Props
+		want bool
+	}{
+		{gox.Props{"n": 1}, gox.Props{"n": 1}, true},
+		{gox.Props{"n": 1}, gox.Props{"n": 2}, false},
+		{gox.Props{"n": 1}, gox.Props{"m": 1}, false},
+		{gox.Props{"s": []int{1}}, gox.Props{"s": []int{1}}, false},
+		{gox.Props{"children": []gox.VNode{}}, gox.Props{"children": []gox.VNode(nil)}, true},
+		{gox.Props{"children": []gox.VNode{child}}, gox.Props{"children": []gox.VNode{child}}, false},
+	}
+	for i, tt := range tests {
+		if got := ShallowPropsEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("case %d: expected %v, got %v", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package goli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/germtb/gox"
+)
+
+// mountTracker calls IntrinsicHandler.OnMount and OnUnmount as elements
+// enter and leave the layout tree between renders.
+//
+// VNodes are rebuilt on every render, so elements are identified by their
+// path in the tree: each segment is the element type plus its "key" prop,
+// or its index among siblings when it has no key.
+type mountTracker struct {
+	mounted map[string]gox.VNode
+}
+
+// update diffs the elements with lifecycle hooks in root against the
+// previous call, calling OnUnmount for removed elements, then OnMount for
+// new ones. Inside a renderer's frame the hooks run once it is drawn.
+func (t *mountTracker) update(root *LayoutBox) {
+	if !lifecycleHooks.Load() {
+		return
+	}
+
+	live := make(map[string]gox.VNode)
+	var walk func(box *LayoutBox, parent string, index int)
+	walk = func(box *LayoutBox, parent string, index int) {
+		if box == nil {
+			return
+		}
+		id := parent + "/" + mountSegment(box.Node, index)
+		if name, ok := box.Node.Type.(string); ok {
+			if h := GetIntrinsicHandler(name); h != nil && (h.OnMount != nil || h.OnUnmount != nil) {
+				live[id] = box.Node
+			}
+		}
+		for i, child := range box.Children {
+			walk(child, id, i)
+		}
+	}
+	walk(root, "", 0)
+
+	for id, node := range t.mounted {
+		if _, ok := live[id]; !ok {
+			unmountNode(node)
+		}
+	}
+	for id, node := range live {
+		if _, ok := t.mounted[id]; !ok {
+			if h := GetIntrinsicHandler(node.Type.(string)); h != nil && h.OnMount != nil {
+				Global.afterLayout(func() { h.OnMount(node) })
+			}
+		}
+	}
+	t.mounted = live
+}
+
+// unmountAll calls OnUnmount for every mounted element, for when no later
+// frame will remove them (the app is disposed).
+func (t *mountTracker) unmountAll() {
+	mounted := t.mounted
+	t.mounted = nil
+	for _, node := range mounted {
+		unmountNode(node)
+	}
+}
+
+// unmountNode calls the OnUnmount hook of node's intrinsic, if any.
+func unmountNode(node gox.VNode) {
+	if h := GetIntrinsicHandler(node.Type.(string)); h != nil && h.OnUnmount != nil {
+		Global.afterLayout(func() { h.OnUnmount(node) })
+	}
+}
+
+// mountSegment returns the path segment identifying node among its siblings.
+func mountSegment(node gox.VNode, index int) string {
+	name := fmt.Sprint(node.Type)
+	if key, ok := node.Props["key"]; ok && key != nil {
+		return name + ":" + fmt.Sprint(key)
+	}
+	return name + "#" + strconv.Itoa(index)
+}
@@ -0,0 +1,142 @@
+package goli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+// registerMountProbe registers a container intrinsic that records its
+// lifecycle hook calls by "id" prop.
+func registerMountProbe(t *testing.T, name string) (mounted, unmounted *[]string) {
+	t.Helper()
+	mounted, unmounted = &[]string{}, &[]string{}
+	RegisterIntrinsic(name, &IntrinsicHandler{
+		OnMount: func(node gox.VNode) {
+			*mounted = append(*mounted, GetStringProp(node.Props, "id", ""))
+		},
+		OnUnmount: func(node gox.VNode) {
+			*unmounted = append(*unmounted, GetStringProp(node.Props, "id", ""))
+		},
+	})
+	return mounted, unmounted
+}
+
+func TestIntrinsicHandler_MountAndUnmount(t *testing.T) {
+	Reset()
+	mounted, unmounted := registerMountProbe(t, "mountprobe")
+
+	show, setShow := CreateSignal(true)
+	app := Render(func() gox.VNode {
+		children := []gox.VNode{gox.Element("text", nil, gox.Text("static"))}
+		if show() {
+			children = append(children, gox.Element("mountprobe", gox.Props{"id": "probe"}))
+		}
+		return gox.Element("box", nil, children...)
+	}, Options{Width: 10, Height: 2, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	if len(*mounted) != 1 || (*mounted)[0] != "probe" {
+		t.Fatalf("expected OnMount for probe on first render, got %v", *mounted)
+	}
+
+	app.Rerender()
+	if len(*mounted) != 1 || len(*unmounted) != 0 {
+		t.Errorf("expected no hooks on an unchanged rerender, got mounted=%v unmounted=%v", *mounted, *unmounted)
+	}
+
+	setShow(false)
+	app.Rerender()
+	if len(*unmounted) != 1 || (*unmounted)[0] != "probe" {
+		t.Errorf("expected OnUnmount for probe after removal, got %v", *unmounted)
+	}
+}
+
+func TestIntrinsicHandler_KeyedElementStaysMounted(t *testing.T) {
+	Reset()
+	mounted, unmounted := registerMountProbe(t, "mountprobekeyed")
+
+	prepend, setPrepend := CreateSignal(false)
+	app := Render(func() gox.VNode {
+		var children []gox.VNode
+		if prepend() {
+			children = append(children, gox.Element("text", nil, gox.Text("new")))
+		}
+		children = append(children, gox.Element("mountprobekeyed", gox.Props{"id": "probe", "key": "probe"}))
+		return gox.Element("box", gox.Props{"direction": "column"}, children...)
+	}, Options{Width: 10, Height: 2, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	setPrepend(true)
+	app.Rerender()
+	if len(*mounted) != 1 || len(*unmounted) != 0 {
+		t.Errorf("expected keyed element to stay mounted when shifted, got mounted=%v unmounted=%v", *mounted, *unmounted)
+	}
+}
+
+func TestIntrinsicHandler_OnMountSetsSignalTheAppReads(t *testing.T) {
+	Reset()
+	status, setStatus := CreateSignal("pending")
+	RegisterIntrinsic("mountsetter", &IntrinsicHandler{
+		OnMount: func(gox.VNode) { setStatus("mounted") },
+	})
+
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("text", nil, gox.Text(status())),
+			gox.Element("mountsetter", nil),
+		)
+	}, Options{Width: 10, Height: 2, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	app.Headless().AssertContains(t, "mounted")
+}
+
+func TestIntrinsicHandler_UnmountOnDispose(t *testing.T) {
+	Reset()
+	mounted, unmounted := registerMountProbe(t, "mountprobedispose")
+
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("mountprobedispose", gox.Props{"id": "a"}),
+			gox.Element("mountprobedispose", gox.Props{"id": "b"}),
+		)
+	}, Options{Width: 10, Height: 2, Headless: true, DisableThrottle: true})
+
+	if len(*mounted) != 2 {
+		t.Fatalf("expected both probes mounted, got %v", *mounted)
+	}
+	app.Dispose()
+	if len(*unmounted) != 2 {
+		t.Errorf("expected OnUnmount for both probes on Dispose, got %v", *unmounted)
+	}
+	app.Dispose()
+	if len(*unmounted) != 2 {
+		t.Errorf("expected no more hooks on a second Dispose, got %v", *unmounted)
+	}
+}
+
+func TestIntrinsicHandler_UnmountOnPipelineStop(t *testing.T) {
+	Reset()
+	unmounted := make(chan string, 1)
+	RegisterIntrinsic("mountprobepipeline", &IntrinsicHandler{
+		OnUnmount: func(node gox.VNode) { unmounted <- GetStringProp(node.Props, "id", "") },
+	})
+
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: &syncBuffer{}})
+	p.Render(gox.Element("mountprobepipeline", gox.Props{"id": "p"}))
+	if err := p.Flush(time.Second); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	p.Stop()
+
+	select {
+	case id := <-unmounted:
+		if id != "p" {
+			t.Errorf("expected OnUnmount for p, got %q", id)
+		}
+	default:
+		t.Error("expected OnUnmount to run before Stop returned")
+	}
+}
@@ -0,0 +1,152 @@
+package goli
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MouseTrackingMode selects which mouse events the terminal reports.
+type MouseTrackingMode int
+
+const (
+	// MouseDisabled reports no mouse events (default).
+	MouseDisabled MouseTrackingMode = iota
+	// MouseClick reports button presses, releases and wheel scrolls.
+	MouseClick
+	// MouseMotion additionally reports pointer movement.
+	MouseMotion
+)
+
+// MouseButton identifies the button of a mouse event.
+type MouseButton int
+
+const (
+	MouseLeft MouseButton = iota
+	MouseMiddle
+	MouseRight
+	MouseNoButton // Motion without a button held
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// MouseAction is the kind of mouse event.
+type MouseAction int
+
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	MouseMove
+)
+
+// MouseEvent is a decoded mouse report. X and Y are 0-based cell coordinates.
+type MouseEvent struct {
+	X, Y   int
+	Button MouseButton
+	Action MouseAction
+	Shift  bool
+	Alt    bool
+	Ctrl   bool
+}
+
+// MouseHandler is implemented by focusables that want mouse events
+// delivered by FocusManager.HandleMouse.
+type MouseHandler interface {
+	HandleMouse(ev MouseEvent) bool
+}
+
+// EnableMouse writes the escape sequences that turn on mouse reporting in
+// SGR (1006) encoding. MouseDisabled writes nothing.
+func EnableMouse(w io.Writer, mode MouseTrackingMode) {
+	switch mode {
+	case MouseClick:
+		io.WriteString(w, CSI+"?1000h"+CSI+"?1006h")
+	case MouseMotion:
+		io.WriteString(w, CSI+"?1003h"+CSI+"?1006h")
+	}
+}
+
+// DisableMouse writes the escape sequences that turn off mouse reporting.
+func DisableMouse(w io.Writer) {
+	io.WriteString(w, CSI+"?1003l"+CSI+"?1000l"+CSI+"?1006l")
+}
+
+// ParseMouseEvent decodes a single SGR mouse report ("\x1b[<b;x;yM" for a
+// press or motion, "...m" for a release). Returns false if s is not exactly
+// one mouse report.
+func ParseMouseEvent(s string) (MouseEvent, bool) {
+	ev, n, ok := parseMouseSGR(s)
+	if !ok || n != len(s) {
+		return MouseEvent{}, false
+	}
+	return ev, true
+}
+
+// splitMouseEvents decodes s as a run of one or more SGR mouse reports, as
+// delivered by a single read while the pointer moves.
+func splitMouseEvents(s string) ([]MouseEvent, bool) {
+	var events []MouseEvent
+	for len(s) > 0 {
+		ev, n, ok := parseMouseSGR(s)
+		if !ok {
+			return nil, false
+		}
+		events = append(events, ev)
+		s = s[n:]
+	}
+	return events, len(events) > 0
+}
+
+// parseMouseSGR decodes the SGR mouse report at the start of s and returns
+// the number of bytes it occupies.
+func parseMouseSGR(s string) (MouseEvent, int, bool) {
+	const prefix = "\x1b[<"
+	if !strings.HasPrefix(s, prefix) {
+		return MouseEvent{}, 0, false
+	}
+	end := strings.IndexAny(s, "Mm")
+	if end < 0 {
+		return MouseEvent{}, 0, false
+	}
+	fields := strings.Split(s[len(prefix):end], ";")
+	if len(fields) != 3 {
+		return MouseEvent{}, 0, false
+	}
+	var nums [3]int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return MouseEvent{}, 0, false
+		}
+		nums[i] = n
+	}
+
+	code := nums[0]
+	ev := MouseEvent{
+		X:     max(nums[1]-1, 0),
+		Y:     max(nums[2]-1, 0),
+		Shift: code&4 != 0,
+		Alt:   code&8 != 0,
+		Ctrl:  code&16 != 0,
+	}
+
+	switch {
+	case code&64 != 0:
+		ev.Button = MouseWheelUp + MouseButton(code&1)
+	case code&3 == 3:
+		ev.Button = MouseNoButton
+	default:
+		ev.Button = MouseButton(code & 3)
+	}
+
+	switch {
+	case code&32 != 0:
+		ev.Action = MouseMove
+	case s[end] == 'm':
+		ev.Action = MouseRelease
+	default:
+		ev.Action = MousePress
+	}
+
+	return ev, end + 1, true
+}
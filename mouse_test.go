@@ -0,0 +1,129 @@
+package goli
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+func TestParseMouseEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  MouseEvent
+	}{
+		{"left press", "\x1b[<0;5;3M", MouseEvent{X: 4, Y: 2, Button: MouseLeft, Action: MousePress}},
+		{"left release", "\x1b[<0;5;3m", MouseEvent{X: 4, Y: 2, Button: MouseLeft, Action: MouseRelease}},
+		{"right press", "\x1b[<2;1;1M", MouseEvent{X: 0, Y: 0, Button: MouseRight, Action: MousePress}},
+		{"wheel down", "\x1b[<65;10;10M", MouseEvent{X: 9, Y: 9, Button: MouseWheelDown, Action: MousePress}},
+		{"motion no button", "\x1b[<35;7;2M", MouseEvent{X: 6, Y: 1, Button: MouseNoButton, Action: MouseMove}},
+		{"ctrl click", "\x1b[<16;1;1M", MouseEvent{Button: MouseLeft, Action: MousePress, Ctrl: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseMouseEvent(tt.input)
+			if !ok {
+				t.Fatalf("expected %q to parse", tt.input)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseMouseEvent_RejectsKeys(t *testing.T) {
+	for _, key := range []string{"a", Up, PageDown, "\x1b[<0;5M", "\x1b[<0;5;3M\x1b[<0;5;3m"} {
+		if _, ok := ParseMouseEvent(key); ok {
+			t.Errorf("expected %q not to parse as a single mouse event", key)
+		}
+	}
+}
+
+func TestHandleMouse_ClickFocusesElementUnderPointer(t *testing.T) {
+	setupTest(t)
+
+	top := NewInput(InputOptions{})
+	bottom := NewInput(InputOptions{})
+	defer top.Dispose()
+	defer bottom.Dispose()
+
+	app := Render(func() gox.VNode {
+		return gox.Element("box", gox.Props{"direction": "column"},
+			gox.Element("input", gox.Props{"input": top, "width": 10, "height": 1}),
+			gox.Element("input", gox.Props{"input": bottom, "width": 10, "height": 1}),
+		)
+	}, Options{Width: 20, Height: 2, Output: &strings.Builder{}, DisableThrottle: true})
+	defer app.Dispose()
+
+	if !HandleMouse(MouseEvent{X: 3, Y: 1, Button: MouseLeft, Action: MousePress}) {
+		t.Error("expected click on input to be consumed")
+	}
+	if !bottom.Focused() {
+		t.Error("expected clicked input to be focused")
+	}
+	if HandleMouse(MouseEvent{X: 15, Y: 0, Button: MouseLeft, Action: MousePress}) {
+		t.Error("expected click outside any focusable to be unhandled")
+	}
+}
+
+func TestRun_DispatchesMouseEvents(t *testing.T) {
+	setupTest(t)
+
+	inp := NewInput(InputOptions{})
+	defer inp.Dispose()
+
+	stdin, stdinWriter := io.Pipe()
+	defer stdinWriter.Close()
+	output := newGatedWriter()
+	close(output.gate)
+
+	mounted := make(chan *App, 1)
+	unhandled := make(chan MouseEvent, 1)
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		Run(func() gox.VNode {
+			return gox.Element("input", gox.Props{"input": inp, "width": 10, "height": 1})
+		}, RunOptions{
+			Width:     20,
+			Height:    2,
+			Output:    output,
+			Input:     stdin,
+			MouseMode: MouseClick,
+			OnMount:   func(app *App) { mounted <- app },
+			OnMouse:   func(ev MouseEvent) { unhandled <- ev },
+		})
+	}()
+	app := <-mounted
+
+	// Click on the input focuses it without reaching OnMouse
+	io.WriteString(stdinWriter, "\x1b[<0;2;1M")
+	// Click on empty space falls through to OnMouse
+	io.WriteString(stdinWriter, "\x1b[<0;15;2M")
+
+	select {
+	case ev := <-unhandled:
+		if ev.X != 14 || ev.Y != 1 {
+			t.Errorf("expected unhandled click at (14,1), got (%d,%d)", ev.X, ev.Y)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for OnMouse")
+	}
+	if !inp.Focused() {
+		t.Error("expected input to be focused by click")
+	}
+
+	app.Quit()
+	<-finished
+
+	out := output.String()
+	if !strings.Contains(out, "\x1b[?1000h") || !strings.Contains(out, "\x1b[?1000l") {
+		t.Error("expected mouse reporting to be enabled and disabled")
+	}
+}
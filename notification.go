@@ -0,0 +1,186 @@
+package goli
+
+import (
+	"sync"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+// DismissFunc removes a notification before its timer fires.
+type DismissFunc func()
+
+// Notification is a toast message shown by a NotificationQueue.
+type Notification struct {
+	ID      int
+	Message string
+	Level   LogLevel
+}
+
+// NotificationOpts configures a NotificationQueue.
+type NotificationOpts struct {
+	// MaxVisible caps how many notifications are shown at once; the newest
+	// are kept visible (0 = unlimited).
+	MaxVisible int
+}
+
+// NotificationQueue holds toast notifications that dismiss themselves.
+type NotificationQueue struct {
+	notifications    Accessor[[]Notification]
+	setNotifications Setter[[]Notification]
+	maxVisible       int
+
+	mu      sync.Mutex // Serializes updates from timer goroutines
+	items   []Notification
+	version int // Incremented by every update to items
+	nextID  int
+	timers  map[int]*time.Timer
+}
+
+// NewNotificationQueue creates an empty notification queue.
+//
+// Example:
+//
+//	toasts := goli.NewNotificationQueue(goli.NotificationOpts{MaxVisible: 3})
+//	toasts.Show("Saved", goli.LogLevelInfo, 2*time.Second)
+//
+//	// In the app's VNode tree, e.g. top-right corner:
+//	toasts.VNode(width-32, 0, 32)
+func NewNotificationQueue(opts NotificationOpts) *NotificationQueue {
+	notifications, setNotifications := CreateSignal[[]Notification](nil)
+	return &NotificationQueue{
+		notifications:    notifications,
+		setNotifications: setNotifications,
+		maxVisible:       opts.MaxVisible,
+		timers:           make(map[int]*time.Timer),
+	}
+}
+
+// Show adds a notification that is removed after duration.
+// A duration <= 0 keeps it until the returned DismissFunc is called.
+func (q *NotificationQueue) Show(msg string, level LogLevel, duration time.Duration) DismissFunc {
+	q.mu.Lock()
+	q.nextID++
+	id := q.nextID
+	q.items = append(q.items, Notification{ID: id, Message: msg, Level: level})
+	q.version++
+	if duration > 0 {
+		q.timers[id] = time.AfterFunc(duration, func() { q.dismiss(id) })
+	}
+	q.mu.Unlock()
+
+	q.publish()
+	return func() { q.dismiss(id) }
+}
+
+// Notifications returns all pending notifications, oldest first.
+func (q *NotificationQueue) Notifications() []Notification {
+	return q.notifications()
+}
+
+// Visible returns the notifications that VNode renders.
+func (q *NotificationQueue) Visible() []Notification {
+	all := q.notifications()
+	if q.maxVisible > 0 && len(all) > q.maxVisible {
+		return all[len(all)-q.maxVisible:]
+	}
+	return all
+}
+
+// Clear removes all notifications and stops their timers.
+func (q *NotificationQueue) Clear() {
+	q.mu.Lock()
+	for id, timer := range q.timers {
+		timer.Stop()
+		delete(q.timers, id)
+	}
+	q.items = nil
+	q.version++
+	q.mu.Unlock()
+
+	q.publish()
+}
+
+// VNode renders the visible notifications stacked vertically in an
+// absolute-positioned column at (x, y). Each toast has a border colored by
+// its level.
+func (q *NotificationQueue) VNode(x, y, width int) gox.VNode {
+	visible := q.Visible()
+	toasts := make([]gox.VNode, len(visible))
+	for i, n := range visible {
+		toasts[i] = gox.Element("box", gox.Props{
+			"border":       "rounded",
+			"width":        width,
+			"paddingLeft":  1,
+			"paddingRight": 1,
+			"style":        map[string]any{"color": notificationColor(n.Level)},
+		}, gox.Element("text", gox.Props{"wrap": true, "color": "white"}, gox.Text(n.Message)))
+	}
+
+	return gox.Element("box", gox.Props{
+		"position":  "absolute",
+		"x":         x,
+		"y":         y,
+		"width":     width,
+		"direction": "column",
+		"zIndex":    notificationZIndex,
+	}, toasts...)
+}
+
+// notificationZIndex keeps toasts above regular content.
+const notificationZIndex = 900
+
+// dismiss removes the notification with the given id, if still present.
+func (q *NotificationQueue) dismiss(id int) {
+	q.mu.Lock()
+	if timer, ok := q.timers[id]; ok {
+		timer.Stop()
+		delete(q.timers, id)
+	}
+	next := make([]Notification, 0, len(q.items))
+	for _, n := range q.items {
+		if n.ID != id {
+			next = append(next, n)
+		}
+	}
+	q.items = next
+	q.version++
+	q.mu.Unlock()
+
+	q.publish()
+}
+
+// publish copies the notifications into the signal. The signal is written
+// outside the lock, so effects that react by calling Show or Clear don't
+// deadlock. If another update lands meanwhile, the copy is written again so
+// the latest list wins.
+func (q *NotificationQueue) publish() {
+	for {
+		q.mu.Lock()
+		version := q.version
+		next := append([]Notification(nil), q.items...)
+		q.mu.Unlock()
+
+		q.setNotifications(next)
+
+		q.mu.Lock()
+		current := q.version
+		q.mu.Unlock()
+		if current == version {
+			return
+		}
+	}
+}
+
+func notificationColor(level LogLevel) string {
+	switch level {
+	case LogLevelError:
+		return "red"
+	case LogLevelWarn:
+		return "yellow"
+	case LogLevelInfo:
+		return "cyan"
+	default:
+		return "gray"
+	}
+}
@@ -0,0 +1,112 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+func TestNotificationQueue_RendersStackedToasts(t *testing.T) {
+	Reset()
+	q := NewNotificationQueue(NotificationOpts{})
+	q.Show("Saved", LogLevelInfo, 0)
+	q.Show("Disk almost full", LogLevelWarn, 0)
+	q.Show("Upload failed", LogLevelError, 0)
+
+	node := boxNode(gox.Props{"width": 30, "height": 10}, q.VNode(2, 0, 20))
+	result := sprintWith(node, PrintOptions{Width: 30, Height: 10, StripStyles: true})
+	lines := strings.Split(result, "\n")
+
+	for i, msg := range []string{"Saved", "Disk almost full", "Upload failed"} {
+		// Each toast is 3 rows: border, message, border
+		row := lines[i*3+1]
+		if !strings.Contains(row, msg) {
+			t.Errorf("expected toast %d to contain %q, got %q", i, msg, row)
+		}
+		if !strings.HasPrefix(row, "  │") {
+			t.Errorf("expected toast %d at x=2 with a border, got %q", i, row)
+		}
+	}
+
+	styled := sprintWith(node, PrintOptions{Width: 30, Height: 10})
+	if !strings.Contains(styled, "\x1b[31m") {
+		t.Error("expected error toast border to be red")
+	}
+}
+
+func TestNotificationQueue_MaxVisibleKeepsNewest(t *testing.T) {
+	Reset()
+	q := NewNotificationQueue(NotificationOpts{MaxVisible: 2})
+	q.Show("one", LogLevelInfo, 0)
+	q.Show("two", LogLevelInfo, 0)
+	q.Show("three", LogLevelInfo, 0)
+
+	visible := q.Visible()
+	if len(visible) != 2 || visible[0].Message != "two" || visible[1].Message != "three" {
+		t.Errorf("expected [two three], got %+v", visible)
+	}
+}
+
+func TestNotificationQueue_AutoDismiss(t *testing.T) {
+	Reset()
+	q := NewNotificationQueue(NotificationOpts{})
+	q.Show("sticky", LogLevelInfo, 0)
+	q.Show("brief", LogLevelInfo, 10*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for len(q.Notifications()) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected brief notification to auto-dismiss, got %+v", q.Notifications())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if q.Notifications()[0].Message != "sticky" {
+		t.Errorf("expected sticky to remain, got %+v", q.Notifications())
+	}
+}
+
+func TestNotificationQueue_ManualDismiss(t *testing.T) {
+	Reset()
+	q := NewNotificationQueue(NotificationOpts{})
+	dismiss := q.Show("bye", LogLevelInfo, time.Hour)
+	dismiss()
+	dismiss() // Idempotent
+
+	if len(q.Notifications()) != 0 {
+		t.Errorf("expected no notifications, got %+v", q.Notifications())
+	}
+}
+
+func TestNotificationQueue_EffectCanShowAndClear(t *testing.T) {
+	Reset()
+	q := NewNotificationQueue(NotificationOpts{})
+
+	// Reacting to the queue by updating it must not deadlock
+	dispose := CreateEffect(func() CleanupFunc {
+		if len(q.Notifications()) > 2 {
+			q.Clear()
+			q.Show("Cleared", LogLevelInfo, 0)
+		}
+		return nil
+	})
+	defer dispose()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			q.Show("n", LogLevelInfo, 0)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlocked updating the queue from an effect")
+	}
+
+	if got := q.Notifications(); len(got) != 1 || got[0].Message != "Cleared" {
+		t.Errorf("expected only the Cleared toast, got %+v", got)
+	}
+}
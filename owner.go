@@ -15,7 +15,8 @@ package goli
 //	    return "done"
 //	})
 func CreateRoot[T any](fn func(dispose DisposeFunc) T) T {
-	owner := &Owner{disposables: make([]func(), 0)}
+	owner := &Owner{disposables: make([]func(), 0), settingUp: true}
+	Global.trackOwner(owner)
 
 	prevOwner := Global.getCurrentOwner()
 	Global.setCurrentOwner(owner)
@@ -25,6 +26,7 @@ func CreateRoot[T any](fn func(dispose DisposeFunc) T) T {
 	}()
 
 	dispose := func() {
+		Global.untrackOwner(owner)
 		Global.mu.Lock()
 		disposables := owner.disposables
 		owner.disposables = nil
@@ -35,17 +37,69 @@ func CreateRoot[T any](fn func(dispose DisposeFunc) T) T {
 		}
 	}
 
-	return fn(dispose)
+	result := fn(dispose)
+	Global.finishSetup(owner)
+	return result
 }
 
 // OnCleanup registers a cleanup function to run when the current owner is disposed.
 func OnCleanup(fn func()) {
+	owner := Global.getCurrentOwner()
+	if owner != nil {
+		Global.addDisposable(owner, fn)
+	}
+}
+
+// SetMaxCleanups makes OnCleanup (and effect registration) panic when a
+// single owner accumulates more than n cleanups. Intended for tests, to catch
+// cleanups registered in a loop or a frequently re-running effect.
+// Pass 0 to disable the limit. Reset clears it.
+func SetMaxCleanups(n int) {
+	Global.mu.Lock()
+	defer Global.mu.Unlock()
+	Global.maxCleanups = n
+}
+
+// EffectCount returns the number of effects (including memos) that have been
+// created and not yet disposed. Intended for tests.
+func EffectCount() int {
 	Global.mu.Lock()
 	defer Global.mu.Unlock()
+	return len(Global.liveComputations)
+}
 
-	owner := Global.currentOwner
-	if owner != nil {
-		owner.disposables = append(owner.disposables, fn)
+// OwnerCount returns the number of roots created by CreateRoot whose
+// dispose function has not been called. Intended for tests.
+func OwnerCount() int {
+	Global.mu.Lock()
+	defer Global.mu.Unlock()
+	return len(Global.liveOwners)
+}
+
+// TB is the part of testing.TB used by DetectLeaks and the HeadlessRenderer
+// assertions. *testing.T and *testing.B satisfy it; goli doesn't import the
+// testing package so it isn't linked into programs that use goli.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// DetectLeaks reports an error if any effects are still live.
+// Defer it first in a test so it runs after the test's other deferred cleanup,
+// like goleak.VerifyNone.
+//
+// Example:
+//
+//	func TestCounter(t *testing.T) {
+//	    goli.Reset()
+//	    defer goli.DetectLeaks(t)
+//	    app := goli.Render(Counter, opts)
+//	    defer app.Dispose()
+//	}
+func DetectLeaks(t TB) {
+	t.Helper()
+	if n := EffectCount(); n > 0 {
+		t.Errorf("goli: %d effect(s) still live; dispose their roots or effects", n)
 	}
 }
 
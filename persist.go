@@ -0,0 +1,120 @@
+package goli
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// PersistentStore stores JSON-encoded signal values by key.
+type PersistentStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte) error
+}
+
+// CreatePersistentSignal creates a signal whose value survives restarts.
+// On creation, a value previously stored under key replaces initial; each
+// write is marshaled to JSON and saved to store. Values that fail to
+// unmarshal are ignored, and store errors don't prevent the signal update.
+//
+// Example:
+//
+//	store := goli.FileStore(filepath.Join(os.Getenv("HOME"), ".myapp"))
+//	selected, setSelected := goli.CreatePersistentSignal("selected", 0, store)
+func CreatePersistentSignal[T any](key string, initial T, store PersistentStore) (Accessor[T], Setter[T]) {
+	if data, ok := store.Get(key); ok {
+		var stored T
+		if err := json.Unmarshal(data, &stored); err == nil {
+			initial = stored
+		}
+	}
+
+	value, setValue := CreateSignal(initial)
+
+	persist := func(newValue T) {
+		setValue(newValue)
+		if data, err := json.Marshal(newValue); err == nil {
+			store.Set(key, data)
+		}
+	}
+
+	return value, persist
+}
+
+// fileStore stores each key as a JSON file in a directory.
+type fileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// FileStore returns a PersistentStore that keeps one <key>.json file per key
+// in dir. The directory is created on first write.
+func FileStore(dir string) PersistentStore {
+	return &fileStore{dir: dir}
+}
+
+func (s *fileStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+func (s *fileStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (s *fileStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	// Write to a temp file and rename so a crash never leaves a partial file
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// MapStore is an in-memory PersistentStore, useful for tests.
+type MapStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+// NewMapStore creates an empty MapStore.
+func NewMapStore() *MapStore {
+	return &MapStore{data: make(map[string][]byte)}
+}
+
+// Get returns the value stored under key.
+func (s *MapStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.data[key]
+	return data, ok
+}
+
+// Set stores value under key.
+func (s *MapStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return nil
+}
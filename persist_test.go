@@ -0,0 +1,55 @@
+package goli
+
+import (
+	"testing"
+)
+
+type formState struct {
+	Name   string
+	Cursor int
+}
+
+func TestCreatePersistentSignal_RestoresValue(t *testing.T) {
+	Reset()
+	store := NewMapStore()
+
+	_, setForm := CreatePersistentSignal("form", formState{}, store)
+	setForm(formState{Name: "goli", Cursor: 3})
+
+	// Simulate a restart
+	Reset()
+	form, _ := CreatePersistentSignal("form", formState{}, store)
+	if got := form(); got.Name != "goli" || got.Cursor != 3 {
+		t.Errorf("expected restored {goli 3}, got %+v", got)
+	}
+}
+
+func TestCreatePersistentSignal_UsesInitialWhenMissing(t *testing.T) {
+	Reset()
+	store := NewMapStore()
+	store.Set("other", []byte(`5`))
+	store.Set("corrupt", []byte(`not json`))
+
+	count, _ := CreatePersistentSignal("count", 7, store)
+	if count() != 7 {
+		t.Errorf("expected initial 7 for missing key, got %d", count())
+	}
+	corrupt, _ := CreatePersistentSignal("corrupt", 1, store)
+	if corrupt() != 1 {
+		t.Errorf("expected initial 1 for corrupt value, got %d", corrupt())
+	}
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	Reset()
+	dir := t.TempDir() + "/state"
+
+	_, setSelected := CreatePersistentSignal("list/selected", 0, FileStore(dir))
+	setSelected(42)
+
+	Reset()
+	selected, _ := CreatePersistentSignal("list/selected", 0, FileStore(dir))
+	if selected() != 42 {
+		t.Errorf("expected 42 restored from file, got %d", selected())
+	}
+}
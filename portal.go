@@ -0,0 +1,122 @@
+// Package goli provides portals for rendering children outside their parent.
+package goli
+
+import "github.com/germtb/gox"
+
+func init() {
+	RegisterIntrinsic("portal", &IntrinsicHandler{
+		Measure: measurePortal,
+		Layout:  layoutPortal,
+	})
+	RegisterIntrinsic("portalTarget", &IntrinsicHandler{
+		Measure: measurePortal,
+		Layout:  layoutPortal,
+	})
+}
+
+// CreatePortalTarget returns a placeholder that renders the children of
+// every portal element whose "target" prop is name. The children are laid
+// out in a column at the placeholder's position, above the rest of the
+// tree like an absolute box; the placeholder itself takes no space.
+//
+// Supported props on the returned element:
+//   - zIndex: z-index of the portal content (default: 0)
+//
+// Example:
+//
+//	gox.Element("box", nil,
+//	    content,
+//	    goli.CreatePortalTarget("overlay"),
+//	)
+//
+//	// Anywhere inside content:
+//	gox.Element("portal", gox.Props{"target": "overlay"}, tooltip)
+func CreatePortalTarget(name string) gox.VNode {
+	return gox.Element("portalTarget", gox.Props{"name": name})
+}
+
+// measurePortal sizes portals and targets as empty: their content is laid
+// out by resolvePortals, outside the normal flow.
+func measurePortal(node gox.VNode, ctx LayoutContext) (int, int) {
+	return 0, 0
+}
+
+func layoutPortal(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox {
+	return &LayoutBox{
+		X:      ctx.X,
+		Y:      ctx.Y,
+		InnerX: ctx.X,
+		InnerY: ctx.Y,
+		Node:   node,
+	}
+}
+
+// extractPortals removes portal elements from an expanded tree and returns
+// their children grouped by target name. Trees without portals are returned
+// unchanged.
+func extractPortals(node gox.VNode) (gox.VNode, map[string][]gox.VNode) {
+	portals := make(map[string][]gox.VNode)
+	var strip func(node gox.VNode) (gox.VNode, bool)
+	strip = func(node gox.VNode) (gox.VNode, bool) {
+		var children []gox.VNode
+		changed := false
+		for i, child := range node.Children {
+			isPortal := child.Type == "portal"
+			if isPortal {
+				target := GetStringProp(child.Props, "target", "")
+				for _, portalChild := range child.Children {
+					portalChild, _ = strip(portalChild)
+					portals[target] = append(portals[target], portalChild)
+				}
+			}
+			stripped := false
+			if !isPortal {
+				child, stripped = strip(child)
+			}
+			if (isPortal || stripped) && !changed {
+				changed = true
+				children = append(make([]gox.VNode, 0, len(node.Children)), node.Children[:i]...)
+			}
+			if changed && !isPortal {
+				children = append(children, child)
+			}
+		}
+		if !changed {
+			return node, false
+		}
+		node.Children = children
+		return node, true
+	}
+	node, _ = strip(node)
+	return node, portals
+}
+
+// resolvePortals lays out the portal children collected for each target in
+// roots, returning the resulting boxes for ComputeLayout's absolute boxes.
+func resolvePortals(roots []*LayoutBox, portals map[string][]gox.VNode) []*LayoutBox {
+	var boxes []*LayoutBox
+	var walk func(box *LayoutBox)
+	walk = func(box *LayoutBox) {
+		if box.Node.Type == "portalTarget" {
+			name := GetStringProp(box.Node.Props, "name", "")
+			if children := portals[name]; len(children) > 0 {
+				content := gox.Element("box", gox.Props{
+					"position":  "absolute",
+					"direction": "column",
+					"zIndex":    GetIntProp(box.Node.Props, "zIndex", 0),
+				}, children...)
+				w, h := measureNode(content)
+				result := layoutNode(content, LayoutContext{X: box.X, Y: box.Y, Width: w, Height: h})
+				boxes = append(boxes, result.Box)
+				boxes = append(boxes, result.AbsoluteBoxes...)
+			}
+		}
+		for _, child := range box.Children {
+			walk(child)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return boxes
+}
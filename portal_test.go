@@ -0,0 +1,65 @@
+package goli
+
+import (
+	"testing"
+
+	"github.com/germtb/gox"
+)
+
+func TestPortal_RendersAtTarget(t *testing.T) {
+	Reset()
+	app := Render(func() gox.VNode {
+		nested := gox.Element("box", gox.Props{"paddingLeft": 2},
+			gox.Element("box", nil,
+				gox.Element("box", nil,
+					gox.Element("text", nil, gox.Text("body")),
+					gox.Element("portal", gox.Props{"target": "overlay"},
+						gox.Element("text", nil, gox.Text("tip")),
+					),
+				),
+			),
+		)
+		return gox.Element("box", gox.Props{"direction": "column"},
+			nested,
+			gox.Element("box", gox.Props{"paddingLeft": 5}, CreatePortalTarget("overlay")),
+		)
+	}, Options{Width: 12, Height: 3, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	h := app.Headless()
+	if x, y, found := app.Headless().CurrentLogicalBuffer().FindText("tip"); !found || x != 5 || y != 1 {
+		t.Errorf("expected portal content at target (5,1), got (%d,%d) found=%v", x, y, found)
+	}
+	if x, y, found := app.Headless().CurrentLogicalBuffer().FindText("body"); !found || x != 2 || y != 0 {
+		t.Errorf("expected portal to take no space beside body at (2,0), got (%d,%d) found=%v", x, y, found)
+	}
+	h.AssertContains(t, "tip")
+}
+
+func TestPortal_WithoutTargetRendersNothing(t *testing.T) {
+	Reset()
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("text", nil, gox.Text("body")),
+			gox.Element("portal", gox.Props{"target": "missing"},
+				gox.Element("text", nil, gox.Text("lost")),
+			),
+		)
+	}, Options{Width: 12, Height: 1, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+
+	if app.Headless().ContainsText("lost") {
+		t.Error("expected portal content without a target not to render")
+	}
+}
+
+func TestExtractPortals_LeavesTreeWithoutPortals(t *testing.T) {
+	tree := gox.Element("box", nil, gox.Element("text", nil, gox.Text("a")))
+	stripped, portals := extractPortals(tree)
+	if len(portals) != 0 {
+		t.Errorf("expected no portals, got %v", portals)
+	}
+	if &stripped.Children[0] != &tree.Children[0] {
+		t.Error("expected children slice to be reused when no portals are present")
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"github.com/germtb/gox"
 )
 
-// PrintOptions configures dimensions for Fprint.
+// PrintOptions configures dimensions and output format for Fprint.
 type PrintOptions struct {
-	Width  int // 0 = auto-detect terminal width (default 80)
-	Height int // 0 = auto-detect terminal height (default 24)
+	Width              int  // 0 = auto-detect terminal width (default 80)
+	Height             int  // 0 = auto-detect terminal height (default 24)
+	StripStyles        bool // Write plain characters only, no ANSI codes
+	TrimTrailingSpaces bool // Drop trailing blank cells from each line
 }
 
 // Print renders a VNode tree to stdout with ANSI styling.
@@ -27,7 +29,8 @@ func Sprint(node gox.VNode) string {
 	return sb.String()
 }
 
-// Fprint renders a VNode tree to a writer with ANSI styling.
+// Fprint renders a VNode tree to a writer with ANSI styling
+// (or plain text when opts.StripStyles is set).
 func Fprint(w io.Writer, node gox.VNode, opts PrintOptions) {
 	width := opts.Width
 	height := opts.Height
@@ -60,7 +63,7 @@ func Fprint(w io.Writer, node gox.VNode, opts PrintOptions) {
 		Y:      0,
 		Width:  width,
 		Height: 100_000,
-	})
+	}).Tree()
 
 	contentHeight := layoutBox.Height
 	if contentHeight <= 0 {
@@ -84,8 +87,47 @@ func Fprint(w io.Writer, node gox.VNode, opts PrintOptions) {
 	}
 found:
 
-	// Convert to ANSI and write
-	output := bufferToAnsiLines(buf, lastRow)
+	// Convert to ANSI (or plain text) and write
+	var output string
+	if opts.StripStyles {
+		output = bufferToPlainLines(buf, lastRow, opts.TrimTrailingSpaces)
+	} else {
+		output = bufferToAnsiLines(buf, lastRow, opts.TrimTrailingSpaces)
+	}
 	io.WriteString(w, output)
 	io.WriteString(w, "\n")
 }
+
+// bufferToPlainLines renders rows 0..maxRow as plain characters separated
+// by \n. If trimTrailing is true, trailing spaces are dropped from each row.
+func bufferToPlainLines(buf *CellBuffer, maxRow int, trimTrailing bool) string {
+	var sb strings.Builder
+	sb.Grow(buf.Width() * (maxRow + 1))
+
+	for y := 0; y <= maxRow; y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		end := buf.Width()
+		if trimTrailing {
+			end = rowContentEnd(buf, y, true)
+		}
+		for x := 0; x < end; x++ {
+			sb.WriteRune(buf.Get(x, y).Char)
+		}
+	}
+
+	return sb.String()
+}
+
+// rowContentEnd returns the index just past the last non-blank cell in row y.
+// A cell is blank if it is a space and, unless ignoreStyle is set, unstyled.
+func rowContentEnd(buf *CellBuffer, y int, ignoreStyle bool) int {
+	for x := buf.Width() - 1; x >= 0; x-- {
+		c := buf.Get(x, y)
+		if c.Char != ' ' || (!ignoreStyle && c.Style != EmptyStyle) {
+			return x + 1
+		}
+	}
+	return 0
+}
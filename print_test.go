@@ -180,3 +180,44 @@ func sprintWith(node gox.VNode, opts PrintOptions) string {
 	Fprint(&sb, node, opts)
 	return sb.String()
 }
+
+func TestFprint_StripStyles(t *testing.T) {
+	node := boxNode(
+		gox.Props{"width": 10, "height": 1},
+		styledTextNode("Bold", Style{Bold: true, Color: ColorRed}),
+	)
+
+	result := sprintWith(node, PrintOptions{Width: 10, Height: 1, StripStyles: true})
+
+	if strings.Contains(result, "\x1b") {
+		t.Errorf("expected no escape codes, got: %q", result)
+	}
+	if result != "Bold      \n" {
+		t.Errorf("expected padded plain line, got: %q", result)
+	}
+}
+
+func TestFprint_TrimTrailingSpaces(t *testing.T) {
+	node := boxNode(
+		gox.Props{"width": 12, "height": 2, "direction": "column"},
+		textNode("a b  c"),
+		textNode("de"),
+	)
+
+	result := sprintWith(node, PrintOptions{Width: 12, Height: 2, StripStyles: true, TrimTrailingSpaces: true})
+	if result != "a b  c\nde\n" {
+		t.Errorf("expected trailing spaces trimmed, got: %q", result)
+	}
+
+	// Styled output keeps content and styled cells but drops blank padding
+	styled := sprintWith(boxNode(
+		gox.Props{"width": 12, "height": 1},
+		styledTextNode("hi", Style{Bold: true}),
+	), PrintOptions{Width: 12, Height: 1, TrimTrailingSpaces: true})
+	if !strings.Contains(styled, "hi") {
+		t.Errorf("expected content preserved, got: %q", styled)
+	}
+	if strings.Contains(StripAnsi(styled), "hi ") {
+		t.Errorf("expected no trailing padding, got: %q", styled)
+	}
+}
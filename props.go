@@ -0,0 +1,104 @@
+package goli
+
+import "github.com/germtb/gox"
+
+// PropReader is gox.Props with typed accessors. Convert with
+// PropReader(node.Props); each accessor returns the default when props is
+// nil, the key is missing, or the value has an unexpected type.
+//
+// Example:
+//
+//	props := goli.PropReader(node.Props)
+//	width := props.Int("width", -1)
+//	padding := props.Spacing("padding", goli.Spacing{})
+type PropReader gox.Props
+
+// Int returns an int property. float64 values are truncated.
+func (p PropReader) Int(key string, defaultVal int) int {
+	switch i := p[key].(type) {
+	case int:
+		return i
+	case float64:
+		return int(i)
+	default:
+		return defaultVal
+	}
+}
+
+// Float returns a float64 property. int values are converted.
+func (p PropReader) Float(key string, defaultVal float64) float64 {
+	switch f := p[key].(type) {
+	case float64:
+		return f
+	case int:
+		return float64(f)
+	default:
+		return defaultVal
+	}
+}
+
+// Bool returns a bool property.
+func (p PropReader) Bool(key string, defaultVal bool) bool {
+	if b, ok := p[key].(bool); ok {
+		return b
+	}
+	return defaultVal
+}
+
+// String returns a string property.
+func (p PropReader) String(key string, defaultVal string) string {
+	if s, ok := p[key].(string); ok {
+		return s
+	}
+	return defaultVal
+}
+
+// Rune returns a rune property. A string value yields its first rune.
+func (p PropReader) Rune(key string, defaultVal rune) rune {
+	switch v := p[key].(type) {
+	case rune:
+		return v
+	case string:
+		for _, r := range v {
+			return r
+		}
+	}
+	return defaultVal
+}
+
+// Style returns a style property given as a Style or a style map.
+func (p PropReader) Style(key string, defaultVal Style) Style {
+	switch s := p[key].(type) {
+	case Style:
+		return s
+	case map[string]any:
+		return mapToStyle(s)
+	default:
+		return defaultVal
+	}
+}
+
+// Spacing returns a spacing property, applying the directional overrides
+// key+"Top", key+"Right", key+"Bottom" and key+"Left". The default is used
+// as the base when key itself is missing.
+func (p PropReader) Spacing(key string, defaultVal Spacing) Spacing {
+	spacing := defaultVal
+	if v, ok := p[key]; ok {
+		spacing = NormalizeSpacing(v)
+	}
+
+	if v, ok := p[key+"Top"]; ok {
+		spacing.Top = getIntFromAny(v)
+	}
+	if v, ok := p[key+"Right"]; ok {
+		spacing.Right = getIntFromAny(v)
+	}
+	if v, ok := p[key+"Bottom"]; ok {
+		spacing.Bottom = getIntFromAny(v)
+	}
+	if v, ok := p[key+"Left"]; ok {
+		spacing.Left = getIntFromAny(v)
+	}
+
+	return spacing
+}
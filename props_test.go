@@ -0,0 +1,147 @@
+package goli
+
+import (
+	"testing"
+
+	"github.com/germtb/gox"
+)
+
+func TestProps_IsGoxPropsAlias(t *testing.T) {
+	// Props must stay interchangeable with gox.Props for existing callers
+	var props gox.Props = Props{"id": "a"}
+	node := gox.Element("box", Props{"id": "b"})
+	if PropReader(props).String("id", "") != "a" || PropReader(node.Props).String("id", "") != "b" {
+		t.Errorf("expected ids a and b, got %v and %v", props, node.Props)
+	}
+}
+
+func TestPropReader_Int(t *testing.T) {
+	tests := []struct {
+		name  string
+		props PropReader
+		want  int
+	}{
+		{"nil props", nil, 7},
+		{"missing key", PropReader{}, 7},
+		{"wrong type", PropReader{"n": "3"}, 7},
+		{"int", PropReader{"n": 3}, 3},
+		{"float64", PropReader{"n": 3.9}, 3},
+	}
+	for _, tt := range tests {
+		if got := tt.props.Int("n", 7); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPropReader_Float(t *testing.T) {
+	tests := []struct {
+		name  string
+		props PropReader
+		want  float64
+	}{
+		{"nil props", nil, 1.5},
+		{"missing key", PropReader{}, 1.5},
+		{"wrong type", PropReader{"f": "2"}, 1.5},
+		{"float64", PropReader{"f": 0.25}, 0.25},
+		{"int", PropReader{"f": 3}, 3},
+	}
+	for _, tt := range tests {
+		if got := tt.props.Float("f", 1.5); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPropReader_Bool(t *testing.T) {
+	tests := []struct {
+		name  string
+		props PropReader
+		want  bool
+	}{
+		{"nil props", nil, true},
+		{"wrong type", PropReader{"b": 0}, true},
+		{"bool", PropReader{"b": false}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.props.Bool("b", true); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPropReader_String(t *testing.T) {
+	tests := []struct {
+		name  string
+		props PropReader
+		want  string
+	}{
+		{"nil props", nil, "def"},
+		{"wrong type", PropReader{"s": 1}, "def"},
+		{"string", PropReader{"s": "row"}, "row"},
+	}
+	for _, tt := range tests {
+		if got := tt.props.String("s", "def"); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPropReader_Rune(t *testing.T) {
+	tests := []struct {
+		name  string
+		props PropReader
+		want  rune
+	}{
+		{"nil props", nil, '?'},
+		{"wrong type", PropReader{"r": 1.5}, '?'},
+		{"empty string", PropReader{"r": ""}, '?'},
+		{"rune", PropReader{"r": '█'}, '█'},
+		{"string", PropReader{"r": "éx"}, 'é'},
+	}
+	for _, tt := range tests {
+		if got := tt.props.Rune("r", '?'); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPropReader_Style(t *testing.T) {
+	def := Style{Dim: true}
+	tests := []struct {
+		name  string
+		props PropReader
+		want  Style
+	}{
+		{"nil props", nil, def},
+		{"wrong type", PropReader{"style": "bold"}, def},
+		{"style", PropReader{"style": Style{Bold: true}}, Style{Bold: true}},
+		{"style map", PropReader{"style": map[string]any{"color": "red"}}, Style{Color: ColorRed}},
+	}
+	for _, tt := range tests {
+		if got := tt.props.Style("style", def); !got.Equal(tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPropReader_Spacing(t *testing.T) {
+	def := Spacing{Top: 1, Right: 1, Bottom: 1, Left: 1}
+	tests := []struct {
+		name  string
+		props PropReader
+		want  Spacing
+	}{
+		{"nil props", nil, def},
+		{"missing key", PropReader{}, def},
+		{"wrong type", PropReader{"padding": "wide"}, Spacing{}},
+		{"int", PropReader{"padding": 2}, Spacing{Top: 2, Right: 2, Bottom: 2, Left: 2}},
+		{"directional override", PropReader{"padding": 2, "paddingLeft": 5}, Spacing{Top: 2, Right: 2, Bottom: 2, Left: 5}},
+		{"override on default", PropReader{"paddingTop": 0}, Spacing{Top: 0, Right: 1, Bottom: 1, Left: 1}},
+	}
+	for _, tt := range tests {
+		if got := tt.props.Spacing("padding", def); got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
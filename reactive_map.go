@@ -0,0 +1,185 @@
+package goli
+
+import "sync"
+
+// ReactiveMap is a map with per-key reactivity. Unlike CreateSignal(map[K]V),
+// where any change re-runs every consumer, Get(k) and Has(k) only track key
+// k, and Keys only tracks the key set.
+type ReactiveMap[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]*reactiveMapEntry[V]
+	order   []K // Present keys in insertion order
+
+	// Entries for missing keys, kept while a computation reads them, and
+	// the count at which unsubscribed ones are swept
+	placeholders int
+	sweepAt      int
+
+	keys    Accessor[[]K]
+	setKeys Setter[[]K]
+}
+
+// reactiveMapEntry is the signal backing a single key. Entries are also
+// created for missing keys read by a computation, so a later Set notifies
+// it; they are dropped once nothing subscribes to them.
+type reactiveMapEntry[V any] struct {
+	signal  *signalValue[reactiveMapSlot[V]]
+	slot    Accessor[reactiveMapSlot[V]]
+	setSlot Setter[reactiveMapSlot[V]]
+	present bool
+}
+
+type reactiveMapSlot[V any] struct {
+	value   V
+	present bool
+}
+
+// CreateComputedMap creates a ReactiveMap holding a copy of initial.
+//
+// Example:
+//
+//	rows := goli.CreateComputedMap(map[int]string{1: "a", 2: "b"})
+//	goli.CreateEffect(func() goli.CleanupFunc {
+//	    fmt.Println(rows.Get(1)) // Re-runs only when key 1 changes
+//	    return nil
+//	})
+//	rows.Set(2, "c") // Does not re-run the effect
+func CreateComputedMap[K comparable, V any](initial map[K]V) *ReactiveMap[K, V] {
+	m := &ReactiveMap[K, V]{entries: make(map[K]*reactiveMapEntry[V], len(initial))}
+	for k, v := range initial {
+		e := m.newEntry(reactiveMapSlot[V]{value: v, present: true})
+		e.present = true
+		m.entries[k] = e
+		m.order = append(m.order, k)
+	}
+	m.keys, m.setKeys = CreateSignal(append([]K(nil), m.order...))
+	return m
+}
+
+func (m *ReactiveMap[K, V]) newEntry(slot reactiveMapSlot[V]) *reactiveMapEntry[V] {
+	sig, s, set := createSignalValue(Global, slot)
+	return &reactiveMapEntry[V]{signal: sig, slot: s, setSlot: set}
+}
+
+// entry returns the entry for k, creating a placeholder if needed.
+// Callers hold m.mu.
+func (m *ReactiveMap[K, V]) entry(k K) *reactiveMapEntry[V] {
+	e, ok := m.entries[k]
+	if !ok {
+		m.sweepPlaceholders()
+		e = m.newEntry(reactiveMapSlot[V]{})
+		m.entries[k] = e
+		m.placeholders++
+	}
+	return e
+}
+
+// sweepPlaceholders drops the entries for missing keys that nothing
+// subscribes to any more. Runs each time the number of placeholders
+// doubles, so probing many missing keys costs amortized constant time.
+func (m *ReactiveMap[K, V]) sweepPlaceholders() {
+	if m.placeholders < m.sweepAt {
+		return
+	}
+	for k, e := range m.entries {
+		if !e.present && e.signal.subscriberCount() == 0 {
+			delete(m.entries, k)
+			m.placeholders--
+		}
+	}
+	m.sweepAt = max(2*m.placeholders, 8)
+}
+
+// read returns the slot for k, subscribing the current computation. A
+// missing key only gets a placeholder entry when a computation is tracking
+// it.
+func (m *ReactiveMap[K, V]) read(k K) reactiveMapSlot[V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[k]
+	if !ok {
+		if !IsTracking() {
+			return reactiveMapSlot[V]{}
+		}
+		e = m.entry(k)
+	}
+	return e.slot()
+}
+
+// Get returns the value for k, or the zero value if k is missing.
+// Tracks only key k.
+func (m *ReactiveMap[K, V]) Get(k K) V {
+	return m.read(k).value
+}
+
+// Has returns whether k is present. Tracks only key k.
+func (m *ReactiveMap[K, V]) Has(k K) bool {
+	return m.read(k).present
+}
+
+// Keys returns the present keys in insertion order. Tracks the key set, not
+// the values.
+func (m *ReactiveMap[K, V]) Keys() []K {
+	return append([]K(nil), m.keys()...)
+}
+
+// Set stores v under k. Setting an existing key to an equal value is a
+// no-op; a new key also notifies Keys consumers.
+func (m *ReactiveMap[K, V]) Set(k K, v V) {
+	m.mu.Lock()
+	e := m.entry(k)
+	added := !e.present
+	if !added && valuesEqual(Untrack(e.slot).value, v) {
+		m.mu.Unlock()
+		return
+	}
+	e.present = true
+	if added {
+		m.order = append(m.order, k)
+		m.placeholders--
+	}
+	keys := append([]K(nil), m.order...)
+	m.mu.Unlock()
+
+	BatchVoid(func() {
+		e.setSlot(reactiveMapSlot[V]{value: v, present: true})
+		if added {
+			m.setKeys(keys)
+		}
+	})
+}
+
+// Delete removes k. Deleting a missing key is a no-op.
+func (m *ReactiveMap[K, V]) Delete(k K) {
+	m.mu.Lock()
+	e, ok := m.entries[k]
+	if !ok || !e.present {
+		m.mu.Unlock()
+		return
+	}
+	e.present = false
+	if e.signal.subscriberCount() == 0 {
+		delete(m.entries, k)
+	} else {
+		m.placeholders++
+	}
+	for i, key := range m.order {
+		if key == k {
+			m.order = append(m.order[:i:i], m.order[i+1:]...)
+			break
+		}
+	}
+	keys := append([]K(nil), m.order...)
+	m.mu.Unlock()
+
+	BatchVoid(func() {
+		e.setSlot(reactiveMapSlot[V]{})
+		m.setKeys(keys)
+	})
+}
+
+// valuesEqual compares two values with ==, treating uncomparable values
+// as different.
+func valuesEqual(a, b any) bool {
+	return depsEqual([]any{a}, []any{b})
+}
@@ -0,0 +1,186 @@
+package goli
+
+import "testing"
+
+const reactiveMapKeys = 1000
+
+// subscribePerKey creates one effect per key that reads get(key) and
+// returns the total number of effect runs.
+func subscribePerKey(get func(k int) string) *int {
+	runs := 0
+	CreateRoot(func(dispose DisposeFunc) int {
+		for k := 0; k < reactiveMapKeys; k++ {
+			CreateEffect(func() CleanupFunc {
+				_ = get(k)
+				runs++
+				return nil
+			})
+		}
+		return 0
+	})
+	return &runs
+}
+
+func newKeyedValues() map[int]string {
+	initial := make(map[int]string, reactiveMapKeys)
+	for k := 0; k < reactiveMapKeys; k++ {
+		initial[k] = "v"
+	}
+	return initial
+}
+
+func TestReactiveMap_SetRerunsOnlyThatKey(t *testing.T) {
+	Reset()
+	m := CreateComputedMap(newKeyedValues())
+	runs := subscribePerKey(m.Get)
+	*runs = 0
+
+	m.Set(42, "changed")
+	if *runs != 1 {
+		t.Errorf("expected 1 consumer re-run, got %d", *runs)
+	}
+	if got := m.Get(42); got != "changed" {
+		t.Errorf("expected changed, got %q", got)
+	}
+}
+
+func TestReactiveMap_PlainMapSignalRerunsAll(t *testing.T) {
+	Reset()
+	values, setValues := CreateSignal(newKeyedValues())
+	runs := subscribePerKey(func(k int) string { return values()[k] })
+	*runs = 0
+
+	next := newKeyedValues()
+	next[42] = "changed"
+	setValues(next)
+	if *runs != reactiveMapKeys {
+		t.Errorf("expected %d consumer re-runs, got %d", reactiveMapKeys, *runs)
+	}
+}
+
+func TestReactiveMap_SetEqualValueIsNoop(t *testing.T) {
+	Reset()
+	m := CreateComputedMap(map[string]int{"a": 1})
+	runs := 0
+	CreateRoot(func(dispose DisposeFunc) int {
+		CreateEffect(func() CleanupFunc {
+			_ = m.Get("a")
+			runs++
+			return nil
+		})
+		return 0
+	})
+
+	m.Set("a", 1)
+	if runs != 1 {
+		t.Errorf("expected no re-run for equal value, got %d runs", runs)
+	}
+}
+
+func TestReactiveMap_KeysAndHas(t *testing.T) {
+	Reset()
+	m := CreateComputedMap[string, int](nil)
+	keyRuns := 0
+	var has bool
+	CreateRoot(func(dispose DisposeFunc) int {
+		CreateEffect(func() CleanupFunc {
+			_ = m.Keys()
+			keyRuns++
+			return nil
+		})
+		CreateEffect(func() CleanupFunc {
+			has = m.Has("b")
+			return nil
+		})
+		return 0
+	})
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("a", 3) // Value change only: key set unchanged
+	if keyRuns != 3 {
+		t.Errorf("expected Keys consumer to run 3 times, got %d", keyRuns)
+	}
+	if !has {
+		t.Error("expected Has(b) to become true")
+	}
+
+	m.Delete("b")
+	if has {
+		t.Error("expected Has(b) to become false after Delete")
+	}
+	if keys := m.Keys(); len(keys) != 1 || keys[0] != "a" {
+		t.Errorf("expected [a], got %v", keys)
+	}
+}
+
+func TestReactiveMap_UntrackedProbesDontGrow(t *testing.T) {
+	Reset()
+	m := CreateComputedMap(map[int]string{0: "a"})
+	for k := 1; k <= reactiveMapKeys; k++ {
+		if m.Has(k) {
+			t.Fatalf("expected key %d to be missing", k)
+		}
+	}
+	if len(m.entries) != 1 {
+		t.Errorf("expected probes of missing keys to add no entries, got %d", len(m.entries))
+	}
+}
+
+func TestReactiveMap_DropsUnsubscribedEntries(t *testing.T) {
+	Reset()
+	m := CreateComputedMap(map[int]string{})
+	dispose := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		for k := 0; k < reactiveMapKeys; k++ {
+			CreateEffect(func() CleanupFunc {
+				_ = m.Has(k)
+				return nil
+			})
+		}
+		return dispose
+	})
+	m.Set(0, "a")
+	dispose()
+
+	m.Delete(0)
+	if _, ok := m.entries[0]; ok {
+		t.Error("expected Delete to drop the entry once nothing subscribes to it")
+	}
+
+	// Keep reading new missing keys from short-lived effects
+	for k := reactiveMapKeys; k < 10*reactiveMapKeys; k++ {
+		CreateRoot(func(dispose DisposeFunc) int {
+			CreateEffect(func() CleanupFunc {
+				_ = m.Get(k)
+				return nil
+			})
+			dispose()
+			return 0
+		})
+	}
+	if len(m.entries) > 2*reactiveMapKeys {
+		t.Errorf("expected entries without subscribers to be dropped, got %d", len(m.entries))
+	}
+}
+
+func BenchmarkReactiveMap_OneKeyPerFrame(b *testing.B) {
+	Reset()
+	m := CreateComputedMap(newKeyedValues())
+	subscribePerKey(m.Get)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Set(i%reactiveMapKeys, string(rune('a'+i%26)))
+	}
+}
+
+func BenchmarkMapSignal_OneKeyPerFrame(b *testing.B) {
+	Reset()
+	values, setValues := CreateSignal(newKeyedValues())
+	subscribePerKey(func(k int) string { return values()[k] })
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		next := values()
+		next[i%reactiveMapKeys] = string(rune('a' + i%26))
+		setValues(next)
+	}
+}
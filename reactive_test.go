@@ -1,6 +1,8 @@
 package goli
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -120,6 +122,78 @@ func TestCreateEffect_RunsImmediately(t *testing.T) {
 	}
 }
 
+func TestCreateEffect_DeferredRunsAfterRootSetup(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	var seen []int
+	ranDuringSetup := false
+
+	CreateRoot(func(dispose DisposeFunc) func() {
+		CreateEffect(func() CleanupFunc {
+			seen = append(seen, count())
+			return nil
+		}, EffectOptions{Deferred: true})
+		ranDuringSetup = len(seen) > 0
+		setCount(1) // Still part of setup
+		return dispose
+	})
+
+	if ranDuringSetup {
+		t.Error("expected deferred effect not to run during CreateRoot")
+	}
+	if len(seen) != 1 || seen[0] != 1 {
+		t.Fatalf("expected one run after setup seeing 1, got %v", seen)
+	}
+
+	setCount(2)
+	if len(seen) != 2 || seen[1] != 2 {
+		t.Errorf("expected deferred effect to track changes after its first run, got %v", seen)
+	}
+}
+
+func TestCreateEffect_DeferredOutsideRootWaitsForBatch(t *testing.T) {
+	Reset()
+	ran := false
+	BatchVoid(func() {
+		CreateEffect(func() CleanupFunc {
+			ran = true
+			return nil
+		}, EffectOptions{Deferred: true})
+		if ran {
+			t.Error("expected deferred effect not to run inside the batch")
+		}
+	})
+	if !ran {
+		t.Error("expected deferred effect to run when the batch ends")
+	}
+}
+
+func TestCreateEffect_OnceRunsOnce(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	runs, cleanups := 0, 0
+
+	dispose := CreateRoot(func(dispose DisposeFunc) func() {
+		CreateEffect(func() CleanupFunc {
+			_ = count()
+			runs++
+			return func() { cleanups++ }
+		}, EffectOptions{Once: true})
+		return dispose
+	})
+
+	setCount(1)
+	setCount(2)
+	if runs != 1 {
+		t.Errorf("expected a single run, got %d", runs)
+	}
+
+	dispose()
+	if cleanups != 1 {
+		t.Errorf("expected cleanup on dispose, got %d", cleanups)
+	}
+}
+
 func TestCreateEffect_RerunsOnDependencyChange(t *testing.T) {
 	Reset()
 	count, setCount := CreateSignal(0)
@@ -228,6 +302,81 @@ func TestCreateEffect_CleanupRunsOnDispose(t *testing.T) {
 	})
 }
 
+func TestCreateEffect_DisposeWhileRootActive(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	disposedRuns, siblingRuns, onCleanups := 0, 0, 0
+
+	var disposeEffect DisposeFunc
+	disposeRoot := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		disposeEffect = CreateEffect(func() CleanupFunc {
+			count()
+			disposedRuns++
+			OnCleanup(func() { onCleanups++ })
+			return nil
+		})
+		CreateEffect(func() CleanupFunc {
+			count()
+			siblingRuns++
+			return nil
+		})
+		return dispose
+	})
+	defer disposeRoot()
+
+	setCount(1)
+	if onCleanups != 1 {
+		t.Errorf("expected OnCleanup to run before the re-run, got %d", onCleanups)
+	}
+
+	disposeEffect()
+	if onCleanups != 2 {
+		t.Errorf("expected OnCleanup to run on dispose, got %d", onCleanups)
+	}
+
+	setCount(2)
+	if disposedRuns != 2 {
+		t.Errorf("expected the disposed effect not to re-run, got %d runs", disposedRuns)
+	}
+	if siblingRuns != 3 {
+		t.Errorf("expected the sibling effect to keep running, got %d runs", siblingRuns)
+	}
+}
+
+func TestCreateEffect_NestedEffectsDisposedOnRerun(t *testing.T) {
+	Reset()
+	outer, setOuter := CreateSignal(0)
+	inner, setInner := CreateSignal(0)
+	innerRuns := 0
+
+	dispose := CreateEffect(func() CleanupFunc {
+		outer()
+		CreateEffect(func() CleanupFunc {
+			inner()
+			innerRuns++
+			return nil
+		})
+		return nil
+	})
+
+	setOuter(1)
+	innerRuns = 0
+	setInner(1)
+	if innerRuns != 1 {
+		t.Errorf("expected only the latest nested effect to run, got %d runs", innerRuns)
+	}
+
+	dispose()
+	innerRuns = 0
+	setInner(2)
+	if innerRuns != 0 {
+		t.Errorf("expected nested effects disposed with their parent, got %d runs", innerRuns)
+	}
+	if n := EffectCount(); n != 0 {
+		t.Errorf("expected no live effects, got %d", n)
+	}
+}
+
 func TestCreateMemo_ComputesDerivedValue(t *testing.T) {
 	Reset()
 	count, _ := CreateSignal(5)
@@ -281,6 +430,111 @@ func TestCreateMemo_ChainsMemos(t *testing.T) {
 	}
 }
 
+func TestCreateMemoWithDeps_IgnoresUnlistedSignals(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(2)
+	scale, setScale := CreateSignal(10)
+	computeCount := 0
+
+	scaled := CreateMemoWithDeps([]func() any{func() any { return count() }}, func() int {
+		computeCount++
+		return count() * scale()
+	})
+
+	if scaled() != 20 {
+		t.Errorf("expected 20, got %d", scaled())
+	}
+	setScale(100)
+	if computeCount != 1 {
+		t.Errorf("expected unlisted signal not to recompute, got %d computations", computeCount)
+	}
+	if scaled() != 20 {
+		t.Errorf("expected stale 20, got %d", scaled())
+	}
+	setCount(3)
+	if scaled() != 300 {
+		t.Errorf("expected 300, got %d", scaled())
+	}
+	if computeCount != 2 {
+		t.Errorf("expected 2 computations, got %d", computeCount)
+	}
+}
+
+func TestCreateMemoWithDeps_UnchangedDepDoesNotRecompute(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(4)
+	computeCount := 0
+
+	// The dep only changes when count crosses an even/odd boundary
+	parity := func() any { return count() % 2 }
+	label := CreateMemoWithDeps([]func() any{parity}, func() string {
+		computeCount++
+		if count()%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	})
+
+	setCount(6)
+	if computeCount != 1 {
+		t.Errorf("expected unchanged dep not to recompute, got %d computations", computeCount)
+	}
+	setCount(7)
+	if label() != "odd" {
+		t.Errorf("expected odd, got %s", label())
+	}
+	if computeCount != 2 {
+		t.Errorf("expected 2 computations, got %d", computeCount)
+	}
+}
+
+func TestCreateSelector_NotifiesOnlyWhenSelectionChanges(t *testing.T) {
+	Reset()
+	type state struct {
+		Title string
+		Count int
+	}
+	store, setStore := CreateSignal(state{Title: "goli"})
+	title := CreateSelector(store, func(s state) string { return s.Title })
+
+	runs := 0
+	CreateEffect(func() CleanupFunc {
+		title()
+		runs++
+		return nil
+	})
+
+	setStore(state{Title: "goli", Count: 1})
+	setStore(state{Title: "goli", Count: 2})
+	if runs != 1 {
+		t.Errorf("expected unrelated changes not to re-run, got %d runs", runs)
+	}
+
+	setStore(state{Title: "gox", Count: 2})
+	if title() != "gox" {
+		t.Errorf("expected gox, got %s", title())
+	}
+	if runs != 2 {
+		t.Errorf("expected 2 runs, got %d", runs)
+	}
+}
+
+func TestCreateSelector_IgnoresSignalsReadInSelector(t *testing.T) {
+	Reset()
+	store, setStore := CreateSignal(1)
+	scale, setScale := CreateSignal(10)
+	scaled := CreateSelector(store, func(s int) int { return s * scale() })
+
+	setScale(20)
+	if scaled() != 10 {
+		t.Errorf("expected scale changes to be ignored, got %d", scaled())
+	}
+	setStore(2)
+	if scaled() != 40 {
+		t.Errorf("expected 40, got %d", scaled())
+	}
+}
+
 func TestCreateRoot_ReturnsResult(t *testing.T) {
 	Reset()
 	result := CreateRoot(func(dispose DisposeFunc) int {
@@ -291,6 +545,37 @@ func TestCreateRoot_ReturnsResult(t *testing.T) {
 	}
 }
 
+func TestCreateRoot_ReturnTypes(t *testing.T) {
+	Reset()
+	if s := CreateRoot(func(DisposeFunc) string { return "root" }); s != "root" {
+		t.Errorf("expected root, got %q", s)
+	}
+
+	errRoot := errors.New("root failed")
+	if err := CreateRoot(func(DisposeFunc) error { return errRoot }); err != errRoot {
+		t.Errorf("expected %v, got %v", errRoot, err)
+	}
+	if err := CreateRoot(func(DisposeFunc) error { return nil }); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+
+	runs := 0
+	count, setCount := CreateSignal(0)
+	stop := CreateRoot(func(dispose DisposeFunc) func() {
+		CreateEffect(func() CleanupFunc {
+			count()
+			runs++
+			return nil
+		})
+		return func() { dispose() }
+	})
+	stop()
+	setCount(1)
+	if runs != 1 {
+		t.Errorf("expected the returned func() to dispose the root, got %d runs", runs)
+	}
+}
+
 func TestCreateRoot_DisposeCleansUpEffects(t *testing.T) {
 	Reset()
 	count, setCount := CreateSignal(0)
@@ -317,6 +602,42 @@ func TestCreateRoot_DisposeCleansUpEffects(t *testing.T) {
 	})
 }
 
+func TestCreateRoot_DisposeDecrementsCounters(t *testing.T) {
+	Reset()
+	count, _ := CreateSignal(0)
+
+	outer := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		CreateEffect(func() CleanupFunc {
+			_ = count()
+			return nil
+		})
+		return dispose
+	})
+	inner := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		CreateMemo(func() int { return count() + 1 })
+		CreateEffectSimple(func() { _ = count() })
+		return dispose
+	})
+
+	if OwnerCount() != 2 {
+		t.Errorf("expected 2 owners, got %d", OwnerCount())
+	}
+	if EffectCount() != 3 {
+		t.Errorf("expected 3 effects, got %d", EffectCount())
+	}
+
+	inner()
+	if OwnerCount() != 1 || EffectCount() != 1 {
+		t.Errorf("expected 1 owner and 1 effect, got %d and %d", OwnerCount(), EffectCount())
+	}
+
+	outer()
+	outer() // Disposing twice doesn't double-decrement
+	if OwnerCount() != 0 || EffectCount() != 0 {
+		t.Errorf("expected no owners or effects, got %d and %d", OwnerCount(), EffectCount())
+	}
+}
+
 func TestOnCleanup_RunsOnDispose(t *testing.T) {
 	Reset()
 	cleaned := false
@@ -407,6 +728,95 @@ func TestBatch_ReturnsResult(t *testing.T) {
 	}
 }
 
+// countEffectRuns creates an effect over a and b and returns its run counter.
+func countEffectRuns(a, b Accessor[int]) *int {
+	runs := 0
+	CreateRoot(func(dispose DisposeFunc) func() {
+		CreateEffect(func() CleanupFunc {
+			_ = a()
+			_ = b()
+			runs++
+			return nil
+		})
+		return dispose
+	})
+	return &runs
+}
+
+func TestBatchErr_ReturnsValueAndError(t *testing.T) {
+	Reset()
+	a, setA := CreateSignal(0)
+	b, setB := CreateSignal(0)
+	runs := countEffectRuns(a, b)
+
+	wantErr := errors.New("boom")
+	v, err := BatchErr(func() (int, error) {
+		setA(1)
+		setB(2)
+		return 7, wantErr
+	})
+
+	if v != 7 || err != wantErr {
+		t.Errorf("expected (7, boom), got (%d, %v)", v, err)
+	}
+	if *runs != 2 {
+		t.Errorf("expected 2 runs (initial + 1 batch), got %d", *runs)
+	}
+}
+
+func TestBatchMany_RunsAllInOneBatch(t *testing.T) {
+	Reset()
+	a, setA := CreateSignal(0)
+	b, setB := CreateSignal(0)
+	runs := countEffectRuns(a, b)
+
+	BatchMany(
+		func() { setA(1) },
+		func() { BatchVoid(func() { setB(2) }) },
+		func() { setA(3) },
+	)
+
+	if *runs != 2 {
+		t.Errorf("expected 2 runs (initial + 1 batch), got %d", *runs)
+	}
+	if a() != 3 || b() != 2 {
+		t.Errorf("expected a=3 b=2, got a=%d b=%d", a(), b())
+	}
+}
+
+func TestWhenIdle_DefersUntilOutermostBatchCompletes(t *testing.T) {
+	Reset()
+	a, setA := CreateSignal(0)
+	b, setB := CreateSignal(0)
+	runs := countEffectRuns(a, b)
+
+	var runsAtIdle []int
+	Batch(func() int {
+		setA(1)
+		BatchVoid(func() {
+			setB(1)
+			WhenIdle(func() { runsAtIdle = append(runsAtIdle, *runs) })
+		})
+		if len(runsAtIdle) != 0 {
+			t.Error("expected WhenIdle to wait for the outer batch")
+		}
+		return 0
+	})
+
+	if len(runsAtIdle) != 1 || runsAtIdle[0] != 2 {
+		t.Errorf("expected idle callback once after effects flushed, got %v", runsAtIdle)
+	}
+}
+
+func TestWhenIdle_RunsImmediatelyOutsideBatch(t *testing.T) {
+	Reset()
+	ran := false
+	WhenIdle(func() { ran = true })
+	if !ran {
+		t.Error("expected WhenIdle to run immediately outside a batch")
+	}
+}
+
 func TestUntrack_PreventsTracking(t *testing.T) {
 	Reset()
 	count, setCount := CreateSignal(0)
@@ -527,3 +937,339 @@ func TestSolidPatterns_ConditionalEffects(t *testing.T) {
 		t.Errorf("expected still [0, 1], got %v", values)
 	}
 }
+
+func TestSetAtIndex_UpdatesElementInPlace(t *testing.T) {
+	Reset()
+	items, setItems := CreateSignal([]int{1, 2, 3})
+	before := items()
+
+	SetAtIndex(setItems, 1, func(v int) int { return v * 10 }, items)
+
+	after := items()
+	if after[1] != 20 {
+		t.Errorf("expected 20, got %d", after[1])
+	}
+	if &before[0] != &after[0] {
+		t.Error("expected the same backing array to be reused")
+	}
+}
+
+func TestSetAtIndex_OutOfRangeDoesNotNotify(t *testing.T) {
+	Reset()
+	items, setItems := CreateSignal([]int{1, 2, 3})
+	effectRuns := 0
+
+	CreateRoot(func(dispose DisposeFunc) func() {
+		CreateEffect(func() CleanupFunc {
+			_ = items()
+			effectRuns++
+			return nil
+		})
+		return dispose
+	})
+
+	SetAtIndex(setItems, 5, func(v int) int { return v + 1 }, items)
+	SetAtIndex(setItems, -1, func(v int) int { return v + 1 }, items)
+	if effectRuns != 1 {
+		t.Errorf("expected 1 effect run, got %d", effectRuns)
+	}
+}
+
+func TestSetAtIndexBatch_NotifiesOnce(t *testing.T) {
+	Reset()
+	items, setItems := CreateSignal([]int{1, 2, 3, 4})
+	effectRuns := 0
+
+	CreateRoot(func(dispose DisposeFunc) func() {
+		CreateEffect(func() CleanupFunc {
+			_ = items()
+			effectRuns++
+			return nil
+		})
+		return dispose
+	})
+
+	SetAtIndexBatch(setItems, map[int]func(int) int{
+		0: func(v int) int { return v + 100 },
+		3: func(v int) int { return v + 100 },
+	}, items)
+
+	if effectRuns != 2 {
+		t.Errorf("expected 2 runs (initial + 1 batch), got %d", effectRuns)
+	}
+	got := items()
+	if got[0] != 101 || got[1] != 2 || got[2] != 3 || got[3] != 104 {
+		t.Errorf("expected [101 2 3 104], got %v", got)
+	}
+}
+
+func BenchmarkSetAtIndex(b *testing.B) {
+	Reset()
+	items, setItems := CreateSignal(make([]int, 10000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SetAtIndex(setItems, i%10000, func(v int) int { return v + 1 }, items)
+	}
+}
+
+func BenchmarkSetWith_FullSliceCopy(b *testing.B) {
+	Reset()
+	items, setItems := CreateSignal(make([]int, 10000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx := i % 10000
+		SetWith(setItems, func(prev []int) []int {
+			next := make([]int, len(prev))
+			copy(next, prev)
+			next[idx]++
+			return next
+		}, items)
+	}
+}
+
+// selectorStore has 100 fields; the benchmarks below change one per frame
+// and count how often a subscriber to field 0 re-runs.
+type selectorStore [100]int
+
+func benchmarkStoreSubscriber(b *testing.B, field func(store Accessor[selectorStore]) Accessor[int]) {
+	Reset()
+	store, setStore := CreateSignal(selectorStore{})
+	value := field(store)
+	runs := 0
+	CreateEffect(func() CleanupFunc {
+		value()
+		runs++
+		return nil
+	})
+	runs = 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		next := store()
+		next[i%len(next)]++
+		setStore(next)
+	}
+	b.ReportMetric(float64(runs)*100/float64(b.N), "reruns/100frames")
+}
+
+func BenchmarkCreateSelector_OneFieldChanges(b *testing.B) {
+	benchmarkStoreSubscriber(b, func(store Accessor[selectorStore]) Accessor[int] {
+		return CreateSelector(store, func(s selectorStore) int { return s[0] })
+	})
+}
+
+func BenchmarkCreateMemo_OneFieldChanges(b *testing.B) {
+	benchmarkStoreSubscriber(b, func(store Accessor[selectorStore]) Accessor[int] {
+		memo := CreateMemo(store)
+		return func() int { return memo()[0] }
+	})
+}
+
+type fakeReporter struct {
+	errors []string
+}
+
+func (r *fakeReporter) Helper() {}
+func (r *fakeReporter) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestDetectLeaks_CatchesUndisposedEffect(t *testing.T) {
+	Reset()
+	count, _ := CreateSignal(0)
+
+	// Intentionally leaked: never disposed
+	CreateEffect(func() CleanupFunc {
+		_ = count()
+		return nil
+	})
+
+	r := &fakeReporter{}
+	DetectLeaks(r)
+	if len(r.errors) != 1 {
+		t.Errorf("expected 1 leak report, got %v", r.errors)
+	}
+}
+
+func TestDetectLeaks_PassesAfterRootDisposed(t *testing.T) {
+	Reset()
+	count, _ := CreateSignal(0)
+
+	dispose := CreateRoot(func(dispose DisposeFunc) DisposeFunc {
+		CreateEffect(func() CleanupFunc {
+			_ = count()
+			return nil
+		})
+		CreateMemo(func() int { return count() * 2 })
+		return dispose
+	})
+	if EffectCount() != 2 {
+		t.Errorf("expected 2 live effects, got %d", EffectCount())
+	}
+	dispose()
+
+	r := &fakeReporter{}
+	DetectLeaks(r)
+	if len(r.errors) != 0 {
+		t.Errorf("expected no leak reports, got %v", r.errors)
+	}
+}
+
+func TestSetMaxCleanups_PanicsWhenExceeded(t *testing.T) {
+	Reset()
+	SetMaxCleanups(3)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic after exceeding cleanup limit")
+		}
+	}()
+
+	CreateRoot(func(dispose DisposeFunc) int {
+		for i := 0; i < 4; i++ {
+			OnCleanup(func() {})
+		}
+		return 0
+	})
+}
+
+func TestSetMaxCleanups_AllowsUpToLimit(t *testing.T) {
+	Reset()
+	SetMaxCleanups(3)
+
+	CreateRoot(func(dispose DisposeFunc) int {
+		for i := 0; i < 3; i++ {
+			OnCleanup(func() {})
+		}
+		return 0
+	})
+}
+
+func TestReadWrite_MapTracksSource(t *testing.T) {
+	Reset()
+	count := NewReadWrite(2)
+	doubled := MapReadWrite(count,
+		func(n int) int { return n * 2 },
+		func(_ int, v int) int { return v / 2 },
+	)
+
+	var seen []int
+	CreateEffect(func() CleanupFunc {
+		seen = append(seen, doubled.Value())
+		return nil
+	})
+
+	count.Update(5)
+	doubled.Update(20)
+	if count.Value() != 10 {
+		t.Errorf("expected write through the mapped binding, got %d", count.Value())
+	}
+	if len(seen) != 3 || seen[1] != 10 || seen[2] != 20 {
+		t.Errorf("expected the effect to track the source, got %v", seen)
+	}
+}
+
+func TestCreateSignalPair_Bidirectional(t *testing.T) {
+	Reset()
+
+	type form struct{ Name string }
+	value, setValue := CreateSignal(form{Name: "goli"})
+
+	var name Accessor[string]
+	var setName Setter[string]
+	CreateRoot(func(dispose DisposeFunc) int {
+		name, setName = CreateSignalPair(
+			func() string { return value().Name },
+			func(v string) {
+				f := Untrack(value)
+				f.Name = v
+				setValue(f)
+			},
+		)
+		return 0
+	})
+
+	setName("gox")
+	if got := value().Name; got != "gox" {
+		t.Errorf("expected write through pair to update original, got %q", got)
+	}
+
+	setValue(form{Name: "outside"})
+	if got := name(); got != "outside" {
+		t.Errorf("expected pair to reflect original, got %q", got)
+	}
+
+	runs := 0
+	CreateRoot(func(dispose DisposeFunc) int {
+		CreateEffect(func() CleanupFunc {
+			_ = name()
+			runs++
+			return nil
+		})
+		return 0
+	})
+	setValue(form{Name: "again"})
+	if runs != 2 {
+		t.Errorf("expected pair consumer to re-run once, got %d runs", runs)
+	}
+}
+
+func TestSuspend_PausesWhileConditionTrue(t *testing.T) {
+	Reset()
+	defer DetectLeaks(t)
+
+	loading, setLoading := CreateSignal(true)
+	data, setData := CreateSignal(1)
+
+	var seen []int
+	dispose := Suspend(loading, func() {
+		seen = append(seen, data())
+	})
+	defer dispose()
+
+	setData(2)
+	if len(seen) != 0 {
+		t.Errorf("expected no runs while suspended, got %v", seen)
+	}
+
+	setLoading(false)
+	setData(3)
+	if len(seen) != 2 || seen[0] != 2 || seen[1] != 3 {
+		t.Errorf("expected [2 3] after resuming, got %v", seen)
+	}
+
+	setLoading(true)
+	setData(4)
+	if len(seen) != 2 {
+		t.Errorf("expected no runs after suspending again, got %v", seen)
+	}
+}
+
+func TestSuspend_DisposesInnerEffectsWhileSuspended(t *testing.T) {
+	Reset()
+	defer DetectLeaks(t)
+
+	loading, setLoading := CreateSignal(false)
+	data, setData := CreateSignal(0)
+
+	innerRuns := 0
+	dispose := Suspend(loading, func() {
+		CreateEffect(func() CleanupFunc {
+			_ = data()
+			innerRuns++
+			return nil
+		})
+	})
+	defer dispose()
+
+	setLoading(true)
+	setData(1)
+	if innerRuns != 1 {
+		t.Errorf("expected inner effect not to run while suspended, got %d runs", innerRuns)
+	}
+
+	setLoading(false)
+	setData(2)
+	if innerRuns != 3 {
+		t.Errorf("expected inner effect to resume, got %d runs", innerRuns)
+	}
+}
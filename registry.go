@@ -3,6 +3,7 @@ package goli
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/germtb/gox"
 )
@@ -13,8 +14,10 @@ import (
 type IntrinsicLayoutFunc func(node gox.VNode, availWidth, availHeight int, ctx *LayoutContext) *LayoutBox
 
 // IntrinsicMeasureFunc measures the intrinsic size of an element.
+// ctx is the zero LayoutContext when no layout constraint is known, e.g.
+// from MeasureNode; a zero Width means unconstrained.
 // Returns (width, height).
-type IntrinsicMeasureFunc func(node gox.VNode, ctx *LayoutContext) (int, int)
+type IntrinsicMeasureFunc func(node gox.VNode, ctx LayoutContext) (int, int)
 
 // IntrinsicRenderFunc renders an element to a CellBuffer.
 type IntrinsicRenderFunc func(box *LayoutBox, buf *CellBuffer, clip *ClipRegion)
@@ -39,11 +42,24 @@ type IntrinsicHandler struct {
 	// RenderLogical draws this element to a LogicalBuffer.
 	// If nil, children are rendered with default box behavior.
 	RenderLogical IntrinsicRenderLogicalFunc
+
+	// OnMount is called after layout when an element of this type first
+	// appears in the tree. Optional.
+	OnMount func(node gox.VNode)
+
+	// OnUnmount is called after layout when an element of this type is no
+	// longer in the tree, and for every element still on screen when the
+	// app is disposed (or a PipelineRenderer stopped). Optional.
+	OnUnmount func(node gox.VNode)
 }
 
 var (
 	intrinsicRegistry = make(map[string]*IntrinsicHandler)
 	registryMu        sync.RWMutex
+
+	// lifecycleHooks is set once any handler with OnMount or OnUnmount is
+	// registered, so trees are only walked for mount tracking when needed.
+	lifecycleHooks atomic.Bool
 )
 
 // RegisterIntrinsic registers a handler for an intrinsic element type.
@@ -53,6 +69,9 @@ func RegisterIntrinsic(name string, handler *IntrinsicHandler) {
 	registryMu.Lock()
 	defer registryMu.Unlock()
 	intrinsicRegistry[name] = handler
+	if handler != nil && (handler.OnMount != nil || handler.OnUnmount != nil) {
+		lifecycleHooks.Store(true)
+	}
 }
 
 // GetIntrinsicHandler returns the handler for an intrinsic element type.
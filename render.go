@@ -46,7 +46,7 @@ func RenderToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 	}
 
 	// Skip fragments, just render children
-	if typeStr == "__fragment__" {
+	if typeStr == "fragment" || typeStr == gox.FragmentNodeType {
 		for _, childBox := range box.Children {
 			RenderToBuffer(childBox, buf, clip)
 		}
@@ -101,7 +101,7 @@ func RenderToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion)
 	}
 
 	// Skip fragments
-	if typeStr == "__fragment__" {
+	if typeStr == "fragment" || typeStr == gox.FragmentNodeType {
 		for _, childBox := range box.Children {
 			RenderToLogicalBuffer(childBox, buf, clip)
 		}
@@ -118,6 +118,16 @@ func RenderToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion)
 	}
 }
 
+// inputCursorLineCol returns the cursor's line and rune column. Inputs that
+// implement CursorLineCol() report it directly; otherwise the rune offset
+// is mapped onto the display value.
+func inputCursorLineCol(inputPrim any, displayValue string, cursorPos int) (int, int) {
+	if lc, ok := inputPrim.(interface{ CursorLineCol() (int, int) }); ok {
+		return lc.CursorLineCol()
+	}
+	return RuneOffsetToLineCol(displayValue, cursorPos)
+}
+
 func RenderInputToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 	node := box.Node
 	x, y, width, height := box.X, box.Y, box.Width, box.Height
@@ -154,18 +164,11 @@ func RenderInputToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 	}
 
 	lines := strings.Split(displayValue, "\n")
-	charPos := 0
+
+	// Map the cursor's rune offset to a (line, rune column) position
+	cursorLine, cursorCol := inputCursorLineCol(inputPrim, displayValue, cursorPos)
 
 	// Calculate vertical scroll offset to keep cursor line visible
-	cursorLine := 0
-	tempPos := 0
-	for i, line := range lines {
-		if cursorPos >= tempPos && cursorPos <= tempPos+len(line) {
-			cursorLine = i
-			break
-		}
-		tempPos += len(line) + 1
-	}
 	scrollY := 0
 	if cursorLine >= height {
 		scrollY = cursorLine - height + 1
@@ -183,14 +186,8 @@ func RenderInputToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 			line := lines[srcLineIdx]
 			lineRunes := []rune(line)
 
-			// Calculate charPos for this line
-			lineCharPos := 0
-			for i := 0; i < srcLineIdx; i++ {
-				lineCharPos += len(lines[i]) + 1
-			}
-
-			cursorOnThisLine := isFocused && cursorPos >= lineCharPos && cursorPos <= lineCharPos+len(lineRunes)
-			cursorColOnLine := cursorPos - lineCharPos
+			cursorOnThisLine := isFocused && srcLineIdx == cursorLine
+			cursorColOnLine := cursorCol
 
 			// Calculate horizontal scroll offset to keep cursor visible
 			scrollX := 0
@@ -218,8 +215,6 @@ func RenderInputToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 					buf.SetCharMerge(charX, lineY, ' ', textStyle)
 				}
 			}
-
-			charPos = lineCharPos + len(line) + 1
 		} else {
 			for i := 0; i < width; i++ {
 				charX := x + i
@@ -229,7 +224,6 @@ func RenderInputToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 			}
 		}
 	}
-	_ = charPos // silence unused variable warning
 }
 
 func RenderInputToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRegion) {
@@ -268,18 +262,11 @@ func RenderInputToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRe
 	}
 
 	lines := strings.Split(displayValue, "\n")
-	charPos := 0
+
+	// Map the cursor's rune offset to a (line, rune column) position
+	cursorLine, cursorCol := inputCursorLineCol(inputPrim, displayValue, cursorPos)
 
 	// Calculate vertical scroll offset to keep cursor line visible
-	cursorLine := 0
-	tempPos := 0
-	for i, line := range lines {
-		if cursorPos >= tempPos && cursorPos <= tempPos+len(line) {
-			cursorLine = i
-			break
-		}
-		tempPos += len(line) + 1
-	}
 	scrollY := 0
 	if cursorLine >= height {
 		scrollY = cursorLine - height + 1
@@ -297,14 +284,8 @@ func RenderInputToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRe
 			line := lines[srcLineIdx]
 			lineRunes := []rune(line)
 
-			// Calculate charPos for this line
-			lineCharPos := 0
-			for i := 0; i < srcLineIdx; i++ {
-				lineCharPos += len(lines[i]) + 1
-			}
-
-			cursorOnThisLine := isFocused && cursorPos >= lineCharPos && cursorPos <= lineCharPos+len(lineRunes)
-			cursorColOnLine := cursorPos - lineCharPos
+			cursorOnThisLine := isFocused && srcLineIdx == cursorLine
+			cursorColOnLine := cursorCol
 
 			// Calculate horizontal scroll offset to keep cursor visible
 			scrollX := 0
@@ -330,8 +311,6 @@ func RenderInputToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRe
 					buf.SetMerge(charX, lineY, New(char, textStyle))
 				}
 			}
-
-			charPos = lineCharPos + len(line) + 1
 		} else {
 			for i := 0; i < width; i++ {
 				charX := x + i
@@ -341,7 +320,28 @@ func RenderInputToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipRe
 			}
 		}
 	}
-	_ = charPos // silence unused variable warning
+}
+
+// selectWindow returns the range of options visible in a select of the
+// given height, starting at the select's scroll offset.
+func selectWindow(selectPrim any, count, height int) (start, end int) {
+	if sel, ok := selectPrim.(interface{ ScrollOffset() int }); ok && height < count {
+		start = max(0, min(sel.ScrollOffset(), count-height))
+	}
+	return start, min(count, start+max(height, 0))
+}
+
+// selectScrollIndicator returns the marker drawn in the last pointer column
+// of the first and last visible options when more options are hidden above
+// (▲) or below (▼), or 0.
+func selectScrollIndicator(idx, start, end, count int) rune {
+	switch {
+	case idx == start && start > 0:
+		return '▲'
+	case idx == end-1 && end < count:
+		return '▼'
+	}
+	return 0
 }
 
 func RenderSelectToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
@@ -354,9 +354,11 @@ func RenderSelectToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 	selectedStyle := getStyleProp(node.Props, "selectedStyle", EmptyStyle)
 
 	optionChildren := FilterChildren(node, "option")
+	start, end := selectWindow(selectPrim, len(optionChildren), box.Height)
 
-	for idx, opt := range optionChildren {
-		optY := y + idx
+	for idx := start; idx < end; idx++ {
+		opt := optionChildren[idx]
+		optY := y + idx - start
 		if clip != nil && (optY < clip.MinY || optY >= clip.MaxY) {
 			continue
 		}
@@ -387,6 +389,11 @@ func RenderSelectToBuffer(box *LayoutBox, buf *CellBuffer, clip *ClipRegion) {
 				buf.SetCharMerge(charX, optY, pointerRunes[i], EmptyStyle)
 			}
 		}
+		if indicator := selectScrollIndicator(idx, start, end, len(optionChildren)); indicator != 0 && pointerWidth > 0 {
+			if charX := x + pointerWidth - 1; IsInClip(charX, optY, clip) {
+				buf.SetCharMerge(charX, optY, indicator, EmptyStyle)
+			}
+		}
 
 		// Render option text
 		optText := CollectTextContent(opt)
@@ -410,9 +417,11 @@ func RenderSelectToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipR
 	selectedStyle := getStyleProp(node.Props, "selectedStyle", EmptyStyle)
 
 	optionChildren := FilterChildren(node, "option")
+	start, end := selectWindow(selectPrim, len(optionChildren), box.Height)
 
-	for idx, opt := range optionChildren {
-		optY := y + idx
+	for idx := start; idx < end; idx++ {
+		opt := optionChildren[idx]
+		optY := y + idx - start
 		if clip != nil && (optY < clip.MinY || optY >= clip.MaxY) {
 			continue
 		}
@@ -443,6 +452,11 @@ func RenderSelectToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipR
 				buf.SetMerge(charX, optY, New(pointerRunes[i], EmptyStyle))
 			}
 		}
+		if indicator := selectScrollIndicator(idx, start, end, len(optionChildren)); indicator != 0 && pointerWidth > 0 {
+			if charX := x + pointerWidth - 1; IsInClip(charX, optY, clip) {
+				buf.SetMerge(charX, optY, New(indicator, EmptyStyle))
+			}
+		}
 
 		// Render option text
 		optText := CollectTextContent(opt)
@@ -458,36 +472,12 @@ func RenderSelectToLogicalBuffer(box *LayoutBox, buf *LogicalBuffer, clip *ClipR
 
 // GetOverflow returns the overflow mode from props.
 func GetOverflow(props map[string]any) Overflow {
-	if props == nil {
-		return OverflowVisible
-	}
-	v, ok := props["overflow"]
-	if !ok {
-		return OverflowVisible
-	}
-	if s, ok := v.(string); ok {
-		return Overflow(s)
-	}
-	if o, ok := v.(Overflow); ok {
+	if o, ok := props["overflow"].(Overflow); ok {
 		return o
 	}
-	return OverflowVisible
+	return Overflow(GetStringProp(props, "overflow", string(OverflowVisible)))
 }
 
 func getStyleProp(props map[string]any, key string, defaultStyle Style) Style {
-	if props == nil {
-		return defaultStyle
-	}
-	v, ok := props[key]
-	if !ok {
-		return defaultStyle
-	}
-	switch s := v.(type) {
-	case Style:
-		return s
-	case map[string]any:
-		return mapToStyle(s)
-	default:
-		return defaultStyle
-	}
+	return PropReader(props).Style(key, defaultStyle)
 }
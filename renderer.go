@@ -2,9 +2,13 @@
 package goli
 
 import (
+	"errors"
 	"io"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/germtb/gox"
 )
@@ -21,8 +25,20 @@ type Options struct {
 	Output          io.Writer
 	Pipeline        bool // Force pipeline renderer (auto-detected if not set)
 	DisableThrottle bool // Disable frame rate limiting (for tests)
+	Headless        bool // Render into memory instead of Output (for tests, see App.Headless)
 	OnRender        func()
-	OnError         func(error)
+	OnError         func(error) // Called when rendering panics; an error frame is shown until the next render
+	OnFirstRender   func()      // Called once after the first frame is rendered, before Render returns
+
+	// CompressBlankRuns paints long runs of blank cells with an erase and a
+	// cursor advance instead of spaces (see RunsToAnsiCompressed). It saves
+	// bytes on large background fills, but requires a terminal with
+	// background color erase (BCE); others show those cells unpainted.
+	CompressBlankRuns bool
+
+	// PipelineThreshold overrides the cell count at which NewAuto switches
+	// to the pipeline renderer. Zero uses the package PipelineThreshold.
+	PipelineThreshold int
 }
 
 // PipelineThreshold is the minimum cell count where the pipeline renderer helps.
@@ -30,18 +46,36 @@ type Options struct {
 const PipelineThreshold = 3000 // ~80x40 or 60x50
 
 // NewAuto creates the optimal renderer based on grid size.
-// Uses pipeline renderer for larger grids (>3000 cells) and sequential for smaller ones.
+// Uses pipeline renderer for larger grids (>3000 cells by default, see
+// Options.PipelineThreshold) and sequential for smaller ones.
 func NewAuto(opts Options) RendererInterface {
+	threshold := PipelineThreshold
+	if opts.PipelineThreshold > 0 {
+		threshold = opts.PipelineThreshold
+	}
 	cells := opts.Width * opts.Height
-	if opts.Pipeline || cells >= PipelineThreshold {
+	if opts.Pipeline || cells >= threshold {
 		return NewPipeline(opts)
 	}
 	return NewRenderer(opts)
 }
 
+// NewAutoWithStats is like NewAuto but enables per-frame stats collection.
+// The returned renderer also implements StatsProvider.
+func NewAutoWithStats(opts Options) RendererInterface {
+	r := NewAuto(opts)
+	r.(StatsProvider).CollectStats(true)
+	return r
+}
+
 // Renderer is the main orchestrator that ties everything together.
 // Uses LogicalBuffer for content storage, transforms to visual rows for output.
 type Renderer struct {
+	// mu serializes frames with Resize, which reallocates the buffers a
+	// frame draws into. They run on different goroutines (SIGWINCH). It is
+	// not held while layout runs user code, which may render again.
+	mu sync.Mutex
+
 	width, height  int
 	currentLogical *LogicalBuffer
 	nextLogical    *LogicalBuffer
@@ -49,6 +83,20 @@ type Renderer struct {
 	nextVisual     *CellBuffer
 	output         io.Writer
 	isFirstRender  bool
+
+	// Instrumentation (see CollectStats)
+	collectStats bool
+	lastStats    RenderStats
+
+	lastLayout *LayoutBox
+	mirror     *LogicalBuffer // See SetMirrorOutput
+	compress   bool           // See Options.CompressBlankRuns
+	mounts     mountTracker
+
+	// Content of the frame on screen, to skip identical frames
+	lastHash          uint64
+	lastContentHeight int
+	hasHash           bool
 }
 
 // NewRenderer creates a new renderer.
@@ -67,25 +115,48 @@ func NewRenderer(opts Options) *Renderer {
 		nextVisual:     NewCellBuffer(opts.Width, opts.Height),
 		output:         output,
 		isFirstRender:  true,
+		compress:       opts.CompressBlankRuns,
 	}
 }
 
 // Render renders a gox VNode tree to the terminal.
 func (r *Renderer) Render(root gox.VNode) {
+	// Callbacks queued during layout, such as Select.OnChange, may render
+	// again, so they run once the frame is drawn and the lock released
+	rt := Global
+	rt.beginLayout()
+	defer rt.endLayout()
+
+	var stats RenderStats
+	stageStart := time.Now()
+
 	// Increment memo generation for cache management
 	BeginRender()
 
-	// Clear next logical buffer
-	r.nextLogical.Clear()
-
-	// Compute layout
+	// Compute layout. It runs components, so it runs unlocked: one that
+	// sets a signal the app reads renders again instead of deadlocking. A
+	// Resize in the meantime is picked up by the next frame.
+	r.mu.Lock()
 	ctx := LayoutContext{
 		X:      0,
 		Y:      0,
 		Width:  r.width,
 		Height: r.height,
 	}
-	layoutBox := ComputeLayout(root, ctx)
+	r.mu.Unlock()
+	layoutBox := ComputeLayout(root, ctx).Tree()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastLayout = layoutBox
+	r.mounts.update(layoutBox)
+	Manager().SetActiveBoxes(CollectBoxKeyHandlers(layoutBox))
+	Manager().SetLayout(layoutBox)
+	Manager().SortByTabIndex()
+	stats.LayoutDuration, stageStart = time.Since(stageStart), time.Now()
+
+	// Clear next logical buffer
+	r.nextLogical.Clear()
 
 	// Render to logical buffer
 	RenderToLogicalBuffer(layoutBox, r.nextLogical, nil)
@@ -96,6 +167,22 @@ func (r *Renderer) Render(root gox.VNode) {
 		contentHeight = layoutBox.Height
 	}
 
+	// Identical content produces an empty diff, so skip the visual stages
+	hash := r.nextLogical.contentHash()
+	if r.hasHash && !r.isFirstRender && hash == r.lastHash && contentHeight == r.lastContentHeight {
+		stats.BufferDuration = time.Since(stageStart)
+		stats.Skipped = true
+		if r.collectStats {
+			r.lastStats = stats
+		}
+		if r.mirror != nil {
+			r.mirror.CopyFrom(r.nextLogical)
+		}
+		r.currentLogical, r.nextLogical = r.nextLogical, r.currentLogical
+		return
+	}
+	r.lastHash, r.lastContentHeight, r.hasHash = hash, contentHeight, true
+
 	// Clear next visual buffer (Clear() already sets all cells to EmptyCell)
 	r.nextVisual.Clear()
 
@@ -115,10 +202,12 @@ func (r *Renderer) Render(root gox.VNode) {
 			r.nextVisual.Set(x, vy, row[x])
 		}
 	}
+	stats.BufferDuration, stageStart = time.Since(stageStart), time.Now()
 
 	// Diff and output
+	var ansiOutput string
 	if r.isFirstRender {
-		io.WriteString(r.output, ClearScreen())
+		ansiOutput = ClearScreen()
 		r.isFirstRender = false
 	}
 
@@ -126,33 +215,154 @@ func (r *Renderer) Render(root gox.VNode) {
 	if contentHeight > r.height {
 		// Overflow mode: output entire buffer sequentially with newlines
 		// ANSI cursor positioning doesn't work beyond terminal height
-		ansiOutput := BufferToSequentialAnsi(r.nextVisual)
-		io.WriteString(r.output, ansiOutput)
+		ansiOutput += bufferToSequentialAnsi(r.nextVisual, r.compress)
+		stats.CellsChanged = r.width * contentHeight
 	} else {
 		// Normal mode: use diff-based updates with cursor positioning
 		changes := DiffBuffers(r.currentVisual, r.nextVisual)
 
 		if len(changes) > 0 {
 			runs := FindRuns(changes)
-			ansiOutput := RunsToAnsi(runs)
-			io.WriteString(r.output, ansiOutput)
+			ansiOutput += r.runsToAnsi(runs)
+			stats.CellsChanged = len(changes)
+			stats.RunsEmitted = len(runs)
 		}
 	}
+	stats.DiffDuration, stageStart = time.Since(stageStart), time.Now()
+
+	if ansiOutput != "" {
+		stats.BytesWritten, _ = io.WriteString(r.output, ansiOutput)
+	}
+	stats.OutputDuration = time.Since(stageStart)
+
+	if r.collectStats {
+		r.lastStats = stats
+	}
+
+	if r.mirror != nil {
+		r.mirror.CopyFrom(r.nextLogical)
+	}
 
 	// Swap buffers
 	r.currentLogical, r.nextLogical = r.nextLogical, r.currentLogical
 	r.currentVisual, r.nextVisual = r.nextVisual, r.currentVisual
 }
 
+// RenderPartial lays out root within region and redraws only that region,
+// leaving the rest of the screen untouched. Use it for small, frequently
+// updated areas such as a status bar clock. root is laid out as if region
+// were the whole screen; its cells are diffed against what is currently
+// shown there. The region is clipped to the screen.
+func (r *Renderer) RenderPartial(region ClipRegion, root gox.VNode) {
+	rt := Global
+	rt.beginLayout()
+	defer rt.endLayout()
+
+	r.mu.Lock()
+	region = *IntersectClip(&region, &ClipRegion{MaxX: r.currentVisual.Width(), MaxY: r.currentVisual.Height()})
+	r.mu.Unlock()
+	width, height := region.MaxX-region.MinX, region.MaxY-region.MinY
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Layout runs unlocked, as in Render
+	layoutBox := ComputeLayout(root, LayoutContext{Width: width, Height: height}).Tree()
+	next := NewCellBuffer(width, height)
+	RenderToBuffer(layoutBox, next, &ClipRegion{MaxX: width, MaxY: height})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev := NewCellBuffer(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			prev.Set(x, y, r.currentVisual.Get(region.MinX+x, region.MinY+y))
+		}
+	}
+
+	changes := DiffBuffers(prev, next)
+	if len(changes) == 0 {
+		return
+	}
+	r.hasHash = false // The screen no longer matches the last full frame
+	for i := range changes {
+		changes[i].X += region.MinX
+		changes[i].Y += region.MinY
+		r.currentVisual.Set(changes[i].X, changes[i].Y, changes[i].Cell)
+	}
+	io.WriteString(r.output, r.runsToAnsi(FindRuns(changes)))
+}
+
+// runsToAnsi renders runs, compressing blank runs if enabled.
+func (r *Renderer) runsToAnsi(runs []CellRun) string {
+	if !r.compress {
+		return RunsToAnsi(runs)
+	}
+	var sb strings.Builder
+	RunsToAnsiCompressed(runs, &sb)
+	return sb.String()
+}
+
+// SetMirrorOutput makes every render also copy its logical buffer into lb,
+// e.g. so a debug console can show what was rendered. Pass nil to stop.
+func (r *Renderer) SetMirrorOutput(lb *LogicalBuffer) {
+	r.mirror = lb
+}
+
+// LogicalBuffer returns a copy of the logical buffer of the last render.
+func (r *Renderer) LogicalBuffer() *LogicalBuffer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lb := NewLogicalBuffer(0)
+	lb.CopyFrom(r.currentLogical)
+	return lb
+}
+
+// unmount calls OnUnmount for the elements of the last frame. App.Dispose
+// calls it, since no later frame will remove them.
+func (r *Renderer) unmount() {
+	r.mu.Lock()
+	mounts := r.mounts
+	r.mounts = mountTracker{}
+	r.mu.Unlock()
+	mounts.unmountAll()
+}
+
+// LastLayout returns the layout tree of the most recent frame, or nil
+// before the first render.
+func (r *Renderer) LastLayout() *LayoutBox {
+	return r.lastLayout
+}
+
+// CollectStats enables or disables per-frame instrumentation.
+// Disabling it also clears the last recorded stats.
+func (r *Renderer) CollectStats(enable bool) {
+	r.collectStats = enable
+	if !enable {
+		r.lastStats = RenderStats{}
+	}
+}
+
+// LastStats returns the stats of the most recent frame rendered while
+// collection was enabled.
+func (r *Renderer) LastStats() RenderStats {
+	return r.lastStats
+}
+
 // Resize resizes the renderer.
+// Buffers are resized in place to avoid reallocating on every resize event.
 func (r *Renderer) Resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.width = width
 	r.height = height
-	r.currentLogical = NewLogicalBuffer(height)
-	r.nextLogical = NewLogicalBuffer(height)
-	r.currentVisual = NewCellBuffer(width, height)
-	r.nextVisual = NewCellBuffer(width, height)
+	r.currentLogical.Resize(height)
+	r.nextLogical.Resize(height)
+	r.currentVisual.Resize(width, height)
+	r.nextVisual.Resize(width, height)
 	r.isFirstRender = true
+	r.hasHash = false
 }
 
 // CurrentBuffer returns the current visual buffer (for testing).
@@ -160,16 +370,39 @@ func (r *Renderer) CurrentBuffer() *CellBuffer {
 	return r.currentVisual
 }
 
+// CurrentLogicalBuffer returns the logical buffer of the last render.
+// Unlike LogicalBuffer it is not a copy and is reused by the next render.
+func (r *Renderer) CurrentLogicalBuffer() *LogicalBuffer {
+	return r.currentLogical
+}
+
 // Width returns the terminal width.
 func (r *Renderer) Width() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.width
 }
 
 // Height returns the terminal height.
 func (r *Renderer) Height() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.height
 }
 
+// DropPolicy controls what PipelineRenderer.Render does when the pipeline
+// is full.
+type DropPolicy int32
+
+const (
+	// DropNewest discards the frame being submitted (default).
+	DropNewest DropPolicy = iota
+	// DropOldest discards queued frames so the latest one always enters.
+	DropOldest
+	// Block makes Render wait until the pipeline has capacity.
+	Block
+)
+
 // PipelineRenderer uses a 4-stage concurrent pipeline for rendering.
 // Each stage runs in its own goroutine:
 //  1. Layout: VNode → LayoutBox
@@ -181,19 +414,77 @@ type PipelineRenderer struct {
 	output        io.Writer
 
 	// Channels connecting pipeline stages
-	layoutIn chan gox.VNode
-	bufferIn chan *LayoutBox
-	diffIn   chan *CellBuffer
-	outputIn chan string
+	layoutIn chan layoutFrame
+	bufferIn chan bufferFrame
+	diffIn   chan diffFrame
+	outputIn chan outputFrame
 
 	// Stop signal
-	stop chan struct{}
-	done chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+	stages sync.WaitGroup
 
 	// Previous buffer for diffing (owned by diff stage)
 	prevBuffer *CellBuffer
+
+	// Instrumentation, written by each stage as frames pass through
+	statsMu      sync.Mutex
+	collectStats bool
+	lastStats    RenderStats
+
+	// Back-pressure handling
+	dropPolicy    atomic.Int32
+	droppedFrames atomic.Int64
+
+	compress bool // See Options.CompressBlankRuns
 }
 
+// pipelineFlush is a sentinel that travels through the pipeline behind the
+// frames queued before it. The output stage closes done when it arrives.
+type pipelineFlush struct {
+	done chan struct{}
+}
+
+// pipelineResize is a sentinel that changes the dimensions of each stage it
+// passes through, so frames queued before it keep the old size.
+type pipelineResize struct {
+	width, height int
+}
+
+// layoutFrame is a VNode for the layout stage, or a flush or resize sentinel.
+type layoutFrame struct {
+	node   gox.VNode
+	flush  *pipelineFlush
+	resize *pipelineResize
+}
+
+// bufferFrame is a layout for the buffer stage, or a flush or resize sentinel.
+type bufferFrame struct {
+	box    *LayoutBox
+	flush  *pipelineFlush
+	resize *pipelineResize
+}
+
+// diffFrame is a buffer for the diff stage, or a flush or resize sentinel.
+type diffFrame struct {
+	buf    *CellBuffer
+	flush  *pipelineFlush
+	resize *pipelineResize
+}
+
+// outputFrame is ANSI output for the output stage, or a flush sentinel.
+type outputFrame struct {
+	ansi  string
+	flush *pipelineFlush
+}
+
+// ErrFlushTimeout is returned by PipelineRenderer.Flush when queued frames
+// are not written within the timeout.
+var ErrFlushTimeout = errors.New("goli: pipeline flush timed out")
+
+// ErrPipelineStopped is returned by PipelineRenderer.Flush after Stop.
+var ErrPipelineStopped = errors.New("goli: pipeline stopped")
+
 // NewPipeline creates a new pipelined renderer.
 func NewPipeline(opts Options) *PipelineRenderer {
 	output := opts.Output
@@ -205,16 +496,18 @@ func NewPipeline(opts Options) *PipelineRenderer {
 		width:      opts.Width,
 		height:     opts.Height,
 		output:     output,
-		layoutIn:   make(chan gox.VNode, 2),
-		bufferIn:   make(chan *LayoutBox, 2),
-		diffIn:     make(chan *CellBuffer, 2),
-		outputIn:   make(chan string, 2),
+		layoutIn:   make(chan layoutFrame, 2),
+		bufferIn:   make(chan bufferFrame, 2),
+		diffIn:     make(chan diffFrame, 2),
+		outputIn:   make(chan outputFrame, 2),
 		stop:       make(chan struct{}),
 		done:       make(chan struct{}),
 		prevBuffer: nil,
+		compress:   opts.CompressBlankRuns,
 	}
 
 	// Start pipeline stages
+	p.stages.Add(4)
 	go p.layoutStage()
 	go p.bufferStage()
 	go p.diffStage()
@@ -225,45 +518,79 @@ func NewPipeline(opts Options) *PipelineRenderer {
 
 // layoutStage: VNode → LayoutBox
 func (p *PipelineRenderer) layoutStage() {
+	defer p.stages.Done()
 	ctx := LayoutContext{
 		X:      0,
 		Y:      0,
 		Width:  p.width,
 		Height: p.height,
 	}
+	var mounts mountTracker // Owned by this stage
+	defer mounts.unmountAll()
 
 	for {
 		select {
 		case <-p.stop:
 			close(p.bufferIn)
 			return
-		case node := <-p.layoutIn:
+		case frame := <-p.layoutIn:
+			if frame.resize != nil {
+				ctx.Width, ctx.Height = frame.resize.width, frame.resize.height
+			}
+			// Pass sentinels through in order
+			if frame.flush != nil || frame.resize != nil {
+				select {
+				case p.bufferIn <- bufferFrame{flush: frame.flush, resize: frame.resize}:
+				case <-p.stop:
+					close(p.bufferIn)
+					return
+				}
+				continue
+			}
+			node := frame.node
 			// Check for empty VNode (used as nil marker)
 			if node.Type == nil {
 				continue
 			}
-			layoutBox := ComputeLayout(node, ctx)
-			p.bufferIn <- layoutBox
+			start := time.Now()
+			layoutBox := ComputeLayout(node, ctx).Tree()
+			mounts.update(layoutBox)
+			Manager().SetActiveBoxes(CollectBoxKeyHandlers(layoutBox))
+			Manager().SetLayout(layoutBox)
+			Manager().SortByTabIndex()
+			p.recordStats(func(s *RenderStats) { s.LayoutDuration = time.Since(start) })
+			select {
+			case p.bufferIn <- bufferFrame{box: layoutBox}:
+			case <-p.stop:
+				close(p.bufferIn)
+				return
+			}
 		}
 	}
 }
 
 // bufferStage: LayoutBox → CellBuffer
-// Uses a rotating pool of 4 buffers to avoid per-frame allocations.
-// Pool size 4 ensures no buffer is reused while still referenced:
+// Uses a rotating pool of 5 buffers to avoid per-frame allocations.
+// Pool size 5 ensures no buffer is reused while still referenced:
 //   - 2 in channel capacity
 //   - 1 being filled
+//   - 1 being diffed by diffStage
 //   - 1 held as prevBuffer by diffStage
 func (p *PipelineRenderer) bufferStage() {
-	const poolSize = 4
+	defer p.stages.Done()
+	const poolSize = 5
 
 	// Pre-allocate buffer pool
+	width, height := p.width, p.height
 	logicalPool := make([]*LogicalBuffer, poolSize)
 	visualPool := make([]*CellBuffer, poolSize)
-	for i := 0; i < poolSize; i++ {
-		logicalPool[i] = NewLogicalBuffer(p.height)
-		visualPool[i] = NewCellBuffer(p.width, p.height)
+	allocPool := func() {
+		for i := 0; i < poolSize; i++ {
+			logicalPool[i] = NewLogicalBuffer(height)
+			visualPool[i] = NewCellBuffer(width, height)
+		}
 	}
+	allocPool()
 	poolIdx := 0
 
 	for {
@@ -271,15 +598,33 @@ func (p *PipelineRenderer) bufferStage() {
 		case <-p.stop:
 			close(p.diffIn)
 			return
-		case layoutBox, ok := <-p.bufferIn:
+		case frame, ok := <-p.bufferIn:
 			if !ok {
 				close(p.diffIn)
 				return
 			}
+			if frame.resize != nil {
+				// Buffers still held by the diff stage keep the old size
+				width, height = frame.resize.width, frame.resize.height
+				allocPool()
+				poolIdx = 0
+			}
+			if frame.flush != nil || frame.resize != nil {
+				select {
+				case p.diffIn <- diffFrame{flush: frame.flush, resize: frame.resize}:
+				case <-p.stop:
+					close(p.diffIn)
+					return
+				}
+				continue
+			}
+			layoutBox := frame.box
 			if layoutBox == nil {
 				continue
 			}
 
+			start := time.Now()
+
 			// Get next buffer from pool (rotating)
 			logicalBuf := logicalPool[poolIdx]
 			visualBuf := visualPool[poolIdx]
@@ -293,15 +638,21 @@ func (p *PipelineRenderer) bufferStage() {
 			RenderToLogicalBuffer(layoutBox, logicalBuf, nil)
 
 			// Convert logical to visual
-			visualRows := logicalBuf.ToVisualRows(p.width)
-			for vy := 0; vy < len(visualRows.Rows) && vy < p.height; vy++ {
+			visualRows := logicalBuf.ToVisualRows(width)
+			for vy := 0; vy < len(visualRows.Rows) && vy < height; vy++ {
 				row := visualRows.Rows[vy]
 				for x := 0; x < len(row); x++ {
 					visualBuf.Set(x, vy, row[x])
 				}
 			}
+			p.recordStats(func(s *RenderStats) { s.BufferDuration = time.Since(start) })
 
-			p.diffIn <- visualBuf
+			select {
+			case p.diffIn <- diffFrame{buf: visualBuf}:
+			case <-p.stop:
+				close(p.diffIn)
+				return
+			}
 		}
 	}
 }
@@ -309,11 +660,13 @@ func (p *PipelineRenderer) bufferStage() {
 // diffStage: CellBuffer → ANSI string
 // Uses pre-allocated slices for diff results.
 func (p *PipelineRenderer) diffStage() {
+	defer p.stages.Done()
 	isFirst := true
+	width, height := p.width, p.height
 
 	// Pre-allocate reusable slices for diff results
 	// Estimate: 20% of cells change per frame on average
-	estimatedChanges := (p.width * p.height) / 5
+	estimatedChanges := (width * height) / 5
 	if estimatedChanges < 64 {
 		estimatedChanges = 64
 	}
@@ -329,15 +682,33 @@ func (p *PipelineRenderer) diffStage() {
 		case <-p.stop:
 			close(p.outputIn)
 			return
-		case currentBuf, ok := <-p.diffIn:
+		case frame, ok := <-p.diffIn:
 			if !ok {
 				close(p.outputIn)
 				return
 			}
+			if frame.resize != nil {
+				// Redraw everything at the new size
+				width, height = frame.resize.width, frame.resize.height
+				p.prevBuffer = nil
+				isFirst = true
+			}
+			if frame.flush != nil {
+				select {
+				case p.outputIn <- outputFrame{flush: frame.flush}:
+				case <-p.stop:
+					close(p.outputIn)
+					return
+				}
+				continue
+			}
+			currentBuf := frame.buf
 			if currentBuf == nil {
 				continue
 			}
 
+			start := time.Now()
+
 			// Clear and reuse slices
 			changes = changes[:0]
 			runs = runs[:0]
@@ -347,11 +718,11 @@ func (p *PipelineRenderer) diffStage() {
 				// First frame: clear screen and output everything
 				sb.WriteString(ClearScreen())
 				// Create a blank buffer to diff against (only on first frame)
-				blankBuf := NewCellBuffer(p.width, p.height)
+				blankBuf := NewCellBuffer(width, height)
 				changes = DiffBuffersInto(blankBuf, currentBuf, changes)
 				if len(changes) > 0 {
 					runs = FindRunsInto(changes, runs)
-					RunsToAnsiBuilder(runs, &sb)
+					runsToAnsiBuilder(runs, &sb, p.compress)
 				}
 				isFirst = false
 			} else {
@@ -359,15 +730,26 @@ func (p *PipelineRenderer) diffStage() {
 				changes = DiffBuffersInto(p.prevBuffer, currentBuf, changes)
 				if len(changes) > 0 {
 					runs = FindRunsInto(changes, runs)
-					RunsToAnsiBuilder(runs, &sb)
+					runsToAnsiBuilder(runs, &sb, p.compress)
 				}
 			}
 
 			// Keep current buffer for next diff
 			p.prevBuffer = currentBuf
 
+			p.recordStats(func(s *RenderStats) {
+				s.DiffDuration = time.Since(start)
+				s.CellsChanged = len(changes)
+				s.RunsEmitted = len(runs)
+			})
+
 			if sb.Len() > 0 {
-				p.outputIn <- sb.String()
+				select {
+				case p.outputIn <- outputFrame{ansi: sb.String()}:
+				case <-p.stop:
+					close(p.outputIn)
+					return
+				}
 			}
 		}
 	}
@@ -375,37 +757,164 @@ func (p *PipelineRenderer) diffStage() {
 
 // outputStage: ANSI string → io.Writer
 func (p *PipelineRenderer) outputStage() {
+	defer p.stages.Done()
 	for {
 		select {
 		case <-p.stop:
 			close(p.done)
 			return
-		case ansiStr, ok := <-p.outputIn:
+		case frame, ok := <-p.outputIn:
 			if !ok {
 				close(p.done)
 				return
 			}
-			io.WriteString(p.output, ansiStr)
+			if frame.flush != nil {
+				close(frame.flush.done)
+				continue
+			}
+			start := time.Now()
+			n, _ := io.WriteString(p.output, frame.ansi)
+			p.recordStats(func(s *RenderStats) {
+				s.OutputDuration = time.Since(start)
+				s.BytesWritten = n
+			})
 		}
 	}
 }
 
-// Render submits a frame to the pipeline (non-blocking if pipeline has capacity).
+// recordStats applies a stage's measurements when collection is enabled.
+func (p *PipelineRenderer) recordStats(fn func(s *RenderStats)) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	if p.collectStats {
+		fn(&p.lastStats)
+	}
+}
+
+// CollectStats enables or disables per-frame instrumentation.
+// Disabling it also clears the last recorded stats.
+func (p *PipelineRenderer) CollectStats(enable bool) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	p.collectStats = enable
+	if !enable {
+		p.lastStats = RenderStats{}
+	}
+}
+
+// LastStats returns the most recent measurements from each stage.
+// Since stages run concurrently, fields may come from adjacent frames.
+func (p *PipelineRenderer) LastStats() RenderStats {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	return p.lastStats
+}
+
+// Render submits a frame to the pipeline. When the pipeline is full the
+// frame is handled according to the drop policy (see SetDropPolicy). After
+// Stop, frames are discarded.
 func (p *PipelineRenderer) Render(root gox.VNode) {
-	select {
-	case p.layoutIn <- root:
+	frame := layoutFrame{node: root}
+	switch DropPolicy(p.dropPolicy.Load()) {
+	case Block:
+		select {
+		case p.layoutIn <- frame:
+		case <-p.stop:
+		}
+	case DropOldest:
+		for {
+			select {
+			case p.layoutIn <- frame:
+				return
+			default:
+			}
+			// Pipeline full - discard the oldest queued frame and retry
+			select {
+			case oldest := <-p.layoutIn:
+				if oldest.flush != nil || oldest.resize != nil {
+					// Never drop a sentinel; requeue it behind the remaining frames
+					select {
+					case p.layoutIn <- oldest:
+					case <-p.stop:
+						return
+					}
+					continue
+				}
+				p.droppedFrames.Add(1)
+			default:
+			}
+		}
 	default:
-		// Pipeline full - drop frame (could also block here)
+		select {
+		case p.layoutIn <- frame:
+		default:
+			// Pipeline full - drop this frame
+			p.droppedFrames.Add(1)
+		}
 	}
 }
 
+// SetDropPolicy sets how Render behaves when the pipeline is full.
+func (p *PipelineRenderer) SetDropPolicy(policy DropPolicy) {
+	p.dropPolicy.Store(int32(policy))
+}
+
+// DroppedFrames returns the number of frames discarded due to back-pressure.
+func (p *PipelineRenderer) DroppedFrames() int64 {
+	return p.droppedFrames.Load()
+}
+
 // RenderBlocking submits a frame and waits until it enters the pipeline.
 func (p *PipelineRenderer) RenderBlocking(root gox.VNode) {
-	p.layoutIn <- root
+	select {
+	case p.layoutIn <- layoutFrame{node: root}:
+	case <-p.stop:
+	}
 }
 
-// Stop shuts down the pipeline gracefully.
+// Resize changes the dimensions used for frames submitted after the call.
+// The first frame at the new size redraws the whole screen.
+func (p *PipelineRenderer) Resize(width, height int) {
+	select {
+	case p.layoutIn <- layoutFrame{resize: &pipelineResize{width: width, height: height}}:
+	case <-p.stop:
+	}
+}
+
+// Flush waits until every frame submitted before the call has been written
+// to the output, so the caller can safely restore the terminal afterwards.
+// Returns ErrFlushTimeout if that takes longer than timeout.
+func (p *PipelineRenderer) Flush(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	flush := &pipelineFlush{done: make(chan struct{})}
+	select {
+	case p.layoutIn <- layoutFrame{flush: flush}:
+	case <-p.stop:
+		return ErrPipelineStopped
+	case <-timer.C:
+		return ErrFlushTimeout
+	}
+
+	select {
+	case <-flush.done:
+		return nil
+	case <-p.done:
+		return ErrPipelineStopped
+	case <-timer.C:
+		return ErrFlushTimeout
+	}
+}
+
+// pipelineStopFlushTimeout bounds how long Stop waits for queued frames.
+const pipelineStopFlushTimeout = 500 * time.Millisecond
+
+// Stop drains queued frames (waiting up to 500ms) and shuts down the
+// pipeline. It returns once every stage goroutine has exited.
 func (p *PipelineRenderer) Stop() {
+	p.Flush(pipelineStopFlushTimeout)
 	close(p.stop)
 	<-p.done
+	p.stages.Wait()
 }
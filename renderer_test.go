@@ -0,0 +1,437 @@
+package goli
+
+import (
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+// gatedWriter blocks all writes until the gate is opened, simulating a
+// slow terminal that backs up the pipeline.
+type gatedWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  strings.Builder
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func frameNode(i int) gox.VNode {
+	return gox.Element("text", nil, gox.Text(strings.Repeat(string(rune('A'+i)), 5)))
+}
+
+func TestPipelineRenderer_DroppedFramesUnderBackPressure(t *testing.T) {
+	w := newGatedWriter()
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+
+	for i := 0; i < 20; i++ {
+		p.Render(frameNode(i))
+	}
+	close(w.gate)
+	p.Stop()
+
+	if p.DroppedFrames() == 0 {
+		t.Error("expected frames to be dropped while the output was blocked")
+	}
+}
+
+func TestPipelineRenderer_DropOldestKeepsLatestFrame(t *testing.T) {
+	w := newGatedWriter()
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+	p.SetDropPolicy(DropOldest)
+
+	const frames = 20
+	for i := 0; i < frames; i++ {
+		p.Render(frameNode(i))
+	}
+	if p.DroppedFrames() == 0 {
+		t.Error("expected older frames to be dropped")
+	}
+	close(w.gate)
+
+	last := strings.Repeat(string(rune('A'+frames-1)), 5)
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(w.String(), last) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	p.Stop()
+
+	if !strings.Contains(w.String(), last) {
+		t.Errorf("expected latest frame %q in output", last)
+	}
+}
+
+func TestPipelineRenderer_BlockDropsNothing(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+	p.SetDropPolicy(Block)
+
+	for i := 0; i < 20; i++ {
+		p.Render(frameNode(i))
+	}
+	p.Stop()
+
+	if got := p.DroppedFrames(); got != 0 {
+		t.Errorf("expected 0 dropped frames, got %d", got)
+	}
+}
+
+func TestPipelineRenderer_FlushWritesQueuedFrames(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+	defer p.Stop()
+	p.SetDropPolicy(Block)
+
+	const frames = 10
+	for i := 0; i < frames; i++ {
+		p.Render(frameNode(i))
+	}
+	if err := p.Flush(2 * time.Second); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	last := strings.Repeat(string(rune('A'+frames-1)), 5)
+	if !strings.Contains(w.String(), last) {
+		t.Errorf("expected last frame %q written before Flush returned", last)
+	}
+}
+
+func TestPipelineRenderer_FlushTimeout(t *testing.T) {
+	w := newGatedWriter()
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+
+	p.Render(frameNode(0))
+	if err := p.Flush(20 * time.Millisecond); err != ErrFlushTimeout {
+		t.Errorf("expected ErrFlushTimeout, got %v", err)
+	}
+
+	close(w.gate)
+	p.Stop()
+}
+
+func TestPipelineRenderer_StopExitsBlockedStages(t *testing.T) {
+	before := runtime.NumGoroutine()
+	w := newGatedWriter()
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+	p.SetDropPolicy(Block)
+
+	// Back up every stage behind the blocked writer. Ten frames fill the
+	// stages and the channels between them without blocking Render.
+	for i := 0; i < 10; i++ {
+		p.Render(frameNode(i))
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+	<-p.stop // Flush timed out; release the writer mid-shutdown
+	close(w.gate)
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("pipeline stages still running after Stop: %d goroutines, want %d", n, before)
+	}
+}
+
+func TestPipelineRenderer_RenderAfterStopReturns(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+	p.Stop()
+
+	for _, policy := range []DropPolicy{Block, DropOldest} {
+		p.SetDropPolicy(policy)
+		done := make(chan struct{})
+		go func() {
+			// More frames than the channel holds, with a sentinel queued
+			p.Resize(5, 1)
+			for i := 0; i < 10; i++ {
+				p.Render(frameNode(i))
+			}
+			p.RenderBlocking(frameNode(10))
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Render with policy %v blocked after Stop", policy)
+		}
+	}
+}
+
+func TestPipelineRenderer_ResizeAppliesToNextFrame(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	p := NewPipeline(Options{Width: 10, Height: 2, Output: w})
+	defer p.Stop()
+	p.SetDropPolicy(Block)
+
+	wide := gox.Element("text", nil, gox.Text("0123456789ABCDE"))
+	p.Render(wide)
+	if err := p.Flush(2 * time.Second); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if strings.Contains(w.String(), "0123456789ABCDE") {
+		t.Fatalf("expected text wrapped at width 10, got %q", w.String())
+	}
+
+	p.Resize(20, 3)
+	p.Render(wide)
+	if err := p.Flush(2 * time.Second); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	out := w.String()
+	if !strings.Contains(out, "0123456789ABCDE") {
+		t.Errorf("expected full text at width 20, got %q", out)
+	}
+	if n := strings.Count(out, ClearScreen()); n != 2 {
+		t.Errorf("expected the resized frame to redraw the screen, got %d clears", n)
+	}
+}
+
+func TestNewAuto_PipelineThresholdForcesPipeline(t *testing.T) {
+	r := NewAuto(Options{Width: 1, Height: 1, Output: &strings.Builder{}, PipelineThreshold: 1})
+	p, ok := r.(*PipelineRenderer)
+	if !ok {
+		t.Fatalf("expected *PipelineRenderer, got %T", r)
+	}
+	p.Stop()
+}
+
+func TestNewAuto_PipelineThresholdForcesSequential(t *testing.T) {
+	r := NewAuto(Options{Width: 80, Height: 24, Output: &strings.Builder{}, PipelineThreshold: 999999})
+	if _, ok := r.(*Renderer); !ok {
+		t.Errorf("expected *Renderer, got %T", r)
+	}
+}
+
+func TestNewAutoWithStats_CollectsStats(t *testing.T) {
+	Reset()
+	r := NewAutoWithStats(Options{Width: 20, Height: 2, Output: &strings.Builder{}})
+	sp, ok := r.(StatsProvider)
+	if !ok {
+		t.Fatalf("expected StatsProvider, got %T", r)
+	}
+	r.Render(frameNode(0))
+	if sp.LastStats().BytesWritten == 0 {
+		t.Error("expected stats to be collected")
+	}
+}
+
+func TestRenderer_MirrorOutputMatchesCurrentBuffer(t *testing.T) {
+	Reset()
+	r := NewRenderer(Options{Width: 10, Height: 3, Output: &strings.Builder{}})
+	mirror := NewLogicalBuffer(0)
+	r.SetMirrorOutput(mirror)
+
+	r.Render(gox.Element("box", gox.Props{"direction": "column"},
+		gox.Element("text", nil, gox.Text("hello")),
+		gox.Element("text", nil, gox.Text("world")),
+	))
+
+	visual := r.CurrentBuffer()
+	for _, lb := range []*LogicalBuffer{mirror, r.LogicalBuffer()} {
+		if lb.Height() != visual.Height() {
+			t.Fatalf("expected height %d, got %d", visual.Height(), lb.Height())
+		}
+		for y := 0; y < visual.Height(); y++ {
+			for x := 0; x < visual.Width(); x++ {
+				if !lb.Get(x, y).Equal(visual.Get(x, y)) {
+					t.Errorf("cell (%d,%d): expected %q, got %q", x, y, visual.Get(x, y).Char, lb.Get(x, y).Char)
+				}
+			}
+		}
+	}
+
+	// The copy is independent of later renders
+	snapshot := r.LogicalBuffer()
+	r.Render(gox.Element("text", nil, gox.Text("bye")))
+	if got := snapshot.Get(0, 0).Char; got != 'h' {
+		t.Errorf("expected snapshot to keep 'h', got %q", got)
+	}
+	if got := mirror.Get(0, 0).Char; got != 'b' {
+		t.Errorf("expected mirror to follow render, got %q", got)
+	}
+}
+
+func TestRenderer_RenderPartialOnlyEmitsRegion(t *testing.T) {
+	Reset()
+	var out strings.Builder
+	r := NewRenderer(Options{Width: 20, Height: 3, Output: &out})
+	r.Render(gox.Element("box", gox.Props{"direction": "column"},
+		gox.Element("text", nil, gox.Text("header")),
+		gox.Element("text", nil, gox.Text("body")),
+		gox.Element("text", nil, gox.Text("time 10:00")),
+	))
+
+	out.Reset()
+	r.RenderPartial(ClipRegion{MinX: 0, MinY: 2, MaxX: 10, MaxY: 3},
+		gox.Element("text", nil, gox.Text("time 10:01")))
+
+	ansi := out.String()
+	if !strings.Contains(ansi, MoveCursor(9, 2)) || StripAnsi(ansi) != "1" {
+		t.Errorf("expected only the changed digit at (9,2), got %q", ansi)
+	}
+	for _, row := range []string{"\x1b[1;", "\x1b[2;"} {
+		if strings.Contains(ansi, row) {
+			t.Errorf("expected no output outside the region, got %q", ansi)
+		}
+	}
+	if got := r.CurrentBuffer().Get(9, 2).Char; got != '1' {
+		t.Errorf("expected current buffer updated to '1', got %q", got)
+	}
+	if got := r.CurrentBuffer().Get(0, 0).Char; got != 'h' {
+		t.Errorf("expected rest of screen unchanged, got %q at (0,0)", got)
+	}
+
+	// A full render after the partial one only redraws what differs from it
+	out.Reset()
+	r.Render(gox.Element("box", gox.Props{"direction": "column"},
+		gox.Element("text", nil, gox.Text("header")),
+		gox.Element("text", nil, gox.Text("body")),
+		gox.Element("text", nil, gox.Text("time 10:01")),
+	))
+	if out.Len() != 0 {
+		t.Errorf("expected no output for an already drawn frame, got %q", out.String())
+	}
+}
+
+func TestRenderer_RenderPartialClipsToScreen(t *testing.T) {
+	Reset()
+	var out strings.Builder
+	r := NewRenderer(Options{Width: 5, Height: 1, Output: &out})
+	r.RenderPartial(ClipRegion{MinX: 3, MinY: 0, MaxX: 10, MaxY: 4},
+		gox.Element("text", nil, gox.Text("abcdef")))
+
+	if got := r.CurrentBuffer().Get(4, 0).Char; got != 'b' {
+		t.Errorf("expected region clipped to screen width, got %q at (4,0)", got)
+	}
+}
+
+func TestRenderer_SkipsIdenticalFrames(t *testing.T) {
+	Reset()
+	var out strings.Builder
+	r := NewRenderer(Options{Width: 10, Height: 2, Output: &out})
+	r.CollectStats(true)
+	view := func(text string) gox.VNode {
+		return gox.Element("text", nil, gox.Text(text))
+	}
+
+	r.Render(view("same"))
+	if r.LastStats().Skipped {
+		t.Error("expected first frame to be rendered")
+	}
+
+	out.Reset()
+	r.Render(view("same"))
+	if !r.LastStats().Skipped || out.Len() != 0 {
+		t.Errorf("expected identical frame skipped with no output, got skipped=%v output=%q", r.LastStats().Skipped, out.String())
+	}
+
+	r.Render(view("diff"))
+	if r.LastStats().Skipped || !strings.Contains(out.String(), "diff") {
+		t.Errorf("expected changed frame rendered, got skipped=%v output=%q", r.LastStats().Skipped, out.String())
+	}
+	if got := r.CurrentLogicalBuffer().GetRowText(0); got != "diff" {
+		t.Errorf("expected logical buffer to hold the new frame, got %q", got)
+	}
+
+	r.Resize(12, 2)
+	r.Render(view("diff"))
+	if r.LastStats().Skipped {
+		t.Error("expected frame after Resize to be rendered")
+	}
+}
+
+func BenchmarkRenderer_IdenticalFrames(b *testing.B) {
+	Reset()
+	r := NewRenderer(Options{Width: 80, Height: 24, Output: io.Discard})
+	rows := make([]gox.VNode, 24)
+	for i := range rows {
+		rows[i] = gox.Element("text", nil, gox.Text(strings.Repeat("x", 80)))
+	}
+	view := gox.Element("box", gox.Props{"direction": "column"}, rows...)
+	r.Render(view)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Render(view)
+	}
+}
+
+func TestRenderer_ResizeDuringRender(t *testing.T) {
+	Reset()
+	r := NewRenderer(Options{Width: 20, Height: 5, Output: io.Discard})
+	node := gox.Element("text", nil, gox.Text("resize me"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			r.Render(node)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			r.Resize(10+i%20, 3+i%5)
+		}
+	}()
+	wg.Wait()
+
+	r.Resize(30, 4)
+	r.Render(node)
+	if r.Width() != 30 || r.Height() != 4 {
+		t.Errorf("expected 30x4, got %dx%d", r.Width(), r.Height())
+	}
+	if x, y, found := r.CurrentBuffer().FindText("resize me"); !found || x != 0 || y != 0 {
+		t.Errorf("expected text at (0,0) after resizing, got (%d,%d) found=%v", x, y, found)
+	}
+}
+
+func TestRenderer_SwappedCellsReachTheTerminal(t *testing.T) {
+	out := &strings.Builder{}
+	r := NewRenderer(Options{Width: 5, Height: 1, Output: out})
+	r.Render(gox.Element("text", nil, gox.Text("ab")))
+	out.Reset()
+
+	r.Render(gox.Element("text", nil, gox.Text("ba")))
+	if !strings.Contains(out.String(), "ba") {
+		t.Errorf("expected the second frame to write \"ba\", got %q", out.String())
+	}
+}
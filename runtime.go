@@ -1,7 +1,12 @@
 // Package goli provides the reactive TUI framework runtime.
 package goli
 
-import "sync"
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
 
 // computation tracks a reactive computation (effect or memo).
 type computation struct {
@@ -18,6 +23,8 @@ type subscriber interface {
 // Owner tracks disposables for cleanup.
 type Owner struct {
 	disposables []func()
+	settingUp   bool     // Inside its CreateRoot callback
+	deferred    []func() // Deferred effects to run when the callback returns
 }
 
 // Runtime holds all global mutable state for the goli framework.
@@ -28,11 +35,28 @@ type Runtime struct {
 	// Reactive context (moved from signals package)
 	currentComputation *computation
 	currentOwner       *Owner
-	batchDepth         int
-	pendingComputations map[*computation]struct{}
+	batches            map[uint64]*batchState // Active batches by goroutine ID
+	activeBatches      atomic.Int32           // len(batches), read without mu
+
+	// Test-mode diagnostics
+	liveComputations map[*computation]struct{}
+	liveOwners       map[*Owner]struct{}
+	maxCleanups      int
 
 	// Focus management (moved from focus.go)
 	focusManager *FocusManager
+
+	// Watchers created by CreateVisibilityEffect that no App has taken yet
+	visibility visibilityTracker
+
+	// MemoComponent caches by parent element, and the Expand call in
+	// progress
+	memoRoot *memoScope
+	expander *expander
+
+	// Callbacks queued during layout, run when the outermost pass ends
+	layoutDepth int
+	layoutQueue []func()
 }
 
 // Global is the package-level runtime instance.
@@ -45,7 +69,9 @@ func init() {
 // NewRuntime creates a new Runtime with initialized state.
 func NewRuntime() *Runtime {
 	rt := &Runtime{
-		pendingComputations: make(map[*computation]struct{}),
+		batches:          make(map[uint64]*batchState),
+		liveComputations: make(map[*computation]struct{}),
+		liveOwners:       make(map[*Owner]struct{}),
 	}
 	// focusManager will be lazily initialized when first accessed
 	return rt
@@ -101,46 +127,240 @@ func (rt *Runtime) setCurrentOwner(owner *Owner) {
 	rt.currentOwner = owner
 }
 
-// getBatchDepth returns the current batch depth.
-func (rt *Runtime) getBatchDepth() int {
-	rt.mu.Lock()
-	defer rt.mu.Unlock()
-	return rt.batchDepth
+// batchState is the batch of one goroutine: effects triggered by its
+// writes are deferred until its outermost Batch returns.
+type batchState struct {
+	depth               int
+	pendingComputations map[*computation]struct{}
+	idleCallbacks       []func()
 }
 
-// incrementBatchDepth increments the batch depth.
-func (rt *Runtime) incrementBatchDepth() {
+// incrementBatchDepth enters a batch on goroutine gid.
+func (rt *Runtime) incrementBatchDepth(gid uint64) {
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
-	rt.batchDepth++
+	b := rt.batches[gid]
+	if b == nil {
+		b = &batchState{}
+		rt.batches[gid] = b
+		rt.activeBatches.Add(1)
+	}
+	b.depth++
 }
 
-// decrementBatchDepth decrements the batch depth and returns true if we should flush.
-func (rt *Runtime) decrementBatchDepth() bool {
+// decrementBatchDepth leaves a batch on goroutine gid. Returns the batch to
+// flush when the outermost batch ends, or nil.
+func (rt *Runtime) decrementBatchDepth(gid uint64) *batchState {
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
-	rt.batchDepth--
-	return rt.batchDepth == 0
+	b := rt.batches[gid]
+	b.depth--
+	if b.depth > 0 {
+		return nil
+	}
+	delete(rt.batches, gid)
+	rt.activeBatches.Add(-1)
+	return b
 }
 
-// addPendingComputation adds a computation to the pending set.
-func (rt *Runtime) addPendingComputation(comp *computation) {
+// addPendingComputations defers comps to the calling goroutine's batch.
+// Returns false if that goroutine has no active batch, in which case the
+// caller runs them itself. Finding the batch and queueing happen under one
+// lock, so comps can't land in a batch that has already been flushed.
+func (rt *Runtime) addPendingComputations(comps []*computation) bool {
+	// Skip the goroutine lookup entirely when no batch is active anywhere
+	if rt.activeBatches.Load() == 0 {
+		return false
+	}
+	gid := goroutineID()
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
-	rt.pendingComputations[comp] = struct{}{}
+	b := rt.batches[gid]
+	if b == nil {
+		return false
+	}
+	if b.pendingComputations == nil {
+		b.pendingComputations = make(map[*computation]struct{})
+	}
+	for _, comp := range comps {
+		b.pendingComputations[comp] = struct{}{}
+	}
+	return true
 }
 
-// flushPending runs all pending computations and clears the set.
-func (rt *Runtime) flushPending() {
+// flushPending runs the batch's pending computations and clears the set.
+func (rt *Runtime) flushPending(b *batchState) {
 	rt.mu.Lock()
-	toRun := make([]*computation, 0, len(rt.pendingComputations))
-	for comp := range rt.pendingComputations {
+	toRun := make([]*computation, 0, len(b.pendingComputations))
+	for comp := range b.pendingComputations {
 		toRun = append(toRun, comp)
 	}
-	rt.pendingComputations = make(map[*computation]struct{})
+	b.pendingComputations = nil
 	rt.mu.Unlock()
 
 	for _, comp := range toRun {
 		comp.execute()
 	}
 }
+
+// deferEffect queues run until owner's CreateRoot callback returns. Outside
+// a root's setup, run is queued with WhenIdle instead.
+func (rt *Runtime) deferEffect(owner *Owner, run func()) {
+	rt.mu.Lock()
+	if owner != nil && owner.settingUp {
+		owner.deferred = append(owner.deferred, run)
+		rt.mu.Unlock()
+		return
+	}
+	rt.mu.Unlock()
+	WhenIdle(run)
+}
+
+// finishSetup ends owner's setup and runs the effects it deferred.
+func (rt *Runtime) finishSetup(owner *Owner) {
+	rt.mu.Lock()
+	owner.settingUp = false
+	deferred := owner.deferred
+	owner.deferred = nil
+	rt.mu.Unlock()
+
+	for _, run := range deferred {
+		run()
+	}
+}
+
+// beginLayout opens a layout pass. Callbacks queued with afterLayout during
+// the pass run when the outermost pass ends, so a renderer that opens one
+// around its frame runs them once the frame is drawn.
+func (rt *Runtime) beginLayout() {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.layoutDepth++
+}
+
+// endLayout closes a layout pass, running the queued callbacks if it was
+// the outermost one.
+func (rt *Runtime) endLayout() {
+	rt.mu.Lock()
+	rt.layoutDepth--
+	if rt.layoutDepth > 0 {
+		rt.mu.Unlock()
+		return
+	}
+	toRun := rt.layoutQueue
+	rt.layoutQueue = nil
+	rt.mu.Unlock()
+
+	for _, fn := range toRun {
+		fn()
+	}
+}
+
+// afterLayout queues fn to run when the current layout pass ends, or runs
+// it now outside one. Layout uses it for user callbacks, which may set
+// signals and so render again.
+func (rt *Runtime) afterLayout(fn func()) {
+	rt.mu.Lock()
+	if rt.layoutDepth > 0 {
+		rt.layoutQueue = append(rt.layoutQueue, fn)
+		rt.mu.Unlock()
+		return
+	}
+	rt.mu.Unlock()
+	fn()
+}
+
+// setExpander makes ex the Expand call in progress and returns the
+// previous one.
+func (rt *Runtime) setExpander(ex *expander) *expander {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	prev := rt.expander
+	rt.expander = ex
+	return prev
+}
+
+// addDisposable registers fn with owner, enforcing the SetMaxCleanups limit.
+func (rt *Runtime) addDisposable(owner *Owner, fn func()) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	owner.disposables = append(owner.disposables, fn)
+	if rt.maxCleanups > 0 && len(owner.disposables) > rt.maxCleanups {
+		panic(fmt.Sprintf("goli: owner has %d cleanup registrations, exceeding SetMaxCleanups(%d)",
+			len(owner.disposables), rt.maxCleanups))
+	}
+}
+
+// trackComputation records comp as live until untrackComputation is called.
+func (rt *Runtime) trackComputation(comp *computation) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.liveComputations[comp] = struct{}{}
+}
+
+// untrackComputation removes comp from the live set.
+func (rt *Runtime) untrackComputation(comp *computation) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	delete(rt.liveComputations, comp)
+}
+
+// trackOwner records a root owner as live until untrackOwner is called.
+func (rt *Runtime) trackOwner(owner *Owner) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.liveOwners[owner] = struct{}{}
+}
+
+// untrackOwner removes owner from the live set.
+func (rt *Runtime) untrackOwner(owner *Owner) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	delete(rt.liveOwners, owner)
+}
+
+// addIdleCallback queues fn to run when the calling goroutine's batch
+// completes. Returns false if no batch is active, in which case fn is not
+// queued.
+func (rt *Runtime) addIdleCallback(fn func()) bool {
+	if rt.activeBatches.Load() == 0 {
+		return false
+	}
+	gid := goroutineID()
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	b := rt.batches[gid]
+	if b == nil {
+		return false
+	}
+	b.idleCallbacks = append(b.idleCallbacks, fn)
+	return true
+}
+
+// flushIdle runs the batch's queued idle callbacks and clears the queue.
+func (rt *Runtime) flushIdle(b *batchState) {
+	rt.mu.Lock()
+	toRun := b.idleCallbacks
+	b.idleCallbacks = nil
+	rt.mu.Unlock()
+
+	for _, fn := range toRun {
+		fn()
+	}
+}
+
+// goroutineID returns the calling goroutine's ID, parsed from the
+// "goroutine N [...]" header of its stack trace. This costs a few
+// microseconds, so callers avoid it when no batch is active.
+func goroutineID() uint64 {
+	var buf [32]byte
+	n := runtime.Stack(buf[:], false)
+	var id uint64
+	for _, c := range buf[len("goroutine "):n] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
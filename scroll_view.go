@@ -0,0 +1,63 @@
+package goli
+
+import "sync"
+
+// ScrollView tracks the content size of a scroll container between renders.
+// The container is the box whose "id" prop matches the view's id.
+//
+// Example:
+//
+//	view := goli.NewScrollView("log")
+//	app.SetLayoutCallback(view.Update)
+//
+//	// In the app's VNode tree:
+//	<box id="log" overflow="scroll" height={5}>...</box>
+//
+//	_, contentHeight := view.ContentSize()
+type ScrollView struct {
+	id string
+
+	mu            sync.RWMutex
+	contentWidth  int
+	contentHeight int
+}
+
+// NewScrollView creates a ScrollView for the box with the given id.
+func NewScrollView(id string) *ScrollView {
+	return &ScrollView{id: id}
+}
+
+// Update records the content size of the view's box in a layout tree.
+// The size is left unchanged if the box is not found.
+func (s *ScrollView) Update(root *LayoutBox) {
+	box := findBoxByID(root, s.id)
+	if box == nil {
+		return
+	}
+	s.mu.Lock()
+	s.contentWidth, s.contentHeight = box.ScrollWidth, box.ScrollHeight
+	s.mu.Unlock()
+}
+
+// ContentSize returns the full content size from the last Update.
+func (s *ScrollView) ContentSize() (w, h int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.contentWidth, s.contentHeight
+}
+
+// findBoxByID returns the first box in the tree whose "id" prop is id.
+func findBoxByID(box *LayoutBox, id string) *LayoutBox {
+	if box == nil {
+		return nil
+	}
+	if GetStringProp(box.Node.Props, "id", "") == id {
+		return box
+	}
+	for _, child := range box.Children {
+		if found := findBoxByID(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -11,10 +11,19 @@ type SelectOptions[T comparable] struct {
 	InitialValue T
 	// OnChange is called when selection changes.
 	OnChange func(value T)
+	// Value, when set, is written the selected value on every change (before
+	// OnChange, once the frame showing the change is drawn), and its value at
+	// creation is the initial selection in place of InitialValue. Later changes to it made elsewhere don't move the
+	// selection; use SetIndex for that.
+	Value ReadWrite[T]
 	// OnKeypress is a custom key handler (called before default handling).
 	OnKeypress func(key string) bool
 	// DisableFocus disables focus management registration (default: false, meaning focusable by default).
 	DisableFocus bool
+	// OnFocus is called when the select gains focus.
+	OnFocus func()
+	// OnBlur is called when the select loses focus.
+	OnBlur func()
 }
 
 // Select represents a list selection component.
@@ -33,10 +42,14 @@ type Select[T comparable] struct {
 	initialValue    T
 	hasInitialValue bool
 	initialApplied  bool
-	initialIndex    int // The index to use when initial value is found
+	initialIndex    int  // The index to use when initial value is found
+	pendingChange   bool // OnChange is due once layout registers the options
+	scrollOffset    int  // First visible option when the select has maxHeight
 
 	onChange       func(value T)
 	onKeypress     func(key string) bool
+	onFocus        func()
+	onBlur         func()
 	shouldRegister bool
 	registered     bool
 }
@@ -51,8 +64,19 @@ func NewSelect[T comparable](opts SelectOptions[T]) *Select[T] {
 		shouldRegister = false
 	}
 
+	initialValue, onChange := opts.InitialValue, opts.OnChange
+	if bound := opts.Value; bound.Get != nil && bound.Set != nil {
+		initialValue = Untrack(bound.Get)
+		onChange = func(value T) {
+			bound.Set(value)
+			if opts.OnChange != nil {
+				opts.OnChange(value)
+			}
+		}
+	}
+
 	var zero T
-	hasInitial := opts.InitialValue != zero
+	hasInitial := initialValue != zero
 
 	s := &Select[T]{
 		selectedIndex:   selectedIndex,
@@ -60,10 +84,12 @@ func NewSelect[T comparable](opts SelectOptions[T]) *Select[T] {
 		focused:         focused,
 		setFocused:      setFocused,
 		optionValues:    make(map[int]T),
-		initialValue:    opts.InitialValue,
+		initialValue:    initialValue,
 		hasInitialValue: hasInitial,
-		onChange:        opts.OnChange,
+		onChange:        onChange,
 		onKeypress:      opts.OnKeypress,
+		onFocus:         opts.OnFocus,
+		onBlur:          opts.OnBlur,
 		shouldRegister:  shouldRegister,
 	}
 
@@ -104,7 +130,14 @@ func (s *Select[T]) SelectedIndex() int {
 }
 
 // IsSelectedIndex returns true if the given index is selected.
+// Indexes beyond the current option count are never selected.
 func (s *Select[T]) IsSelectedIndex(index int) bool {
+	s.mu.RLock()
+	count := s.optionCount
+	s.mu.RUnlock()
+	if index >= count {
+		return false
+	}
 	return s.SelectedIndex() == index
 }
 
@@ -135,11 +168,48 @@ func (s *Select[T]) RegisterOptionAny(index int, value any) {
 }
 
 // SetOptionCount sets the option count (called during layout).
-// This does NOT trigger re-renders.
+// If the options shrank past the selection, the index is clamped to the
+// last option without calling OnChange.
 func (s *Select[T]) SetOptionCount(count int) {
 	s.mu.Lock()
 	s.optionCount = count
+	if s.initialIndex >= count {
+		s.initialIndex = 0
+	}
 	s.mu.Unlock()
+
+	if current := Untrack(s.selectedIndex); current >= count {
+		s.setIndex(max(count-1, 0))
+	}
+}
+
+// Max returns the index of the last option, or -1 if there are none.
+func (s *Select[T]) Max() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.optionCount - 1
+}
+
+// ScrollOffset returns the index of the first visible option. It is only
+// non-zero for selects with a maxHeight prop smaller than their options.
+func (s *Select[T]) ScrollOffset() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.scrollOffset
+}
+
+// scrollIntoView adjusts the scroll offset so the selected option is within
+// a window of visible options (called during layout).
+func (s *Select[T]) scrollIntoView(visible int) {
+	selected := Untrack(s.SelectedIndex)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if selected < s.scrollOffset {
+		s.scrollOffset = selected
+	} else if selected >= s.scrollOffset+visible {
+		s.scrollOffset = selected - visible + 1
+	}
+	s.scrollOffset = max(0, min(s.scrollOffset, s.optionCount-visible))
 }
 
 // ClearOptions clears registered options (called during layout).
@@ -154,20 +224,54 @@ func (s *Select[T]) SetIndex(index int) {
 	if index < 0 {
 		index = 0
 	}
-	s.setIndex(index)
+	s.changeIndex(index)
 }
 
 // Next selects the next option.
 func (s *Select[T]) Next() {
 	current := s.selectedIndex()
-	s.setIndex(current + 1)
+	s.mu.RLock()
+	count := s.optionCount
+	s.mu.RUnlock()
+	if current+1 < count {
+		s.changeIndex(current + 1)
+	}
 }
 
 // Prev selects the previous option.
 func (s *Select[T]) Prev() {
 	current := s.selectedIndex()
 	if current > 0 {
-		s.setIndex(current - 1)
+		s.changeIndex(current - 1)
+	}
+}
+
+// changeIndex sets the selected index and, if it changed, queues OnChange.
+// The call is deferred to flushPendingChange because option values are only
+// known after the next layout.
+func (s *Select[T]) changeIndex(index int) {
+	if Untrack(s.selectedIndex) == index {
+		return
+	}
+	if s.onChange != nil {
+		s.mu.Lock()
+		s.pendingChange = true
+		s.mu.Unlock()
+	}
+	s.setIndex(index)
+}
+
+// flushPendingChange fires a queued OnChange with the selected value.
+// Called by layout after the options are registered; OnChange itself runs
+// once the frame is drawn, since it may set signals that render again.
+func (s *Select[T]) flushPendingChange() {
+	s.mu.Lock()
+	pending := s.pendingChange
+	s.pendingChange = false
+	s.mu.Unlock()
+	if pending {
+		value := Untrack(s.Value)
+		Global.afterLayout(func() { s.onChange(value) })
 	}
 }
 
@@ -183,7 +287,9 @@ func (s *Select[T]) Blur() {
 
 // SetFocused sets the focused state (called by focus manager).
 func (s *Select[T]) SetFocused(f bool) {
+	wasFocused := Untrack(func() bool { return s.focused() })
 	s.setFocused(f)
+	notifyFocusChange(wasFocused, f, s.onFocus, s.onBlur)
 }
 
 // Dispose unregisters from the focus manager.
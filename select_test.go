@@ -0,0 +1,229 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+func TestSelect_SetOptionCountClampsIndex(t *testing.T) {
+	setupTest(t)
+
+	changes := 0
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true, OnChange: func(string) { changes++ }})
+	sel.SetOptionCount(5)
+	sel.SetIndex(4)
+
+	sel.SetOptionCount(3)
+	if got := sel.SelectedIndex(); got != 2 {
+		t.Errorf("expected index clamped to 2, got %d", got)
+	}
+	if got := sel.Max(); got != 2 {
+		t.Errorf("expected Max 2, got %d", got)
+	}
+	if changes != 0 {
+		t.Errorf("expected no OnChange calls, got %d", changes)
+	}
+}
+
+func TestSelect_NextStopsAtLastOption(t *testing.T) {
+	setupTest(t)
+
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true})
+	sel.SetOptionCount(3)
+	for i := 0; i < 5; i++ {
+		sel.Next()
+	}
+	if got := sel.SelectedIndex(); got != 2 {
+		t.Errorf("expected index 2, got %d", got)
+	}
+}
+
+func TestSelect_IsSelectedIndexBeyondOptionCount(t *testing.T) {
+	setupTest(t)
+
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true})
+	sel.SetIndex(4)
+	sel.SetOptionCount(5)
+	if !sel.IsSelectedIndex(4) {
+		t.Error("expected index 4 selected")
+	}
+
+	sel.SetOptionCount(3)
+	if sel.IsSelectedIndex(4) {
+		t.Error("expected index 4 not selected beyond option count")
+	}
+}
+
+func selectNode(sel *Select[string]) gox.VNode {
+	return gox.Element("select", gox.Props{"select": sel},
+		gox.Element("option", gox.Props{"value": "a"}, gox.Text("A")),
+		gox.Element("option", gox.Props{"value": "b"}, gox.Text("B")),
+		gox.Element("option", gox.Props{"value": "c"}, gox.Text("C")),
+	)
+}
+
+func TestSelect_OnChangeFiresAfterLayout(t *testing.T) {
+	setupTest(t)
+
+	var changes []string
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true, OnChange: func(v string) { changes = append(changes, v) }})
+	ComputeLayout(selectNode(sel), LayoutContext{Width: 10, Height: 3})
+
+	sel.SetIndex(2)
+	if len(changes) != 0 {
+		t.Errorf("expected OnChange deferred until layout, got %v", changes)
+	}
+
+	ComputeLayout(selectNode(sel), LayoutContext{Width: 10, Height: 3})
+	if len(changes) != 1 || changes[0] != "c" {
+		t.Errorf("expected [c] after layout, got %v", changes)
+	}
+
+	// Setting the same index again is not a change
+	sel.SetIndex(2)
+	ComputeLayout(selectNode(sel), LayoutContext{Width: 10, Height: 3})
+	if len(changes) != 1 {
+		t.Errorf("expected no further OnChange, got %v", changes)
+	}
+}
+
+func TestSelect_OnChangeFromKeys(t *testing.T) {
+	setupTest(t)
+
+	var changes []string
+	sel := NewSelect(SelectOptions[string]{OnChange: func(v string) { changes = append(changes, v) }})
+	defer sel.Dispose()
+	sel.Focus()
+
+	var output strings.Builder
+	app := Render(func() gox.VNode { return selectNode(sel) },
+		Options{Width: 10, Height: 3, Output: &output, DisableThrottle: true})
+	defer app.Dispose()
+
+	HandleKey(Down)
+	HandleKey(Down)
+	HandleKey(Up)
+	if len(changes) != 3 || changes[0] != "b" || changes[1] != "c" || changes[2] != "b" {
+		t.Errorf("expected [b c b], got %v", changes)
+	}
+}
+
+func TestSelect_OnChangeSetsSignalTheAppReads(t *testing.T) {
+	setupTest(t)
+
+	picked, setPicked := CreateSignal("none")
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true, OnChange: setPicked})
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("text", nil, gox.Text("picked: "+picked())),
+			selectNode(sel),
+		)
+	}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+	app.WaitForRender(time.Second)
+
+	sel.Next()
+	app.WaitForRender(time.Second)
+	app.Headless().AssertContains(t, "picked: b")
+}
+
+func scrollingSelectNode(sel *Select[string]) gox.VNode {
+	options := make([]gox.VNode, 6)
+	for i := range options {
+		label := string(rune('A' + i))
+		options[i] = gox.Element("option", gox.Props{"value": strings.ToLower(label)}, gox.Text(label))
+	}
+	return gox.Element("select", gox.Props{"select": sel, "maxHeight": 3}, options...)
+}
+
+func TestSelect_MaxHeightClampsMeasure(t *testing.T) {
+	setupTest(t)
+
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true})
+	if _, h := measureNode(scrollingSelectNode(sel)); h != 3 {
+		t.Errorf("expected height clamped to maxHeight 3, got %d", h)
+	}
+}
+
+func TestSelect_ScrollsSelectionIntoView(t *testing.T) {
+	setupTest(t)
+
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true})
+	app := Render(func() gox.VNode { return scrollingSelectNode(sel) },
+		Options{Width: 10, Height: 4, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+	h := app.Headless()
+
+	if got := h.PlainOutput(); got != "  A\n  B\n ▼C\n" {
+		t.Errorf("expected first window with ▼ indicator, got %q", got)
+	}
+
+	sel.SetIndex(4)
+	app.Rerender()
+	if got := sel.ScrollOffset(); got != 2 {
+		t.Errorf("expected scroll offset 2 to show index 4, got %d", got)
+	}
+	if got := h.PlainOutput(); got != " ▲C\n  D\n ▼E\n" {
+		t.Errorf("expected middle window with both indicators, got %q", got)
+	}
+
+	sel.SetIndex(5)
+	app.Rerender()
+	if got := h.PlainOutput(); got != " ▲D\n  E\n  F\n" {
+		t.Errorf("expected last window with ▲ indicator, got %q", got)
+	}
+
+	sel.SetIndex(0)
+	app.Rerender()
+	if got := sel.ScrollOffset(); got != 0 {
+		t.Errorf("expected scrolling back up to offset 0, got %d", got)
+	}
+}
+
+func TestSelect_ValueBinding(t *testing.T) {
+	setupTest(t)
+
+	choice := NewReadWrite("b")
+	var changes []string
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true, Value: choice, OnChange: func(v string) {
+		if choice.Value() != v {
+			t.Errorf("expected Value set before OnChange, got %q for %q", choice.Value(), v)
+		}
+		changes = append(changes, v)
+	}})
+	ComputeLayout(selectNode(sel), LayoutContext{Width: 10, Height: 3})
+	if sel.SelectedIndex() != 1 {
+		t.Errorf("expected the bound value to select index 1, got %d", sel.SelectedIndex())
+	}
+
+	sel.SetIndex(2)
+	ComputeLayout(selectNode(sel), LayoutContext{Width: 10, Height: 3})
+	if choice.Value() != "c" || len(changes) != 1 {
+		t.Errorf("expected c written to the binding and one OnChange, got %q and %v", choice.Value(), changes)
+	}
+}
+
+func TestSelect_ValueBindingInApp(t *testing.T) {
+	setupTest(t)
+
+	choice := NewReadWrite("a")
+	sel := NewSelect(SelectOptions[string]{DisableFocus: true, Value: choice})
+	app := Render(func() gox.VNode {
+		return gox.Element("box", nil,
+			gox.Element("text", nil, gox.Text("choice: "+choice.Get())),
+			selectNode(sel),
+		)
+	}, Options{Width: 20, Height: 5, Headless: true, DisableThrottle: true})
+	defer app.Dispose()
+	app.WaitForRender(time.Second)
+
+	sel.Next()
+	app.WaitForRender(time.Second)
+	if choice.Value() != "b" {
+		t.Errorf("expected b written to the binding, got %q", choice.Value())
+	}
+	app.Headless().AssertContains(t, "choice: b")
+}
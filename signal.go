@@ -33,6 +33,39 @@ func (s *signalValue[T]) unsubscribe(comp *computation) {
 	s.mu.Unlock()
 }
 
+// subscriberCount returns the number of computations subscribed.
+func (s *signalValue[T]) subscriberCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subscribers)
+}
+
+// read returns the current value and, if comp is non-nil, subscribes comp
+// to this signal. The value is read and the subscription recorded under a
+// single lock so a concurrent write can't slip in between.
+func (s *signalValue[T]) read(comp *computation) T {
+	if comp == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return s.value
+	}
+
+	s.mu.Lock()
+	val := s.value
+	_, subscribed := s.subscribers[comp]
+	s.subscribers[comp] = struct{}{}
+	s.mu.Unlock()
+
+	// Store subscription for cleanup (fixes memory leak)
+	if !subscribed {
+		comp.mu.Lock()
+		comp.subscriptions = append(comp.subscriptions, s)
+		comp.mu.Unlock()
+	}
+
+	return val
+}
+
 // CreateSignal creates a reactive signal.
 //
 // Example:
@@ -48,30 +81,20 @@ func CreateSignal[T any](initialValue T) (Accessor[T], Setter[T]) {
 // createSignalInternal creates a signal using the given runtime.
 // This is used internally to avoid circular initialization.
 func createSignalInternal[T any](rt *Runtime, initialValue T) (Accessor[T], Setter[T]) {
+	_, read, write := createSignalValue(rt, initialValue)
+	return read, write
+}
+
+// createSignalValue is createSignalInternal that also returns the signal,
+// for callers that need to know whether it has subscribers.
+func createSignalValue[T any](rt *Runtime, initialValue T) (*signalValue[T], Accessor[T], Setter[T]) {
 	s := &signalValue[T]{
 		value:       initialValue,
 		subscribers: make(map[*computation]struct{}),
 	}
 
 	read := func() T {
-		s.mu.RLock()
-		val := s.value
-		s.mu.RUnlock()
-
-		// Track this signal as a dependency of current computation
-		comp := rt.getCurrentComputation()
-		if comp != nil {
-			s.mu.Lock()
-			s.subscribers[comp] = struct{}{}
-			s.mu.Unlock()
-
-			// Store subscription for cleanup (fixes memory leak)
-			comp.mu.Lock()
-			comp.subscriptions = append(comp.subscriptions, s)
-			comp.mu.Unlock()
-		}
-
-		return val
+		return s.read(rt.getCurrentComputation())
 	}
 
 	write := func(newValue T) {
@@ -86,20 +109,14 @@ func createSignalInternal[T any](rt *Runtime, initialValue T) (Accessor[T], Sett
 		s.mu.Unlock()
 
 		// Notify subscribers
-		inBatch := rt.getBatchDepth() > 0
-
-		if inBatch {
-			for _, comp := range subs {
-				rt.addPendingComputation(comp)
-			}
-		} else {
+		if !rt.addPendingComputations(subs) {
 			for _, comp := range subs {
 				comp.execute()
 			}
 		}
 	}
 
-	return read, write
+	return s, read, write
 }
 
 // CreateSignalWithEquals creates a signal with a custom equality function.
@@ -112,23 +129,7 @@ func CreateSignalWithEquals[T any](initialValue T, equals func(a, b T) bool) (Ac
 	}
 
 	read := func() T {
-		s.mu.RLock()
-		val := s.value
-		s.mu.RUnlock()
-
-		comp := Global.getCurrentComputation()
-		if comp != nil {
-			s.mu.Lock()
-			s.subscribers[comp] = struct{}{}
-			s.mu.Unlock()
-
-			// Store subscription for cleanup (fixes memory leak)
-			comp.mu.Lock()
-			comp.subscriptions = append(comp.subscriptions, s)
-			comp.mu.Unlock()
-		}
-
-		return val
+		return s.read(Global.getCurrentComputation())
 	}
 
 	write := func(newValue T) {
@@ -145,13 +146,7 @@ func CreateSignalWithEquals[T any](initialValue T, equals func(a, b T) bool) (Ac
 		}
 		s.mu.Unlock()
 
-		inBatch := Global.getBatchDepth() > 0
-
-		if inBatch {
-			for _, comp := range subs {
-				Global.addPendingComputation(comp)
-			}
-		} else {
+		if !Global.addPendingComputations(subs) {
 			for _, comp := range subs {
 				comp.execute()
 			}
@@ -161,7 +156,101 @@ func CreateSignalWithEquals[T any](initialValue T, equals func(a, b T) bool) (Ac
 	return read, write
 }
 
+// CreateSignalPair adapts a getter/setter pair into a signal, e.g. to bind
+// a field of a struct signal to a child component expecting
+// (Accessor[T], Setter[T]). The returned accessor follows getter
+// reactively, so changes made elsewhere are reflected; writes go through
+// setter.
+//
+// Example:
+//
+//	form, setForm := CreateSignal(Form{Name: "goli"})
+//	name, setName := CreateSignalPair(
+//	    func() string { return form().Name },
+//	    func(v string) { f := form(); f.Name = v; setForm(f) },
+//	)
+//	setName("gox")
+//	fmt.Println(form().Name) // gox
+func CreateSignalPair[T any](getter Accessor[T], setter Setter[T]) (Accessor[T], Setter[T]) {
+	value := CreateMemo(getter)
+	write := func(newValue T) {
+		setter(newValue)
+	}
+	return value, write
+}
+
+// ReadWrite bundles an accessor and setter into one value, for two-way
+// bindings such as InputOptions.Value.
+type ReadWrite[T any] struct {
+	Get Accessor[T]
+	Set Setter[T]
+}
+
+// NewReadWrite creates a signal and returns it as a ReadWrite.
+func NewReadWrite[T any](initial T) ReadWrite[T] {
+	get, set := CreateSignal(initial)
+	return ReadWrite[T]{Get: get, Set: set}
+}
+
+// Value reads the bound value, tracking it like Get.
+func (rw ReadWrite[T]) Value() T {
+	return rw.Get()
+}
+
+// Update writes v through Set.
+func (rw ReadWrite[T]) Update(v T) {
+	rw.Set(v)
+}
+
+// MapReadWrite derives a two-way binding from rw: reads return getter of
+// rw's value, and writes store setter(current value, written value) back
+// into rw. (Go methods can't have type parameters, so this isn't a method.)
+//
+// Example:
+//
+//	form := NewReadWrite(Form{Age: 30})
+//	age := MapReadWrite(form,
+//	    func(f Form) string { return strconv.Itoa(f.Age) },
+//	    func(f Form, s string) Form { f.Age, _ = strconv.Atoi(s); return f },
+//	)
+//	input := NewInput(InputOptions{Value: age})
+func MapReadWrite[T, U any](rw ReadWrite[T], getter func(T) U, setter func(T, U) T) ReadWrite[U] {
+	return ReadWrite[U]{
+		Get: func() U { return getter(rw.Get()) },
+		Set: func(v U) { rw.Set(setter(Untrack(rw.Get), v)) },
+	}
+}
+
 // SetWith updates a signal using a function that receives the previous value.
 func SetWith[T any](setter Setter[T], fn SetterFunc[T], getter Accessor[T]) {
 	setter(fn(getter()))
 }
+
+// SetAtIndex updates a single element of a slice signal in place.
+// The slice is read, fn is applied to the element at index, and the same
+// slice is written back, so no new backing array is allocated.
+// Out-of-range indices are ignored and do not notify subscribers.
+//
+// Example:
+//
+//	items, setItems := CreateSignal([]int{1, 2, 3})
+//	SetAtIndex(setItems, 1, func(v int) int { return v * 10 }, items)
+//	fmt.Println(items()) // [1 20 3]
+func SetAtIndex[T any](setter Setter[[]T], index int, fn func(T) T, getter Accessor[[]T]) {
+	items := getter()
+	if index < 0 || index >= len(items) {
+		return
+	}
+	items[index] = fn(items[index])
+	setter(items)
+}
+
+// SetAtIndexBatch applies several indexed updates to a slice signal inside
+// a single batch, so subscribers are notified once.
+func SetAtIndexBatch[T any](setter Setter[[]T], updates map[int]func(T) T, getter Accessor[[]T]) {
+	BatchVoid(func() {
+		for index, fn := range updates {
+			SetAtIndex(setter, index, fn, getter)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package goli
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreateSignal_ConcurrentReadWrite(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	var effectRuns atomic.Int64
+
+	dispose := CreateEffect(func() CleanupFunc {
+		_ = count()
+		effectRuns.Add(1)
+		return nil
+	})
+	defer dispose()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				_ = count()
+				setCount(g*1000 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if effectRuns.Load() < 2 {
+		t.Errorf("expected effect to re-run on writes, got %d runs", effectRuns.Load())
+	}
+}
+
+func TestCreateSignalWithEquals_ConcurrentReadWrite(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignalWithEquals(0, func(a, b int) bool { return a == b })
+	var effectRuns atomic.Int64
+
+	dispose := CreateEffect(func() CleanupFunc {
+		_ = count()
+		effectRuns.Add(1)
+		return nil
+	})
+	defer dispose()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				setCount(count() + 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if effectRuns.Load() < 2 {
+		t.Errorf("expected effect to re-run on writes, got %d runs", effectRuns.Load())
+	}
+}
+
+func TestCreateSignal_RepeatedReadsSubscribeOnce(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	effectRuns := 0
+
+	CreateEffect(func() CleanupFunc {
+		for i := 0; i < 10; i++ {
+			_ = count()
+		}
+		effectRuns++
+		return nil
+	})
+
+	setCount(1)
+	if effectRuns != 2 {
+		t.Errorf("expected 2 effect runs, got %d", effectRuns)
+	}
+}
+
+func TestBatch_OtherGoroutineWritesNotDeferred(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	var effectRuns atomic.Int64
+	CreateEffect(func() CleanupFunc {
+		_ = count()
+		effectRuns.Add(1)
+		return nil
+	})
+
+	inBatch := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		BatchVoid(func() {
+			close(inBatch)
+			<-release
+		})
+	}()
+	<-inBatch
+
+	setCount(1)
+	if effectRuns.Load() != 2 {
+		t.Errorf("expected write outside the other goroutine's batch to run the effect, got %d runs", effectRuns.Load())
+	}
+	close(release)
+	<-done
+	if effectRuns.Load() != 2 {
+		t.Errorf("expected the other goroutine's empty batch not to rerun the effect, got %d runs", effectRuns.Load())
+	}
+}
+
+func TestBatch_EndingDoesNotFlushOtherGoroutine(t *testing.T) {
+	Reset()
+	count, setCount := CreateSignal(0)
+	var effectRuns atomic.Int64
+	CreateEffect(func() CleanupFunc {
+		_ = count()
+		effectRuns.Add(1)
+		return nil
+	})
+
+	written := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		BatchVoid(func() {
+			setCount(1)
+			close(written)
+			<-release
+		})
+	}()
+	<-written
+
+	BatchVoid(func() {})
+	if effectRuns.Load() != 1 {
+		t.Errorf("expected pending effect to wait for its own batch, got %d runs", effectRuns.Load())
+	}
+	close(release)
+	<-done
+	if effectRuns.Load() != 2 {
+		t.Errorf("expected effect to run when its batch ends, got %d runs", effectRuns.Load())
+	}
+}
+
+func BenchmarkSignalWrite_NoBatch(b *testing.B) {
+	Reset()
+	_, setCount := CreateSignal(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		setCount(i)
+	}
+}
+
+func BenchmarkBatchVoid_SingleWrite(b *testing.B) {
+	Reset()
+	_, setCount := CreateSignal(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVoid(func() { setCount(i) })
+	}
+}
+
+func BenchmarkSignalRead_Untracked(b *testing.B) {
+	Reset()
+	count, _ := CreateSignal(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = count()
+	}
+}
+
+func BenchmarkSignalRead_Tracked(b *testing.B) {
+	Reset()
+	count, _ := CreateSignal(0)
+	comp := &computation{execute: func() {}}
+	Global.setCurrentComputation(comp)
+	defer Global.setCurrentComputation(nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = count()
+	}
+}
@@ -11,7 +11,7 @@ func init() {
 	})
 }
 
-func measureSpacer(node gox.VNode, ctx *LayoutContext) (int, int) {
+func measureSpacer(node gox.VNode, ctx LayoutContext) (int, int) {
 	w := GetIntProp(node.Props, "width", 0)
 	h := GetIntProp(node.Props, "height", 0)
 	return w, h
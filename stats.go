@@ -0,0 +1,102 @@
+package goli
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+// RenderStats holds per-frame instrumentation collected by a renderer.
+// Enable collection with CollectStats(true) and read it with LastStats().
+type RenderStats struct {
+	LayoutDuration time.Duration // VNode → LayoutBox
+	BufferDuration time.Duration // LayoutBox → logical/visual buffers
+	DiffDuration   time.Duration // Buffer diff → ANSI string
+	OutputDuration time.Duration // ANSI string → io.Writer
+	CellsChanged   int
+	RunsEmitted    int
+	BytesWritten   int
+	Skipped        bool // Content matched the previous frame, so no diff ran
+}
+
+// StatsProvider is implemented by renderers that can collect RenderStats.
+// Both Renderer and PipelineRenderer implement it.
+type StatsProvider interface {
+	CollectStats(enable bool)
+	LastStats() RenderStats
+}
+
+// Total returns the sum of all stage durations.
+func (s RenderStats) Total() time.Duration {
+	return s.LayoutDuration + s.BufferDuration + s.DiffDuration + s.OutputDuration
+}
+
+// Percentiles summarizes a distribution of durations.
+type Percentiles struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// BenchReport is the result of RenderBenchmark.
+type BenchReport struct {
+	Frames       int
+	Total        Percentiles
+	Layout       Percentiles
+	Buffer       Percentiles
+	Diff         Percentiles
+	Output       Percentiles
+	BytesWritten int // Summed over all frames
+}
+
+// RenderBenchmark renders appFn for the given number of frames using a
+// sequential renderer and returns percentile timings for each stage.
+// Output defaults to io.Discard so the terminal is left untouched.
+//
+// Example:
+//
+//	report := goli.RenderBenchmark(App, goli.Options{Width: 80, Height: 24}, 100)
+//	fmt.Println("p99 frame:", report.Total.P99)
+func RenderBenchmark(appFn func() gox.VNode, opts Options, frames int) BenchReport {
+	if opts.Output == nil {
+		opts.Output = io.Discard
+	}
+	r := NewRenderer(opts)
+	r.CollectStats(true)
+
+	samples := make([]RenderStats, 0, frames)
+	report := BenchReport{Frames: frames}
+	for i := 0; i < frames; i++ {
+		r.Render(appFn())
+		stats := r.LastStats()
+		samples = append(samples, stats)
+		report.BytesWritten += stats.BytesWritten
+	}
+
+	report.Total = percentilesOf(samples, RenderStats.Total)
+	report.Layout = percentilesOf(samples, func(s RenderStats) time.Duration { return s.LayoutDuration })
+	report.Buffer = percentilesOf(samples, func(s RenderStats) time.Duration { return s.BufferDuration })
+	report.Diff = percentilesOf(samples, func(s RenderStats) time.Duration { return s.DiffDuration })
+	report.Output = percentilesOf(samples, func(s RenderStats) time.Duration { return s.OutputDuration })
+	return report
+}
+
+// percentilesOf computes percentiles of one duration field across samples.
+func percentilesOf(samples []RenderStats, field func(RenderStats) time.Duration) Percentiles {
+	if len(samples) == 0 {
+		return Percentiles{}
+	}
+	durations := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		durations[i] = field(s)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	at := func(p int) time.Duration {
+		return durations[(len(durations)-1)*p/100]
+	}
+	return Percentiles{P50: at(50), P90: at(90), P99: at(99), Max: durations[len(durations)-1]}
+}
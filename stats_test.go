@@ -0,0 +1,88 @@
+package goli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germtb/gox"
+)
+
+func statsNode() gox.VNode {
+	return gox.Element("box", gox.Props{"width": 10, "height": 2},
+		gox.Element("text", nil, gox.Text("hello")),
+	)
+}
+
+func TestRenderStats_ZeroWhenDisabled(t *testing.T) {
+	var out strings.Builder
+	r := NewRenderer(Options{Width: 20, Height: 5, Output: &out})
+	r.CollectStats(false)
+	r.Render(statsNode())
+
+	if r.LastStats() != (RenderStats{}) {
+		t.Errorf("expected zero stats, got %+v", r.LastStats())
+	}
+}
+
+func TestRenderStats_RecordedWhenEnabled(t *testing.T) {
+	var out strings.Builder
+	r := NewRenderer(Options{Width: 20, Height: 5, Output: &out})
+	r.CollectStats(true)
+	r.Render(statsNode())
+
+	stats := r.LastStats()
+	if stats.CellsChanged == 0 {
+		t.Error("expected CellsChanged > 0")
+	}
+	if stats.RunsEmitted == 0 {
+		t.Error("expected RunsEmitted > 0")
+	}
+	if stats.BytesWritten != out.Len() {
+		t.Errorf("expected BytesWritten %d, got %d", out.Len(), stats.BytesWritten)
+	}
+	if stats.Total() <= 0 {
+		t.Error("expected non-zero total duration")
+	}
+
+	// Identical frame: nothing changes
+	r.Render(statsNode())
+	if got := r.LastStats().CellsChanged; got != 0 {
+		t.Errorf("expected 0 cells changed on identical frame, got %d", got)
+	}
+}
+
+func TestRenderStats_Pipeline(t *testing.T) {
+	var out strings.Builder
+	p := NewPipeline(Options{Width: 20, Height: 5, Output: &out})
+	p.CollectStats(true)
+	p.RenderBlocking(statsNode())
+	defer p.Stop()
+
+	// Stages run asynchronously; wait for the frame to reach output
+	deadline := time.Now().Add(time.Second)
+	for p.LastStats().BytesWritten == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	stats := p.LastStats()
+	if stats.LayoutDuration <= 0 {
+		t.Error("expected layout duration to be recorded")
+	}
+	if stats.CellsChanged == 0 {
+		t.Error("expected CellsChanged > 0")
+	}
+}
+
+func TestRenderBenchmark(t *testing.T) {
+	report := RenderBenchmark(statsNode, Options{Width: 20, Height: 5}, 10)
+	if report.Frames != 10 {
+		t.Errorf("expected 10 frames, got %d", report.Frames)
+	}
+	if report.Total.Max <= 0 || report.Total.P50 > report.Total.Max {
+		t.Errorf("unexpected percentiles: %+v", report.Total)
+	}
+	if report.BytesWritten == 0 {
+		t.Error("expected bytes to be written")
+	}
+}
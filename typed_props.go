@@ -0,0 +1,120 @@
+package goli
+
+import "github.com/germtb/gox"
+
+// BoxProps are the props of a box element as a struct, so key typos and
+// wrong value types are caught by the compiler. Convert with ToProps or
+// build the element with NewBox.
+//
+// Example:
+//
+//	goli.NewBox(goli.BoxProps{
+//	    Direction: goli.Row,
+//	    Border:    goli.BorderRounded,
+//	    Padding:   goli.Spacing{Left: 1, Right: 1},
+//	}, children...)
+type BoxProps struct {
+	ID          string
+	Direction   Direction
+	Justify     Justify
+	Align       Align
+	Gap         int
+	RowGap      int
+	ColumnGap   int
+	FlexWrap    bool
+	Padding     Spacing
+	Margin      Spacing
+	Width       int
+	Height      int
+	MinWidth    int
+	MaxWidth    int
+	MinHeight   int
+	MaxHeight   int
+	AspectRatio float64
+	Grow        int
+	Border      BorderStyle
+	Overflow    Overflow
+	Position    Position
+	X, Y        int
+	ZIndex      int
+	Style       Style
+	OnKey       func(key string) bool
+	Active      bool
+}
+
+// ToProps converts p to gox.Props, omitting zero-value fields, so an
+// explicit zero (e.g. a width of 0) needs a props map instead.
+func (p BoxProps) ToProps() gox.Props {
+	props := gox.Props{}
+	setProp(props, "id", p.ID)
+	setProp(props, "direction", p.Direction)
+	setProp(props, "justify", p.Justify)
+	setProp(props, "align", p.Align)
+	setProp(props, "gap", p.Gap)
+	setProp(props, "rowGap", p.RowGap)
+	setProp(props, "columnGap", p.ColumnGap)
+	if p.FlexWrap {
+		props["flexWrap"] = "wrap"
+	}
+	setProp(props, "padding", p.Padding)
+	setProp(props, "margin", p.Margin)
+	setProp(props, "width", p.Width)
+	setProp(props, "height", p.Height)
+	setProp(props, "minWidth", p.MinWidth)
+	setProp(props, "maxWidth", p.MaxWidth)
+	setProp(props, "minHeight", p.MinHeight)
+	setProp(props, "maxHeight", p.MaxHeight)
+	setProp(props, "aspectRatio", p.AspectRatio)
+	setProp(props, "grow", p.Grow)
+	setProp(props, "border", p.Border)
+	setProp(props, "overflow", p.Overflow)
+	setProp(props, "position", p.Position)
+	setProp(props, "x", p.X)
+	setProp(props, "y", p.Y)
+	setProp(props, "zIndex", p.ZIndex)
+	setProp(props, "style", p.Style)
+	setProp(props, "active", p.Active)
+	if p.OnKey != nil {
+		props["onKey"] = p.OnKey
+	}
+	return props
+}
+
+// TextProps are the props of a text element as a struct. Convert with
+// ToProps or build the element with NewText.
+type TextProps struct {
+	Style        Style
+	Wrap         bool
+	MaxLines     int
+	TruncateChar string
+	ZIndex       int
+}
+
+// ToProps converts p to gox.Props, omitting zero-value fields.
+func (p TextProps) ToProps() gox.Props {
+	props := gox.Props{}
+	setProp(props, "style", p.Style)
+	setProp(props, "wrap", p.Wrap)
+	setProp(props, "maxLines", p.MaxLines)
+	setProp(props, "truncateChar", p.TruncateChar)
+	setProp(props, "zIndex", p.ZIndex)
+	return props
+}
+
+// NewBox returns a box element with the given props and children.
+func NewBox(props BoxProps, children ...gox.VNode) gox.VNode {
+	return gox.Element("box", props.ToProps(), children...)
+}
+
+// NewText returns a text element with the given props and content.
+func NewText(props TextProps, text string) gox.VNode {
+	return gox.Element("text", props.ToProps(), gox.Text(text))
+}
+
+// setProp sets props[key] to value unless value is its type's zero value.
+func setProp[T comparable](props gox.Props, key string, value T) {
+	var zero T
+	if value != zero {
+		props[key] = value
+	}
+}
@@ -0,0 +1,89 @@
+package goli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/germtb/gox"
+)
+
+func TestBoxProps_ToProps(t *testing.T) {
+	onKey := func(string) bool { return true }
+	props := BoxProps{
+		ID:        "list",
+		Direction: Row,
+		Justify:   JustifyCenter,
+		FlexWrap:  true,
+		Width:     80,
+		Padding:   Spacing{Left: 1},
+		Border:    BorderRounded,
+		Style:     Style{Bold: true},
+		OnKey:     onKey,
+		Active:    true,
+	}.ToProps()
+
+	want := gox.Props{
+		"id":        "list",
+		"direction": Row,
+		"justify":   JustifyCenter,
+		"flexWrap":  "wrap",
+		"width":     80,
+		"padding":   Spacing{Left: 1},
+		"border":    BorderRounded,
+		"style":     Style{Bold: true},
+		"active":    true,
+	}
+	if _, ok := props["onKey"].(func(string) bool); !ok {
+		t.Errorf("expected onKey to be set, got %v", props["onKey"])
+	}
+	delete(props, "onKey")
+	if !reflect.DeepEqual(props, want) {
+		t.Errorf("expected %v, got %v", want, props)
+	}
+
+	if props := (BoxProps{}).ToProps(); len(props) != 0 {
+		t.Errorf("expected zero-value fields to be omitted, got %v", props)
+	}
+}
+
+func TestTextProps_ToProps(t *testing.T) {
+	props := TextProps{Wrap: true, MaxLines: 2, TruncateChar: "…"}.ToProps()
+	want := gox.Props{"wrap": true, "maxLines": 2, "truncateChar": "…"}
+	if !reflect.DeepEqual(props, want) {
+		t.Errorf("expected %v, got %v", want, props)
+	}
+}
+
+func TestNewBox_MatchesPropsMap(t *testing.T) {
+	typed := NewBox(BoxProps{Direction: Row, Gap: 1, Border: BorderRounded, Padding: Spacing{Left: 1, Right: 1}, Width: 30},
+		NewText(TextProps{Style: Style{Color: ColorRed}}, "left"),
+		NewBox(BoxProps{Grow: 1, Justify: JustifyEnd, Direction: Row}, NewText(TextProps{}, "right")),
+	)
+	manual := gox.Element("box", gox.Props{
+		"direction":    "row",
+		"gap":          1,
+		"border":       "rounded",
+		"paddingLeft":  1,
+		"paddingRight": 1,
+		"width":        30,
+	},
+		gox.Element("text", gox.Props{"style": map[string]any{"color": "red"}}, gox.Text("left")),
+		gox.Element("box", gox.Props{"grow": 1, "justify": "end", "direction": "row"},
+			gox.Element("text", nil, gox.Text("right")),
+		),
+	)
+
+	render := func(node gox.VNode) string {
+		h := NewHeadless(Options{Width: 40, Height: 5})
+		h.Render(node)
+		return h.AnsiOutput()
+	}
+	got, want := render(typed), render(manual)
+	if !strings.Contains(want, "right") {
+		t.Fatalf("expected the manual tree to render, got:\n%s", want)
+	}
+	if got != want {
+		t.Errorf("expected identical output\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
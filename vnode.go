@@ -47,7 +47,25 @@ func CreateTextNode(text string) gox.VNode {
 }
 
 // Expand recursively expands functional components into their rendered output.
+// Components (gox.Component) are called with their props plus "children";
+// text nodes and intrinsic elements are kept and their children expanded.
+// Expand is idempotent: expanding an already-expanded tree yields an
+// equivalent tree. ComputeLayout calls it before laying out a tree, and it
+// is exported for tooling such as layout inspectors or alternate renderers.
 func Expand(v gox.VNode) gox.VNode {
+	ex := &expander{path: []memoSlot{slotOf(v, 0)}}
+	prev := Global.setExpander(ex)
+	defer Global.setExpander(prev)
+	return ex.expand(v)
+}
+
+// expander holds the state of one Expand call: the slots from the root to
+// the node being expanded, which MemoComponent uses to find its cache.
+type expander struct {
+	path []memoSlot
+}
+
+func (ex *expander) expand(v gox.VNode) gox.VNode {
 	// If it's a text node or intrinsic element, just expand children
 	if _, ok := TypeString(v); ok {
 		if len(v.Children) == 0 {
@@ -56,7 +74,9 @@ func Expand(v gox.VNode) gox.VNode {
 
 		expandedChildren := make([]gox.VNode, len(v.Children))
 		for i, child := range v.Children {
-			expandedChildren[i] = Expand(child)
+			ex.path = append(ex.path, slotOf(child, i))
+			expandedChildren[i] = ex.expand(child)
+			ex.path = ex.path[:len(ex.path)-1]
 		}
 
 		return gox.VNode{
@@ -76,8 +96,44 @@ func Expand(v gox.VNode) gox.VNode {
 		props["children"] = v.Children
 
 		result := comp(props)
-		return Expand(result)
+		return ex.expand(result)
 	}
 
 	return v
 }
+
+// ExpandAll expands the entire tree down to intrinsic elements and text
+// nodes, splicing fragment children into their parent so that only nodes
+// with a visual representation remain. A fragment at the root is kept.
+// Useful for snapshot-testing component output without a layout pass.
+func ExpandAll(v gox.VNode) gox.VNode {
+	v = Expand(v)
+	if len(v.Children) == 0 {
+		return v
+	}
+	return gox.VNode{
+		Type:     v.Type,
+		Props:    v.Props,
+		Children: flattenFragments(v.Children),
+	}
+}
+
+// flattenFragments replaces fragment nodes with their (flattened) children.
+func flattenFragments(children []gox.VNode) []gox.VNode {
+	result := make([]gox.VNode, 0, len(children))
+	for _, child := range children {
+		if t, _ := TypeString(child); t == "fragment" || t == gox.FragmentNodeType {
+			result = append(result, flattenFragments(child.Children)...)
+			continue
+		}
+		if len(child.Children) > 0 {
+			child = gox.VNode{
+				Type:     child.Type,
+				Props:    child.Props,
+				Children: flattenFragments(child.Children),
+			}
+		}
+		result = append(result, child)
+	}
+	return result
+}
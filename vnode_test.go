@@ -0,0 +1,64 @@
+package goli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/germtb/gox"
+)
+
+func labelComponent(props gox.Props) gox.VNode {
+	label, _ := props["label"].(string)
+	return gox.Element("box", gox.Props{"direction": "row"},
+		gox.Element("text", nil, gox.Text(label)),
+		gox.Fragment(props["children"].([]gox.VNode)...),
+	)
+}
+
+func TestExpand_Idempotent(t *testing.T) {
+	node := gox.Element(gox.Component(labelComponent), gox.Props{"label": "Name"},
+		gox.Element("text", nil, gox.Text("value")),
+	)
+
+	once := Expand(node)
+	twice := Expand(once)
+
+	if _, ok := TypeString(once); !ok {
+		t.Fatalf("expected intrinsic root after Expand, got %T", once.Type)
+	}
+	if !reflect.DeepEqual(once, twice) {
+		t.Errorf("expected Expand to be idempotent, got %+v then %+v", once, twice)
+	}
+}
+
+func TestExpandAll_FlattensToIntrinsics(t *testing.T) {
+	node := gox.Element(gox.Component(labelComponent), gox.Props{"label": "Name"},
+		gox.Element(gox.Component(labelComponent), gox.Props{"label": "Inner"}),
+	)
+
+	expanded := ExpandAll(node)
+
+	var walk func(v gox.VNode)
+	walk = func(v gox.VNode) {
+		typ, ok := TypeString(v)
+		if !ok {
+			t.Errorf("expected only intrinsic nodes, got %T", v.Type)
+		}
+		if typ == gox.FragmentNodeType {
+			t.Error("expected fragments to be flattened")
+		}
+		for _, child := range v.Children {
+			walk(child)
+		}
+	}
+	walk(expanded)
+
+	// box > [text "Name", box > [text "Inner"]]
+	if len(expanded.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(expanded.Children))
+	}
+	inner := expanded.Children[1]
+	if typ, _ := TypeString(inner); typ != "box" || len(inner.Children) != 1 {
+		t.Errorf("expected nested box with 1 child, got %v with %d", inner.Type, len(inner.Children))
+	}
+}